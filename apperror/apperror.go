@@ -0,0 +1,136 @@
+// Package apperror = error domain aplikasi. Tiap error punya Kind (nentuin status HTTP) dan Code
+// yang stabil buat dibaca mesin (client boleh switch pakai Code, Message bisa berubah kapan aja).
+//
+// Service balikin error dari sini, controller cukup ctx.Error(err), sisanya diurus
+// middleware ErrorHandler. Error lain (bukan *Error) dianggap internal -> 500.
+package apperror
+
+import (
+	"errors"
+	"time"
+)
+
+type Kind string
+
+const (
+	KindBadRequest      Kind = "bad_request"           // 400: request gak kebaca (JSON rusak, ID bukan UUID)
+	KindUnauthorized    Kind = "unauthorized"          // 401
+	KindForbidden       Kind = "forbidden"             // 403
+	KindNotFound        Kind = "not_found"             // 404
+	KindConflict        Kind = "conflict"              // 409
+	KindPrecondition    Kind = "precondition_failed"   // 412
+	KindPayloadTooLarge Kind = "payload_too_large"     // 413
+	KindValidation      Kind = "validation"            // 422: request kebaca tapi melanggar aturan bisnis
+	KindPreconditionReq Kind = "precondition_required" // 428
+	KindTooManyRequests Kind = "too_many_requests"     // 429
+	KindInternal        Kind = "internal"              // 500
+)
+
+type Error struct {
+	Kind       Kind
+	Code       string        // stabil, snake_case, misal "transaction_not_found"
+	Message    string        // buat manusia, masuk ke BaseResponse.Message
+	Details    interface{}   // opsional, misal daftar field yang gak valid
+	RetryAfter time.Duration // opsional, jadi header Retry-After (429)
+	Err        error         // penyebab asli, cuma buat log / errors.Is, gak dikirim ke client
+}
+
+// Sentinel per Kind. errors.Is(err, apperror.ErrNotFound) true buat semua error ber-Kind NotFound,
+// termasuk ErrRecordNotFound GORM yang udah diterjemahin di repository.
+var (
+	ErrBadRequest      = New(KindBadRequest, string(KindBadRequest), "Bad request")
+	ErrUnauthorized    = New(KindUnauthorized, string(KindUnauthorized), "Unauthorized")
+	ErrForbidden       = New(KindForbidden, string(KindForbidden), "Forbidden")
+	ErrNotFound        = New(KindNotFound, string(KindNotFound), "Data not found")
+	ErrConflict        = New(KindConflict, string(KindConflict), "Data conflict")
+	ErrPrecondition    = New(KindPrecondition, string(KindPrecondition), "Precondition failed")
+	ErrValidation      = New(KindValidation, string(KindValidation), "Validation failed")
+	ErrTooManyRequests = New(KindTooManyRequests, string(KindTooManyRequests), "Too many requests")
+	ErrInternal        = New(KindInternal, "internal_error", "Internal server error")
+)
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func BadRequest(code, message string) *Error      { return New(KindBadRequest, code, message) }
+func Unauthorized(code, message string) *Error    { return New(KindUnauthorized, code, message) }
+func Forbidden(code, message string) *Error       { return New(KindForbidden, code, message) }
+func NotFound(code, message string) *Error        { return New(KindNotFound, code, message) }
+func Conflict(code, message string) *Error        { return New(KindConflict, code, message) }
+func Validation(code, message string) *Error      { return New(KindValidation, code, message) }
+func TooManyRequests(code, message string) *Error { return New(KindTooManyRequests, code, message) }
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is: sentinel per Kind (ErrNotFound dll) cocok sama semua error ber-Kind sama,
+// error lain cocok kalau Code-nya sama.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	if t.Code == string(t.Kind) {
+		return e.Kind == t.Kind
+	}
+	return e.Kind == t.Kind && e.Code == t.Code
+}
+
+// Wrap = salinan error dengan penyebab asli (error sentinel gak diubah).
+func (e *Error) Wrap(cause error) *Error {
+	c := *e
+	c.Err = cause
+	return &c
+}
+
+func (e *Error) WithDetails(details interface{}) *Error {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+func (e *Error) WithMessage(message string) *Error {
+	c := *e
+	c.Message = message
+	return &c
+}
+
+func (e *Error) WithRetryAfter(d time.Duration) *Error {
+	c := *e
+	c.RetryAfter = d
+	return &c
+}
+
+// From ambil *Error dari rantai err. Error biasa dibungkus jadi ErrInternal.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return ErrInternal.Wrap(err)
+}
+
+// Replace: kalau err ber-Kind sama dengan target (misal NotFound generik dari repository),
+// ganti jadi target yang lebih spesifik. Selain itu err dibalikin apa adanya.
+//
+//	wallet, err := s.walletRepo.FindByID(ctx, id)
+//	if err != nil {
+//		return apperror.Replace(err, ErrWalletNotFound)
+//	}
+func Replace(err error, target *Error) error {
+	if err == nil {
+		return nil
+	}
+	var appErr *Error
+	if errors.As(err, &appErr) && appErr.Kind == target.Kind {
+		return target.Wrap(err)
+	}
+	return err
+}
@@ -0,0 +1,492 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory" // mode demo: semua data di RAM, hilang pas restart
+)
+
+// Config = semua setting aplikasi. Urutan sumber (yang belakang menimpa yang depan):
+// default per environment -> file JSON (CONFIG_FILE, atau config.<APP_ENV>.json kalau ada) -> env var.
+// File .env juga dibaca kalau ada, isinya dianggap env var.
+type Config struct {
+	Env         string            `json:"env"`
+	Server      ServerConfig      `json:"server"`
+	Database    DatabaseConfig    `json:"database"`
+	Auth        AuthConfig        `json:"auth"`
+	CORS        CORSConfig        `json:"cors"`
+	Redis       RedisConfig       `json:"redis"`
+	Idempotency IdempotencyConfig `json:"idempotency"`
+	Log         LogConfig         `json:"log"`
+	Metrics     MetricsConfig     `json:"metrics"`
+	Tracing     TracingConfig     `json:"tracing"`
+}
+
+type ServerConfig struct {
+	Addr            string   `json:"addr"`             // APP_PORT, misal ":8080"
+	ShutdownTimeout Duration `json:"shutdown_timeout"` // batas nunggu request yang lagi jalan pas SIGTERM
+	// TrustedProxies = IP/CIDR reverse proxy yang X-Forwarded-For-nya dipercaya buat nentuin IP client
+	// (rate limit per IP). Kosong = header itu diabaikan, IP client = IP koneksi langsung.
+	TrustedProxies []string `json:"trusted_proxies"`
+}
+
+type DatabaseConfig struct {
+	Driver          string   `json:"driver"` // postgres | sqlite | memory
+	Path            string   `json:"path"`   // file database, cuma dipakai driver sqlite (":memory:" = gak disimpan)
+	Host            string   `json:"host"`
+	Port            string   `json:"port"`
+	User            string   `json:"user"`
+	Password        string   `json:"password"`
+	Name            string   `json:"name"`
+	SSLMode         string   `json:"ssl_mode"`
+	TimeZone        string   `json:"time_zone"`
+	MaxIdleConns    int      `json:"max_idle_conns"`
+	MaxOpenConns    int      `json:"max_open_conns"`
+	ConnMaxLifetime Duration `json:"conn_max_lifetime"`
+	MigrateOnStart  bool     `json:"migrate_on_start"` // true = migration pending langsung di-apply pas server start
+}
+
+// DSN buat driver postgres.
+func (d DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
+		d.Host, d.User, d.Password, d.Name, d.Port, d.SSLMode, d.TimeZone)
+}
+
+// SQLiteDSN: foreign key dinyalain (default SQLite mati), WAL biar baca gak nunggu tulis,
+// busy_timeout biar nulis barengan antri dulu, dan BEGIN IMMEDIATE biar transaction langsung
+// pegang write lock (pengganti SELECT ... FOR UPDATE yang gak ada di SQLite).
+func (d DatabaseConfig) SQLiteDSN() string {
+	return d.Path + "?_foreign_keys=on&_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate"
+}
+
+type AuthConfig struct {
+	JWTSecret      string   `json:"jwt_secret"`
+	AccessTokenTTL Duration `json:"access_token_ttl"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins   []string `json:"allowed_origins"` // kosong = CORS mati, "*" = semua origin
+	AllowCredentials bool     `json:"allow_credentials"`
+	MaxAge           Duration `json:"max_age"`
+}
+
+type RedisConfig struct {
+	Addr     string `json:"addr"` // kosong = rate limit pakai memory
+	Password string `json:"password"`
+}
+
+type LogConfig struct {
+	Level              string   `json:"level"`                // debug | info | warn | error
+	Format             string   `json:"format"`               // json | text
+	SQLLevel           string   `json:"sql_level"`            // silent | error | warn | info (info = semua query)
+	SlowQueryThreshold Duration `json:"slow_query_threshold"` // query lebih lama dari ini di-log warn
+}
+
+type MetricsConfig struct {
+	Enabled           bool     `json:"enabled"`
+	Addr              string   `json:"addr"`               // kosong = /metrics ikut server utama, diisi (misal ":9090") = listener terpisah
+	Token             string   `json:"token"`              // diisi = scrape wajib pakai header "Authorization: Bearer <token>"
+	ReconcileInterval Duration `json:"reconcile_interval"` // interval cek saldo wallet vs total transaksi, 0 = mati
+}
+
+type TracingConfig struct {
+	Exporter     string  `json:"exporter"`      // none | stdout | otlp
+	OTLPEndpoint string  `json:"otlp_endpoint"` // URL collector OTLP/HTTP, misal "http://localhost:4318"
+	SampleRatio  float64 `json:"sample_ratio"`  // 0..1, porsi request yang di-trace
+	ServiceName  string  `json:"service_name"`
+}
+
+type IdempotencyConfig struct {
+	TTL Duration `json:"ttl"`
+	// Lease = lama request pertama dianggap masih jalan. Kalau prosesnya mati (crash) sebelum selesai,
+	// retry dengan key yang sama baru bisa diproses setelah lease habis (sebelumnya 409)
+	Lease Duration `json:"lease"`
+}
+
+// Duration di file config ditulis kayak time.ParseDuration ("24h", "15m").
+type Duration time.Duration
+
+func (d Duration) Std() time.Duration { return time.Duration(d) }
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var raw string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return fmt.Errorf("durasi harus string, misal \"24h\": %w", err)
+	}
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Load baca config dari semua sumber terus divalidasi. Error-nya udah berisi semua field yang salah sekaligus.
+func Load() (*Config, error) {
+	// .env opsional, di production biasanya env var langsung dari orchestrator
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("gagal baca .env: %w", err)
+	}
+
+	env := strings.ToLower(strings.TrimSpace(os.Getenv("APP_ENV")))
+	if env == "" {
+		env = EnvDevelopment
+	}
+	cfg := defaults(env)
+
+	if err := cfg.loadFile(); err != nil {
+		return nil, err
+	}
+
+	var errs []string
+	cfg.applyEnv(&errs)
+	errs = append(errs, cfg.validate()...)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("config tidak valid:\n  - %s", strings.Join(errs, "\n  - "))
+	}
+	return cfg, nil
+}
+
+func (c *Config) IsProduction() bool {
+	return c.Env == EnvProduction
+}
+
+func defaults(env string) *Config {
+	cfg := &Config{
+		Env:    env,
+		Server: ServerConfig{Addr: ":8080", ShutdownTimeout: Duration(15 * time.Second)},
+		Database: DatabaseConfig{
+			Driver:          DriverPostgres,
+			Path:            "cashflow.db",
+			Host:            "localhost",
+			Port:            "5432",
+			SSLMode:         "disable",
+			TimeZone:        "Asia/Jakarta",
+			MaxIdleConns:    10,
+			MaxOpenConns:    100,
+			ConnMaxLifetime: Duration(time.Hour),
+		},
+		Auth:        AuthConfig{AccessTokenTTL: Duration(24 * time.Hour)},
+		CORS:        CORSConfig{MaxAge: Duration(12 * time.Hour)},
+		Idempotency: IdempotencyConfig{TTL: Duration(24 * time.Hour), Lease: Duration(5 * time.Minute)},
+		Log: LogConfig{
+			Level:              "info",
+			Format:             "json",
+			SQLLevel:           "warn",
+			SlowQueryThreshold: Duration(200 * time.Millisecond),
+		},
+		Metrics: MetricsConfig{Enabled: true, ReconcileInterval: Duration(time.Hour)},
+		Tracing: TracingConfig{Exporter: "none", SampleRatio: 1, ServiceName: "cashflow-api"},
+	}
+
+	if env == EnvDevelopment {
+		// Dev: frontend lokal boleh akses, DB boleh auto-migrate
+		cfg.CORS.AllowedOrigins = []string{"http://localhost:3000", "http://localhost:5173"}
+		cfg.Database.MigrateOnStart = true
+		cfg.Log.Level = "debug"
+	} else {
+		cfg.Database.SSLMode = "require"
+	}
+	return cfg
+}
+
+// loadFile: CONFIG_FILE wajib ada kalau diisi, config.<env>.json cuma dibaca kalau ada.
+func (c *Config) loadFile() error {
+	path := os.Getenv("CONFIG_FILE")
+	required := path != ""
+	if path == "" {
+		path = fmt.Sprintf("config.%s.json", c.Env)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !required && errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("gagal baca config file %s: %w", path, err)
+	}
+
+	env := c.Env
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields() // typo nama field langsung ketahuan
+	if err := decoder.Decode(c); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	// Environment tetap dari APP_ENV, file gak boleh ganti diam-diam
+	c.Env = env
+	return nil
+}
+
+// applyEnv timpa config pakai env var (nama env lama tetap dipakai biar .env yang udah ada jalan).
+func (c *Config) applyEnv(errs *[]string) {
+	setString(&c.Server.Addr, "APP_PORT")
+	setDuration(&c.Server.ShutdownTimeout, "SHUTDOWN_TIMEOUT", errs)
+	if raw, ok := os.LookupEnv("TRUSTED_PROXIES"); ok {
+		c.Server.TrustedProxies = splitList(raw)
+	}
+
+	setString(&c.Database.Driver, "DB_DRIVER")
+	setString(&c.Database.Path, "DB_PATH")
+	setString(&c.Database.Host, "DB_HOST")
+	setString(&c.Database.Port, "DB_PORT")
+	setString(&c.Database.User, "DB_USER")
+	setString(&c.Database.Password, "DB_PASSWORD")
+	setString(&c.Database.Name, "DB_NAME")
+	setString(&c.Database.SSLMode, "DB_SSLMODE")
+	setString(&c.Database.TimeZone, "DB_TIMEZONE")
+	setInt(&c.Database.MaxIdleConns, "DB_MAX_IDLE_CONNS", errs)
+	setInt(&c.Database.MaxOpenConns, "DB_MAX_OPEN_CONNS", errs)
+	setDuration(&c.Database.ConnMaxLifetime, "DB_CONN_MAX_LIFETIME", errs)
+	setBool(&c.Database.MigrateOnStart, "MIGRATE_ON_START", errs)
+
+	setString(&c.Auth.JWTSecret, "JWT_SECRET")
+	setDuration(&c.Auth.AccessTokenTTL, "JWT_ACCESS_TOKEN_TTL", errs)
+
+	if raw, ok := os.LookupEnv("CORS_ALLOWED_ORIGINS"); ok {
+		c.CORS.AllowedOrigins = splitList(raw)
+	}
+	setBool(&c.CORS.AllowCredentials, "CORS_ALLOW_CREDENTIALS", errs)
+
+	setString(&c.Redis.Addr, "REDIS_ADDR")
+	setString(&c.Redis.Password, "REDIS_PASSWORD")
+
+	setDuration(&c.Idempotency.TTL, "IDEMPOTENCY_TTL", errs)
+	setDuration(&c.Idempotency.Lease, "IDEMPOTENCY_LEASE", errs)
+
+	setString(&c.Log.Level, "LOG_LEVEL")
+	setString(&c.Log.Format, "LOG_FORMAT")
+	setString(&c.Log.SQLLevel, "DB_LOG_LEVEL")
+	setDuration(&c.Log.SlowQueryThreshold, "DB_SLOW_QUERY_THRESHOLD", errs)
+
+	setBool(&c.Metrics.Enabled, "METRICS_ENABLED", errs)
+	setString(&c.Metrics.Addr, "METRICS_ADDR")
+	setString(&c.Metrics.Token, "METRICS_TOKEN")
+	setDuration(&c.Metrics.ReconcileInterval, "BALANCE_RECONCILE_INTERVAL", errs)
+
+	// Nama env ngikutin konvensi OpenTelemetry biar gampang dipasang di collector/orchestrator
+	setString(&c.Tracing.Exporter, "OTEL_TRACES_EXPORTER")
+	setString(&c.Tracing.OTLPEndpoint, "OTEL_EXPORTER_OTLP_ENDPOINT")
+	setFloat(&c.Tracing.SampleRatio, "OTEL_TRACES_SAMPLER_ARG", errs)
+	setString(&c.Tracing.ServiceName, "OTEL_SERVICE_NAME")
+
+	// APP_PORT lama kadang cuma angka ("8080")
+	if c.Server.Addr != "" && !strings.Contains(c.Server.Addr, ":") {
+		c.Server.Addr = ":" + c.Server.Addr
+	}
+	if c.Metrics.Addr != "" && !strings.Contains(c.Metrics.Addr, ":") {
+		c.Metrics.Addr = ":" + c.Metrics.Addr
+	}
+}
+
+func (c *Config) validate() []string {
+	var errs []string
+
+	switch c.Env {
+	case EnvDevelopment, EnvStaging, EnvProduction:
+	default:
+		errs = append(errs, fmt.Sprintf("APP_ENV %q gak dikenal (development|staging|production)", c.Env))
+	}
+
+	if c.Server.Addr == "" {
+		errs = append(errs, "server.addr (APP_PORT) wajib diisi")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, "server.shutdown_timeout (SHUTDOWN_TIMEOUT) harus > 0")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			errs = append(errs, fmt.Sprintf("server.trusted_proxies (TRUSTED_PROXIES) %q bukan IP/CIDR", proxy))
+		}
+	}
+
+	switch c.Database.Driver {
+	case DriverPostgres:
+		if c.Database.Host == "" {
+			errs = append(errs, "database.host (DB_HOST) wajib diisi")
+		}
+		if c.Database.User == "" {
+			errs = append(errs, "database.user (DB_USER) wajib diisi")
+		}
+		if c.Database.Name == "" {
+			errs = append(errs, "database.name (DB_NAME) wajib diisi")
+		}
+		switch c.Database.SSLMode {
+		case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+		default:
+			errs = append(errs, fmt.Sprintf("database.ssl_mode (DB_SSLMODE) %q gak valid", c.Database.SSLMode))
+		}
+	case DriverSQLite:
+		if c.Database.Path == "" {
+			errs = append(errs, "database.path (DB_PATH) wajib diisi kalau driver sqlite")
+		}
+	case DriverMemory:
+		// gak butuh setting koneksi apa-apa
+	default:
+		errs = append(errs, fmt.Sprintf("database.driver (DB_DRIVER) %q gak dikenal (postgres|sqlite|memory)", c.Database.Driver))
+	}
+	if _, err := time.LoadLocation(c.Database.TimeZone); err != nil {
+		errs = append(errs, fmt.Sprintf("database.time_zone (DB_TIMEZONE) %q gak valid", c.Database.TimeZone))
+	}
+	if c.Database.MaxOpenConns < 1 {
+		errs = append(errs, "database.max_open_conns (DB_MAX_OPEN_CONNS) minimal 1")
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, "database.max_idle_conns (DB_MAX_IDLE_CONNS) harus 0..max_open_conns")
+	}
+
+	if c.Auth.JWTSecret == "" {
+		errs = append(errs, "auth.jwt_secret (JWT_SECRET) wajib diisi")
+	} else if c.IsProduction() && len(c.Auth.JWTSecret) < 32 {
+		errs = append(errs, "auth.jwt_secret (JWT_SECRET) minimal 32 karakter di production")
+	}
+	if c.Auth.AccessTokenTTL <= 0 {
+		errs = append(errs, "auth.access_token_ttl (JWT_ACCESS_TOKEN_TTL) harus > 0")
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" && c.CORS.AllowCredentials {
+			errs = append(errs, "cors: allowed_origins \"*\" gak boleh dipakai bareng allow_credentials")
+		}
+	}
+
+	if c.Idempotency.TTL <= 0 {
+		errs = append(errs, "idempotency.ttl (IDEMPOTENCY_TTL) harus > 0")
+	}
+	if c.Idempotency.Lease <= 0 || c.Idempotency.Lease > c.Idempotency.TTL {
+		errs = append(errs, "idempotency.lease (IDEMPOTENCY_LEASE) harus > 0 dan <= idempotency.ttl")
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Sprintf("log.level (LOG_LEVEL) %q gak valid (debug|info|warn|error)", c.Log.Level))
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "json", "text":
+	default:
+		errs = append(errs, fmt.Sprintf("log.format (LOG_FORMAT) %q gak valid (json|text)", c.Log.Format))
+	}
+	switch strings.ToLower(c.Log.SQLLevel) {
+	case "silent", "error", "warn", "info":
+	default:
+		errs = append(errs, fmt.Sprintf("log.sql_level (DB_LOG_LEVEL) %q gak valid (silent|error|warn|info)", c.Log.SQLLevel))
+	}
+
+	if c.Metrics.Enabled {
+		if c.Metrics.Addr != "" && c.Metrics.Addr == c.Server.Addr {
+			errs = append(errs, "metrics.addr (METRICS_ADDR) gak boleh sama dengan server.addr")
+		}
+		// Di production /metrics jangan kebuka bebas di port publik
+		if c.IsProduction() && c.Metrics.Addr == "" && c.Metrics.Token == "" {
+			errs = append(errs, "metrics: di production isi metrics.token (METRICS_TOKEN) atau pisah ke metrics.addr (METRICS_ADDR)")
+		}
+	}
+	if c.Metrics.ReconcileInterval < 0 {
+		errs = append(errs, "metrics.reconcile_interval (BALANCE_RECONCILE_INTERVAL) gak boleh negatif")
+	}
+
+	switch strings.ToLower(c.Tracing.Exporter) {
+	case "none", "stdout", "otlp":
+	default:
+		errs = append(errs, fmt.Sprintf("tracing.exporter (OTEL_TRACES_EXPORTER) %q gak valid (none|stdout|otlp)", c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, "tracing.sample_ratio (OTEL_TRACES_SAMPLER_ARG) harus 0..1")
+	}
+	if c.Tracing.ServiceName == "" {
+		errs = append(errs, "tracing.service_name (OTEL_SERVICE_NAME) wajib diisi")
+	}
+	return errs
+}
+
+func setString(dst *string, key string) {
+	if v, ok := os.LookupEnv(key); ok {
+		*dst = strings.TrimSpace(v)
+	}
+}
+
+func setInt(dst *int, key string, errs *[]string) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		*errs = append(*errs, fmt.Sprintf("%s %q bukan angka", key, v))
+		return
+	}
+	*dst = n
+}
+
+func setBool(dst *bool, key string, errs *[]string) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(v))
+	if err != nil {
+		*errs = append(*errs, fmt.Sprintf("%s %q harus true/false", key, v))
+		return
+	}
+	*dst = b
+}
+
+func setFloat(dst *float64, key string, errs *[]string) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		*errs = append(*errs, fmt.Sprintf("%s %q bukan angka", key, v))
+		return
+	}
+	*dst = f
+}
+
+func setDuration(dst *Duration, key string, errs *[]string) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(v))
+	if err != nil {
+		*errs = append(*errs, fmt.Sprintf("%s %q bukan durasi valid (contoh: 24h, 15m)", key, v))
+		return
+	}
+	*dst = Duration(d)
+}
+
+func splitList(raw string) []string {
+	var list []string
+	for _, s := range strings.Split(raw, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
@@ -1,51 +1,46 @@
 package config
 
 import (
-	"cashflow_gin/models"
-	"fmt"
-	"os"
+	"cashflow_gin/logging"
+	"cashflow_gin/repository"
 
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
-func NewDatabaseConnection() (*gorm.DB, error) {
-	// Ambil data dari .env
-	host := os.Getenv("DB_HOST")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbName := os.Getenv("DB_NAME")
-	port := os.Getenv("DB_PORT")
-
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Jakarta",
-		host, user, password, dbName, port)
+func NewDatabaseConnection(cfg DatabaseConfig, logCfg LogConfig) (*gorm.DB, error) {
+	dialector := postgres.Open(cfg.DSN())
+	if cfg.Driver == DriverSQLite {
+		dialector = sqlite.Open(cfg.SQLiteDSN())
+	}
 
-	con, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	con, err := gorm.Open(dialector, &gorm.Config{
+		Logger:         logging.NewGormLogger(logCfg.SQLLevel, logCfg.SlowQueryThreshold.Std()),
+		TranslateError: true, // unique/FK violation jadi gorm.ErrDuplicatedKey dkk, diterusin ke apperror
+	})
 	if err != nil {
 		return nil, err
 	}
+	if err := con.Use(repository.ErrorTranslator{}); err != nil {
+		return nil, err
+	}
 
 	sqlDB, err := con.DB()
 	if err != nil {
 		return nil, err
 	}
 
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-
-	// Automigrate (opsional, tapi buat dev enak)
-	err = con.AutoMigrate(
-		&models.User{},
-		&models.Category{}, // Jangan lupa Category juga harus ada
-		&models.Group{},
-		&models.GroupMember{},
-		&models.Wallet{},
-		&models.Transaction{},
-	)
-	if err != nil {
-		fmt.Println("Gagal AutoMigrate:", err)
-		return nil, err
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime.Std())
+	if cfg.Driver == DriverSQLite && cfg.Path == ":memory:" {
+		// Tiap koneksi :memory: = database baru yang kosong, jadi harus satu koneksi aja
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
 	}
 
+	// Skema gak di-AutoMigrate lagi, pakai migration berversi (package migrations / `cashflow migrate up`)
 	return con, nil
 }
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type APIKeyController struct {
+	service services.APIKeyService
+}
+
+func NewAPIKeyController(s services.APIKeyService) *APIKeyController {
+	return &APIKeyController{service: s}
+}
+
+// CreateAPIKey godoc
+// @Summary      Create API Key
+// @Description  Membuat API key baru untuk script/integrasi. Key hanya ditampilkan sekali di response ini.
+// @Tags         API Keys
+// @Accept       json
+// @Produce      json
+// @Param        request body request.CreateAPIKeyRequest true "request body"
+// @Success      201 {object} response.BaseResponse{data=response.CreatedAPIKeyResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /users/me/api-keys [post]
+func (c *APIKeyController) CreateAPIKey(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	var input request.CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	key, err := c.service.Create(ctx.Request.Context(), userID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.BaseResponse{
+		Status:  true,
+		Message: "API key created, copy it now because it will not be shown again",
+		Data:    key,
+	})
+}
+
+// GetMyAPIKeys godoc
+// @Summary      Get My API Keys
+// @Description  Daftar API key milik user (tanpa secret).
+// @Tags         API Keys
+// @Produce      json
+// @Success      200 {object} response.BaseResponse{data=[]response.APIKeyResponse}
+// @Failure      401 {object} response.BaseResponse
+// @Failure      500 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /users/me/api-keys [get]
+func (c *APIKeyController) GetMyAPIKeys(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	keys, err := c.service.GetMine(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "API keys retrieved successfully",
+		Data:    keys,
+	})
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revoke API Key
+// @Description  Menghapus (revoke) API key, request yang pakai key ini langsung ditolak.
+// @Tags         API Keys
+// @Produce      json
+// @Param        id path string true "API Key ID"
+// @Success      200 {object} response.BaseResponse
+// @Failure      400 {object} response.BaseResponse
+// @Failure      404 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /users/me/api-keys/{id} [delete]
+func (c *APIKeyController) RevokeAPIKey(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	keyID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(invalidID("Invalid API key ID", err))
+		return
+	}
+
+	if err := c.service.Revoke(ctx.Request.Context(), userID, keyID); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "API key revoked successfully",
+	})
+}
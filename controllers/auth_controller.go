@@ -1,12 +1,14 @@
 package controllers
 
 import (
+	"cashflow_gin/apperror"
 	"cashflow_gin/dto/request"
 	"cashflow_gin/dto/response"
 	"cashflow_gin/services"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AuthController struct {
@@ -25,6 +27,7 @@ func NewAuthController(s services.AuthService) *AuthController {
 // @Produce      json
 // @Param        request body request.CreateUserRequest true "request body"
 // @Success      201 {object} response.BaseResponse{data=response.UserResponse}
+// @Failure      429 {object} response.BaseResponse
 // @Failure      500 {object} response.BaseResponse
 // @Router       /auth/register [post]
 func (c *AuthController) Register(ctx *gin.Context) {
@@ -32,25 +35,14 @@ func (c *AuthController) Register(ctx *gin.Context) {
 
 	// 1. Validasi Input JSON
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(
-			http.StatusBadRequest,
-			response.BaseResponse{
-				Status:  false,
-				Message: "Input Tidak Valid",
-				Errors:  err.Error(),
-			},
-		)
+		ctx.Error(bindError(ctx, err))
 		return
 	}
 
 	// 2. Panggil Service
-	user, err := c.service.Register(input)
+	user, err := c.service.Register(ctx.Request.Context(), input)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.BaseResponse{
-			Status:  false,
-			Message: "Internal Server Error",
-			Errors:  err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
@@ -64,43 +56,173 @@ func (c *AuthController) Register(ctx *gin.Context) {
 
 // Login godoc
 // @Summary      User Login
-// @Description  Autentikasi user dan mendapatkan token JWT.
+// @Description  Autentikasi user dan mendapatkan token JWT. Kalau 2FA aktif, yang dikirim challenge_token untuk dilanjut ke /auth/login/2fa.
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
 // @Param        request body request.LoginRequest true "request body"
-// @Success      200 {object} response.BaseResponse{data=string}
+// @Success      200 {object} response.BaseResponse{data=response.LoginResponse}
 // @Failure      400 {object} response.BaseResponse
+// @Failure      429 {object} response.BaseResponse
 // @Failure      500 {object} response.BaseResponse
 // @Router       /auth/login [post]
 func (c *AuthController) Login(ctx *gin.Context) {
 	var input request.LoginRequest
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Input tidak Valid",
-			Errors:  err.Error(),
-		})
+		ctx.Error(bindError(ctx, err))
 		return
 	}
 
-	token, err := c.service.Login(&input)
+	result, err := c.service.Login(ctx.Request.Context(), &input)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.BaseResponse{
-			Status:  false,
-			Message: "Error",
-			Errors:  err.Error(),
-		})
+		ctx.Error(err)
+		return
+	}
+
+	message := "Login Success"
+	if result.TwoFactorRequired {
+		message = "Two-factor authentication required"
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: message,
+		Data:    result,
+	})
+}
+
+// LoginTwoFactor godoc
+// @Summary      Login Step 2 (2FA)
+// @Description  Menukar challenge token + kode TOTP (atau recovery code) dengan token JWT.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.LoginTwoFactorRequest true "request body"
+// @Success      200 {object} response.BaseResponse{data=response.LoginResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Failure      429 {object} response.BaseResponse
+// @Router       /auth/login/2fa [post]
+func (c *AuthController) LoginTwoFactor(ctx *gin.Context) {
+	var input request.LoginTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	result, err := c.service.LoginTwoFactor(ctx.Request.Context(), input)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, response.BaseResponse{
 		Status:  true,
 		Message: "Login Success",
-		Data: struct {
-			Token string `json:"token"`
-		}{
-			Token: token,
-		},
+		Data:    result,
+	})
+}
+
+// EnrollTwoFactor godoc
+// @Summary      Enroll 2FA
+// @Description  Generate secret TOTP & provisioning URI (buat QR code). 2FA belum aktif sampai diverifikasi lewat endpoint enable.
+// @Tags         Auth
+// @Produce      json
+// @Success      200 {object} response.BaseResponse{data=response.TwoFactorEnrollResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /auth/2fa/enroll [post]
+func (c *AuthController) EnrollTwoFactor(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	result, err := c.service.EnrollTwoFactor(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Scan the provisioning URI, then verify a code to enable 2FA",
+		Data:    result,
+	})
+}
+
+// EnableTwoFactor godoc
+// @Summary      Enable 2FA
+// @Description  Verifikasi kode TOTP pertama lalu aktifkan 2FA. Recovery code cuma ditampilkan sekali di response ini.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.TwoFactorCodeRequest true "request body"
+// @Success      200 {object} response.BaseResponse{data=response.RecoveryCodesResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /auth/2fa/enable [post]
+func (c *AuthController) EnableTwoFactor(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	var input request.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	result, err := c.service.EnableTwoFactor(ctx.Request.Context(), userID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "2FA enabled, store these recovery codes safely",
+		Data:    result,
+	})
+}
+
+// DisableTwoFactor godoc
+// @Summary      Disable 2FA
+// @Description  Nonaktifkan 2FA dan hapus semua recovery code. Wajib konfirmasi password.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.DisableTwoFactorRequest true "request body"
+// @Success      200 {object} response.BaseResponse
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /auth/2fa/disable [post]
+func (c *AuthController) DisableTwoFactor(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	var input request.DisableTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	if err := c.service.DisableTwoFactor(ctx.Request.Context(), userID, input); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "2FA disabled successfully",
 	})
 }
@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"cashflow_gin/apperror"
 	"cashflow_gin/dto/request"
 	"cashflow_gin/dto/response"
+	"cashflow_gin/middlewares"
 	"cashflow_gin/services"
 	"net/http"
 
@@ -31,17 +33,9 @@ func NewCategoryController(s services.CategoryService) *CategoryController {
 // @Security 	 BearerAuth
 // @Router       /categories/default [post]
 func (c *CategoryController) CreateDefaultCategories(ctx *gin.Context) {
-	category, err := c.services.CreateDefaultCategories()
+	category, err := c.services.CreateDefaultCategories(ctx.Request.Context())
 	if err != nil {
-		ctx.JSON(
-			http.StatusInternalServerError,
-			response.BaseResponse{
-				Status:  false,
-				Message: "Failed to create default categories",
-				Errors:  err,
-				Data:    nil,
-			},
-		)
+		ctx.Error(err)
 		return
 	}
 
@@ -69,29 +63,13 @@ func (c *CategoryController) CreateDefaultCategories(ctx *gin.Context) {
 func (c *CategoryController) GetAllCategories(ctx *gin.Context) {
 	roleClaim, exists := ctx.Get("user_role")
 	if !exists {
-		ctx.JSON(
-			http.StatusUnauthorized,
-			response.BaseResponse{
-				Status:  false,
-				Message: "Unauthorized access",
-				Errors:  "Insufficient permissions",
-				Data:    nil,
-			},
-		)
+		ctx.Error(apperror.ErrUnauthorized)
 		return
 	}
 
-	cat, err := c.services.GetAllCategories(roleClaim.(float64))
+	cat, err := c.services.GetAllCategories(ctx.Request.Context(), roleClaim.(float64))
 	if err != nil {
-		ctx.JSON(
-			http.StatusInternalServerError,
-			response.BaseResponse{
-				Status:  false,
-				Message: "Failed to retrieve categories",
-				Errors:  err.Error(),
-				Data:    nil,
-			},
-		)
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(
@@ -120,39 +98,25 @@ func (c *CategoryController) GetAllCategories(ctx *gin.Context) {
 func (c *CategoryController) CreateMy(ctx *gin.Context) {
 	userIDStr, exists := ctx.Get("user_id")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, response.BaseResponse{
-			Status:  false,
-			Message: "Unauthorized",
-		})
+		ctx.Error(apperror.ErrUnauthorized)
 		return
 	}
 
 	userID, err := uuid.Parse(userIDStr.(string))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid user ID",
-		})
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
 		return
 	}
 
 	var input request.CreateCategoryRequest
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid input",
-			Errors:  err.Error(),
-		})
+		ctx.Error(bindError(ctx, err))
 		return
 	}
 
-	category, err := c.services.CreateMy(userID, input)
+	category, err := c.services.CreateMy(ctx.Request.Context(), userID, input)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.BaseResponse{
-			Status:  false,
-			Message: "Failed to create category",
-			Errors:  err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
@@ -176,29 +140,19 @@ func (c *CategoryController) CreateMy(ctx *gin.Context) {
 func (c *CategoryController) GetMine(ctx *gin.Context) {
 	userIDStr, exists := ctx.Get("user_id")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, response.BaseResponse{
-			Status:  false,
-			Message: "Unauthorized",
-		})
+		ctx.Error(apperror.ErrUnauthorized)
 		return
 	}
 
 	userID, err := uuid.Parse(userIDStr.(string))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid user ID",
-		})
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
 		return
 	}
 
-	categories, err := c.services.GetMine(userID)
+	categories, err := c.services.GetMine(ctx.Request.Context(), userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.BaseResponse{
-			Status:  false,
-			Message: "Failed to retrieve categories",
-			Errors:  err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
@@ -216,61 +170,49 @@ func (c *CategoryController) GetMine(ctx *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "Category ID"
+// @Param        If-Match header string true "ETag / version kategori"
 // @Param        request body request.CreateCategoryRequest true "Update Category Request"
 // @Success      200 {object} response.BaseResponse
 // @Failure      400 {object} response.BaseResponse
 // @Failure      404 {object} response.BaseResponse
+// @Failure      409 {object} response.BaseResponse
+// @Failure      412 {object} response.BaseResponse
+// @Failure      428 {object} response.BaseResponse
 // @Security 	 BearerAuth
 // @Router       /categories/{id} [put]
 func (c *CategoryController) UpdateById(ctx *gin.Context) {
 	userIDStr, exists := ctx.Get("user_id")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, response.BaseResponse{
-			Status:  false,
-			Message: "Unauthorized",
-		})
+		ctx.Error(apperror.ErrUnauthorized)
 		return
 	}
 
 	userID, err := uuid.Parse(userIDStr.(string))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid user ID",
-		})
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
 		return
 	}
 
 	categoryIDStr := ctx.Param("id")
 	categoryID, err := uuid.Parse(categoryIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid category ID",
-		})
+		ctx.Error(invalidID("Invalid category ID", err))
 		return
 	}
 
 	var input request.CreateCategoryRequest
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid input",
-			Errors:  err.Error(),
-		})
+		ctx.Error(bindError(ctx, err))
 		return
 	}
 
-	category, err := c.services.UpdateById(userID, categoryID, input)
+	category, err := c.services.UpdateById(ctx.Request.Context(), userID, categoryID, middlewares.IfMatchVersion(ctx), input)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.BaseResponse{
-			Status:  false,
-			Message: "Failed to update category",
-			Errors:  err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
+	middlewares.SetETag(ctx, category.Version)
 	ctx.JSON(http.StatusOK, response.BaseResponse{
 		Status:  true,
 		Message: "Category updated successfully",
@@ -285,47 +227,38 @@ func (c *CategoryController) UpdateById(ctx *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "Category ID"
+// @Param        If-Match header string true "ETag / version kategori"
 // @Success      200 {object} response.BaseResponse
 // @Failure      400 {object} response.BaseResponse
 // @Failure      404 {object} response.BaseResponse
+// @Failure      409 {object} response.BaseResponse
+// @Failure      412 {object} response.BaseResponse
+// @Failure      428 {object} response.BaseResponse
 // @Security 	 BearerAuth
 // @Router       /categories/{id} [patch]
 func (c *CategoryController) DeleteById(ctx *gin.Context) {
 	userIDStr, exists := ctx.Get("user_id")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, response.BaseResponse{
-			Status:  false,
-			Message: "Unauthorized",
-		})
+		ctx.Error(apperror.ErrUnauthorized)
 		return
 	}
 
 	userID, err := uuid.Parse(userIDStr.(string))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid user ID",
-		})
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
 		return
 	}
 
 	categoryIDStr := ctx.Param("id")
 	categoryID, err := uuid.Parse(categoryIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid category ID",
-		})
+		ctx.Error(invalidID("Invalid category ID", err))
 		return
 	}
 
-	err = c.services.DeleteById(userID, categoryID)
+	err = c.services.DeleteById(ctx.Request.Context(), userID, categoryID, middlewares.IfMatchVersion(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.BaseResponse{
-			Status:  false,
-			Message: "Failed to delete category",
-			Errors:  err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
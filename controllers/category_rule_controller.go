@@ -0,0 +1,282 @@
+package controllers
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CategoryRuleController struct {
+	service services.CategoryRuleService
+}
+
+func NewCategoryRuleController(s services.CategoryRuleService) *CategoryRuleController {
+	return &CategoryRuleController{service: s}
+}
+
+// CreateRule godoc
+// @Summary      Create Category Rule
+// @Description  Rule auto-kategori, contoh: title contains "GRAB" dan amount lt 100000 -> kategori Transport, tag commute. Semua kondisi harus cocok. Amount dibandingin pakai nilai absolut. Rule dievaluasi urut priority (kecil duluan), yang pertama cocok menang.
+// @Tags         Category Rules
+// @Accept       json
+// @Produce      json
+// @Param        request body request.CategoryRuleRequest true "request body"
+// @Success      201 {object} response.BaseResponse{data=response.CategoryRuleResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /category-rules [post]
+func (c *CategoryRuleController) CreateRule(ctx *gin.Context) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var input request.CategoryRuleRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	rule, err := c.service.Create(ctx.Request.Context(), userID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.BaseResponse{
+		Status:  true,
+		Message: "Rule created successfully",
+		Data:    rule,
+	})
+}
+
+// GetMyRules godoc
+// @Summary      Get My Category Rules
+// @Description  Semua rule user, urut priority.
+// @Tags         Category Rules
+// @Produce      json
+// @Success      200 {object} response.BaseResponse{data=[]response.CategoryRuleResponse}
+// @Failure      401 {object} response.BaseResponse
+// @Failure      500 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /category-rules [get]
+func (c *CategoryRuleController) GetMyRules(ctx *gin.Context) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	rules, err := c.service.GetMine(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Rules retrieved successfully",
+		Data:    rules,
+	})
+}
+
+// UpdateRule godoc
+// @Summary      Update Category Rule
+// @Description  Replace isi rule (kondisi, kategori, tag, priority, active).
+// @Tags         Category Rules
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Rule ID"
+// @Param        request body request.CategoryRuleRequest true "request body"
+// @Success      200 {object} response.BaseResponse{data=response.CategoryRuleResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /category-rules/{id} [put]
+func (c *CategoryRuleController) UpdateRule(ctx *gin.Context) {
+	userID, ruleID, ok := c.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	var input request.CategoryRuleRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	rule, err := c.service.Update(ctx.Request.Context(), userID, ruleID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Rule updated successfully",
+		Data:    rule,
+	})
+}
+
+// DeleteRule godoc
+// @Summary      Delete Category Rule
+// @Tags         Category Rules
+// @Produce      json
+// @Param        id path string true "Rule ID"
+// @Success      200 {object} response.BaseResponse
+// @Failure      404 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /category-rules/{id} [delete]
+func (c *CategoryRuleController) DeleteRule(ctx *gin.Context) {
+	userID, ruleID, ok := c.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.Delete(ctx.Request.Context(), userID, ruleID); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Rule deleted successfully",
+	})
+}
+
+// ReorderRules godoc
+// @Summary      Reorder Category Rules
+// @Description  Urutan rule_ids jadi urutan evaluasi (priority 0, 1, 2, ...). Rule yang gak disebut ditaruh di belakang.
+// @Tags         Category Rules
+// @Accept       json
+// @Produce      json
+// @Param        request body request.ReorderCategoryRulesRequest true "request body"
+// @Success      200 {object} response.BaseResponse{data=[]response.CategoryRuleResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /category-rules/order [put]
+func (c *CategoryRuleController) ReorderRules(ctx *gin.Context) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var input request.ReorderCategoryRulesRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	rules, err := c.service.Reorder(ctx.Request.Context(), userID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Rules reordered successfully",
+		Data:    rules,
+	})
+}
+
+// TestRules godoc
+// @Summary      Test Category Rules
+// @Description  Cek rule mana yang bakal kepakai buat transaksi contoh, tanpa nyimpen apa-apa.
+// @Tags         Category Rules
+// @Accept       json
+// @Produce      json
+// @Param        request body request.TestCategoryRuleRequest true "request body"
+// @Success      200 {object} response.BaseResponse{data=response.TestCategoryRuleResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /category-rules/test [post]
+func (c *CategoryRuleController) TestRules(ctx *gin.Context) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var input request.TestCategoryRuleRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	result, err := c.service.Test(ctx.Request.Context(), userID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Rules evaluated",
+		Data:    result,
+	})
+}
+
+// ApplyRules godoc
+// @Summary      Apply Category Rules
+// @Description  Jalanin ulang rule ke transaksi yang dipilih, atau (kalau transaction_ids kosong) ke semua transaksi "Uncategorized". Tanda nominal & saldo wallet ikut disesuaikan kalau tipe kategori berubah.
+// @Tags         Category Rules
+// @Accept       json
+// @Produce      json
+// @Param        request body request.ApplyCategoryRulesRequest false "request body"
+// @Success      200 {object} response.BaseResponse{data=response.ApplyCategoryRulesResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /category-rules/apply [post]
+func (c *CategoryRuleController) ApplyRules(ctx *gin.Context) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var input request.ApplyCategoryRulesRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+			ctx.Error(bindError(ctx, err))
+			return
+		}
+	}
+
+	result, err := c.service.Apply(ctx.Request.Context(), userID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Rules applied",
+		Data:    result,
+	})
+}
+
+func (c *CategoryRuleController) getUserID(ctx *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func (c *CategoryRuleController) parseIDs(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	ruleID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(invalidID("Invalid rule ID", err))
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, ruleID, true
+}
@@ -1,9 +1,12 @@
 package controllers
 
 import (
+	"cashflow_gin/apperror"
 	"cashflow_gin/dto/request"
 	"cashflow_gin/dto/response"
+	"cashflow_gin/middlewares"
 	"cashflow_gin/services"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -39,37 +42,23 @@ func NewGroupController(service services.GroupService) *GroupController {
 func (c *GroupController) GetGroupByID(ctx *gin.Context) {
 	groupID := ctx.Param("id")
 	if groupID == "" {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Group ID is required",
-			Errors:  nil,
-			Data:    nil,
-		})
+		ctx.Error(invalidID("Group ID is required", nil))
 		return
 	}
 
 	groupIDParsed, err := uuid.Parse(groupID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid group ID format",
-			Errors:  err.Error(),
-			Data:    nil,
-		})
+		ctx.Error(invalidID("Invalid group ID format", err))
 		return
 	}
 
-	group, err := c.services.GetGroupByID(groupIDParsed)
+	group, err := c.services.GetGroupByID(ctx.Request.Context(), groupIDParsed)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.BaseResponse{
-			Status:  false,
-			Message: "Failed to retrieve group",
-			Errors:  err.Error(),
-			Data:    nil,
-		})
+		ctx.Error(err)
 		return
 	}
 
+	middlewares.SetETag(ctx, group.Version)
 	ctx.JSON(http.StatusOK, response.BaseResponse{
 		Status:  true,
 		Message: "Group retrieved successfully",
@@ -78,14 +67,9 @@ func (c *GroupController) GetGroupByID(ctx *gin.Context) {
 }
 
 func (c *GroupController) GetAllGroups(ctx *gin.Context) {
-	groups, err := c.services.GetAllGroups()
+	groups, err := c.services.GetAllGroups(ctx.Request.Context())
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.BaseResponse{
-			Status:  false,
-			Message: "Failed to retrieve groups",
-			Errors:  err.Error(),
-			Data:    nil,
-		})
+		ctx.Error(err)
 		return
 	}
 
@@ -111,56 +95,31 @@ func (c *GroupController) GetAllGroups(ctx *gin.Context) {
 func (c *GroupController) CreateGroup(ctx *gin.Context) {
 	var req request.CreateGroupRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid request body",
-			Errors:  err.Error(),
-			Data:    nil,
-		})
+		ctx.Error(bindError(ctx, err))
 		return
 	}
 
 	userIDClaim, exists := ctx.Get("user_id")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, response.BaseResponse{
-			Status:  false,
-			Message: "Unauthorized",
-			Errors:  "User ID not found in token",
-			Data:    nil,
-		})
+		ctx.Error(apperror.ErrUnauthorized)
 		return
 	}
 
 	userIDStr, ok := userIDClaim.(string)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, response.BaseResponse{
-			Status:  false,
-			Message: "Internal Server Error",
-			Errors:  "User ID claim is not a string",
-			Data:    nil,
-		})
+		ctx.Error(apperror.ErrUnauthorized)
 		return
 	}
 
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.BaseResponse{
-			Status:  false,
-			Message: "Internal Server Error",
-			Errors:  "Invalid User ID format",
-			Data:    nil,
-		})
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
 		return
 	}
 
-	newGroup, err := c.services.CreateGroup(userID, req)
+	newGroup, err := c.services.CreateGroup(ctx.Request.Context(), userID, req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.BaseResponse{
-			Status:  false,
-			Message: "Failed to create group",
-			Errors:  err.Error(),
-			Data:    nil,
-		})
+		ctx.Error(err)
 		return
 	}
 
@@ -171,15 +130,71 @@ func (c *GroupController) CreateGroup(ctx *gin.Context) {
 	})
 }
 
+// UpdateGroup godoc
+// @Summary      Update Group
+// @Description  Mengubah nama & deskripsi grup. Hanya owner / admin grup.
+// @Tags         Groups
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "ID Grup"
+// @Param        If-Match header string true "ETag / version grup dari GET"
+// @Param        request body request.UpdateGroupRequest true "request body"
+// @Success      200 {object} response.BaseResponse{data=response.GroupResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      409 {object} response.BaseResponse
+// @Failure      412 {object} response.BaseResponse
+// @Failure      428 {object} response.BaseResponse
+// @Failure      500 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /groups/{id}/update [patch]
+func (c *GroupController) UpdateGroup(ctx *gin.Context) {
+	groupID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(invalidID("Invalid group ID format", err))
+		return
+	}
+
+	var req request.UpdateGroupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	userIDClaim, _ := ctx.Get("user_id")
+	userID, err := uuid.Parse(fmt.Sprintf("%v", userIDClaim))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	group, err := c.services.UpdateGroup(ctx.Request.Context(), userID, groupID, middlewares.IfMatchVersion(ctx), req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	middlewares.SetETag(ctx, group.Version)
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Group updated successfully",
+		Data:    group,
+	})
+}
+
 // RemoveUserFromGroup godoc
 // @Summary      Remove User From Group
-// @Description  Menghapus pengguna dari grup.
+// @Description  Menghapus pengguna dari grup. Hanya owner / admin grup, atau pengguna itu sendiri (keluar dari grup). Owner tidak bisa dihapus.
 // @Tags         Groups
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "ID Grup"
+// @Param        If-Match header string true "ETag / version grup dari GET"
 // @Success      200 {object} response.BaseResponse
 // @Failure      400 {object} response.BaseResponse
+// @Failure      403 {object} response.BaseResponse
+// @Failure      409 {object} response.BaseResponse
+// @Failure      412 {object} response.BaseResponse
+// @Failure      428 {object} response.BaseResponse
 // @Failure      500 {object} response.BaseResponse
 // @Security 	 BearerAuth
 // @Router       /groups/{id}/remove-user [patch]
@@ -187,44 +202,31 @@ func (c *GroupController) RemoveUserFromGroup(ctx *gin.Context) {
 	groupID := ctx.Param("id")
 	var input removeUser
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid request body",
-			Errors:  err.Error(),
-			Data:    nil,
-		})
+		ctx.Error(bindError(ctx, err))
 		return
 	}
 
 	groupUUID, err := uuid.Parse(groupID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid group ID format",
-			Errors:  err.Error(),
-			Data:    nil,
-		})
+		ctx.Error(invalidID("Invalid group ID format", err))
 		return
 	}
 	userUUID, err := uuid.Parse(input.UserID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid User ID format",
-			Errors:  err.Error(),
-			Data:    nil,
-		})
+		ctx.Error(invalidID("Invalid User ID format", err))
+		return
+	}
+
+	actorIDClaim, _ := ctx.Get("user_id")
+	actorID, err := uuid.Parse(fmt.Sprintf("%v", actorIDClaim))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
 		return
 	}
 
-	err = c.services.RemoveUserFromGroup(groupUUID, userUUID)
+	err = c.services.RemoveUserFromGroup(ctx.Request.Context(), actorID, groupUUID, userUUID, middlewares.IfMatchVersion(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.BaseResponse{
-			Status:  false,
-			Message: "Failed to remove user from group",
-			Errors:  err.Error(),
-			Data:    nil,
-		})
+		ctx.Error(err)
 		return
 	}
 
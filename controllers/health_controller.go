@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"cashflow_gin/dto/response"
+	"cashflow_gin/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	service services.HealthService
+}
+
+func NewHealthController(s services.HealthService) *HealthController {
+	return &HealthController{service: s}
+}
+
+// Liveness (GET /healthz) selalu 200 selama proses hidup. Sengaja gak ngecek DB,
+// biar orchestrator gak restart pod cuma gara-gara DB lagi down.
+func (c *HealthController) Liveness(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "ok",
+	})
+}
+
+// Readiness (GET /readyz) 200 kalau DB bisa di-ping & gak ada migration pending.
+// 503 kalau salah satu gagal atau server lagi shutdown.
+func (c *HealthController) Readiness(ctx *gin.Context) {
+	ready, checks := c.service.Readiness(ctx.Request.Context())
+	if !ready {
+		ctx.JSON(http.StatusServiceUnavailable, response.BaseResponse{
+			Status:  false,
+			Message: "not ready",
+			Data:    checks,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "ready",
+		Data:    checks,
+	})
+}
@@ -1,7 +1,9 @@
 package controllers
 
 import (
+	"cashflow_gin/apperror"
 	"cashflow_gin/dto/response"
+	"cashflow_gin/validation"
 	"fmt"
 	"net/http"
 
@@ -37,14 +39,19 @@ func (c *TransactionController) sendSuccess(ctx *gin.Context, message string, da
 	})
 }
 
-func (c *TransactionController) sendError(ctx *gin.Context, code int, message string, err error) {
-	errVal := ""
-	if err != nil {
-		errVal = err.Error()
+// bindError: body/query gagal di-bind ke DTO. Gagal validasi (atau tipe JSON salah) -> 422 dengan
+// daftar field di details, pesannya ikut Accept-Language. Body gak kebaca sama sekali -> 400 invalid_input.
+func bindError(ctx *gin.Context, err error) error {
+	lang := validation.Language(ctx.GetHeader("Accept-Language"))
+	if fields := validation.Translate(err, lang); len(fields) > 0 {
+		return apperror.Validation("validation_failed", validation.Message(lang, "validation_failed")).
+			WithDetails(fields).
+			Wrap(err)
 	}
-	ctx.JSON(code, response.BaseResponse{
-		Status:  false,
-		Message: message,
-		Errors:  errVal,
-	})
+	return apperror.BadRequest("invalid_input", validation.Message(lang, "invalid_input")).Wrap(err)
+}
+
+// invalidID: ID di path/query bukan UUID yang valid -> 400 invalid_id.
+func invalidID(message string, err error) error {
+	return apperror.BadRequest("invalid_id", message).Wrap(err)
 }
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationController struct {
+	service services.NotificationService
+}
+
+func NewNotificationController(s services.NotificationService) *NotificationController {
+	return &NotificationController{service: s}
+}
+
+// GetMyNotifications godoc
+// @Summary      Get My Notifications
+// @Description  50 notifikasi terbaru + jumlah yang belum dibaca. Pakai ?unread=true buat yang belum dibaca aja.
+// @Tags         Notifications
+// @Produce      json
+// @Param        unread query bool false "Hanya yang belum dibaca"
+// @Success      200 {object} response.BaseResponse{data=response.NotificationListResponse}
+// @Failure      401 {object} response.BaseResponse
+// @Failure      500 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /notifications [get]
+func (c *NotificationController) GetMyNotifications(ctx *gin.Context) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	notifications, err := c.service.GetMine(ctx.Request.Context(), userID, ctx.Query("unread") == "true")
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Notifications retrieved successfully",
+		Data:    notifications,
+	})
+}
+
+// MarkRead godoc
+// @Summary      Mark Notification Read
+// @Tags         Notifications
+// @Produce      json
+// @Param        id path string true "Notification ID"
+// @Success      200 {object} response.BaseResponse
+// @Failure      400 {object} response.BaseResponse
+// @Failure      404 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /notifications/{id}/read [patch]
+func (c *NotificationController) MarkRead(ctx *gin.Context) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	notificationID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(invalidID("Invalid notification ID", err))
+		return
+	}
+
+	if err := c.service.MarkRead(ctx.Request.Context(), userID, notificationID); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Notification marked as read",
+	})
+}
+
+// MarkAllRead godoc
+// @Summary      Mark All Notifications Read
+// @Tags         Notifications
+// @Produce      json
+// @Success      200 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Failure      500 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /notifications/read-all [post]
+func (c *NotificationController) MarkAllRead(ctx *gin.Context) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	updated, err := c.service.MarkAllRead(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "All notifications marked as read",
+		Data:    gin.H{"updated": updated},
+	})
+}
+
+// GetPreferences godoc
+// @Summary      Get Notification Preferences
+// @Description  Status tiap kombinasi tipe notifikasi x channel (in_app, email). Default: in_app nyala, email mati.
+// @Tags         Notifications
+// @Produce      json
+// @Success      200 {object} response.BaseResponse{data=[]response.NotificationPreferenceResponse}
+// @Failure      401 {object} response.BaseResponse
+// @Failure      500 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /notifications/preferences [get]
+func (c *NotificationController) GetPreferences(ctx *gin.Context) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	preferences, err := c.service.GetPreferences(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Preferences retrieved successfully",
+		Data:    preferences,
+	})
+}
+
+// UpdatePreferences godoc
+// @Summary      Update Notification Preferences
+// @Description  Nyalain / matiin channel per tipe notifikasi. Kombinasi yang gak dikirim gak berubah.
+// @Tags         Notifications
+// @Accept       json
+// @Produce      json
+// @Param        request body request.UpdateNotificationPreferencesRequest true "request body"
+// @Success      200 {object} response.BaseResponse{data=[]response.NotificationPreferenceResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /notifications/preferences [put]
+func (c *NotificationController) UpdatePreferences(ctx *gin.Context) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var input request.UpdateNotificationPreferencesRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	preferences, err := c.service.UpdatePreferences(ctx.Request.Context(), userID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Preferences updated successfully",
+		Data:    preferences,
+	})
+}
+
+func (c *NotificationController) getUserID(ctx *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return uuid.Nil, false
+	}
+	return userID, true
+}
@@ -0,0 +1,259 @@
+package controllers
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PayeeController struct {
+	service services.PayeeService
+}
+
+func NewPayeeController(s services.PayeeService) *PayeeController {
+	return &PayeeController{service: s}
+}
+
+// Autocomplete godoc
+// @Summary      Autocomplete Payees
+// @Description  Cari payee yang nama/alias-nya diawali q (case & tanda baca diabaikan), yang paling sering dipakai duluan. group_id kosong = payee pribadi.
+// @Tags         Payees
+// @Produce      json
+// @Param        q query string true "Kata kunci"
+// @Param        group_id query string false "Payee milik group"
+// @Param        limit query int false "Max hasil (default 10, max 50)"
+// @Success      200 {object} response.BaseResponse{data=[]response.PayeeResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /payees [get]
+func (c *PayeeController) Autocomplete(ctx *gin.Context) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var query request.PayeeSearchQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	payees, err := c.service.Autocomplete(ctx.Request.Context(), userID, query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Payees retrieved successfully",
+		Data:    payees,
+	})
+}
+
+// CreatePayee godoc
+// @Summary      Create Payee
+// @Description  Bikin payee (merchant) + alias. default_category_name dipakai kalau transaksi ke payee ini gak nyebut kategori. Nama/alias gak boleh bentrok dengan payee lain.
+// @Tags         Payees
+// @Accept       json
+// @Produce      json
+// @Param        request body request.PayeeRequest true "request body"
+// @Success      201 {object} response.BaseResponse{data=response.PayeeResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /payees [post]
+func (c *PayeeController) CreatePayee(ctx *gin.Context) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var input request.PayeeRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	payee, err := c.service.Create(ctx.Request.Context(), userID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.BaseResponse{
+		Status:  true,
+		Message: "Payee created successfully",
+		Data:    payee,
+	})
+}
+
+// UpdatePayee godoc
+// @Summary      Update Payee
+// @Description  Ganti nama, default kategori & semua alias payee. group_id diabaikan.
+// @Tags         Payees
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Payee ID"
+// @Param        request body request.PayeeRequest true "request body"
+// @Success      200 {object} response.BaseResponse{data=response.PayeeResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /payees/{id} [put]
+func (c *PayeeController) UpdatePayee(ctx *gin.Context) {
+	userID, payeeID, ok := c.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	var input request.PayeeRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	payee, err := c.service.Update(ctx.Request.Context(), userID, payeeID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Payee updated successfully",
+		Data:    payee,
+	})
+}
+
+// DeletePayee godoc
+// @Summary      Delete Payee
+// @Description  Hapus payee. Transaksinya tetap ada, cuma dilepas dari payee.
+// @Tags         Payees
+// @Produce      json
+// @Param        id path string true "Payee ID"
+// @Success      200 {object} response.BaseResponse
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /payees/{id} [delete]
+func (c *PayeeController) DeletePayee(ctx *gin.Context) {
+	userID, payeeID, ok := c.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.Delete(ctx.Request.Context(), userID, payeeID); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Payee deleted successfully",
+	})
+}
+
+// MergePayees godoc
+// @Summary      Merge Payees
+// @Description  Gabungin payee dobel ("STARBUCKS JKT", "starbucks") ke target. Transaksi source pindah ke target, nama & alias source jadi alias target, source dihapus. Semua payee harus satu pemilik (pribadi / group yang sama).
+// @Tags         Payees
+// @Accept       json
+// @Produce      json
+// @Param        request body request.MergePayeesRequest true "request body"
+// @Success      200 {object} response.BaseResponse{data=response.PayeeResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /payees/merge [post]
+func (c *PayeeController) MergePayees(ctx *gin.Context) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var input request.MergePayeesRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	payee, err := c.service.Merge(ctx.Request.Context(), userID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Payees merged successfully",
+		Data:    payee,
+	})
+}
+
+// GetPayeeHistory godoc
+// @Summary      Payee Spending History
+// @Description  Total, rekap per bulan & transaksi terbaru (max 100) ke payee ini.
+// @Tags         Payees
+// @Produce      json
+// @Param        id path string true "Payee ID"
+// @Param        from query string false "Tanggal awal (YYYY-MM-DD)"
+// @Param        to query string false "Tanggal akhir, inklusif (YYYY-MM-DD)"
+// @Success      200 {object} response.BaseResponse{data=response.PayeeHistoryResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /payees/{id}/history [get]
+func (c *PayeeController) GetPayeeHistory(ctx *gin.Context) {
+	userID, payeeID, ok := c.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	var query request.PayeeHistoryQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	history, err := c.service.History(ctx.Request.Context(), userID, payeeID, query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Payee history retrieved successfully",
+		Data:    history,
+	})
+}
+
+func (c *PayeeController) getUserID(ctx *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func (c *PayeeController) parseIDs(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	payeeID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(invalidID("Invalid payee ID", err))
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, payeeID, true
+}
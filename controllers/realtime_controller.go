@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/services"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Komentar SSE dikirim berkala biar proxy/load balancer gak nutup koneksi yang idle.
+const realtimeHeartbeatInterval = 25 * time.Second
+
+type RealtimeController struct {
+	hub *services.RealtimeHub
+}
+
+func NewRealtimeController(hub *services.RealtimeHub) *RealtimeController {
+	return &RealtimeController{hub: hub}
+}
+
+// Stream godoc
+// @Summary      Realtime Wallet Events (SSE)
+// @Description  Server-Sent Events stream untuk transaksi (created/updated/deleted) dan perubahan saldo. Default subscribe semua wallet pribadi + wallet group yang diikuti. Karena EventSource gak bisa kirim header, token boleh lewat query access_token.
+// @Tags         Realtime
+// @Produce      text/event-stream
+// @Param        wallet_ids query string false "Wallet ID dipisah koma"
+// @Param        access_token query string false "JWT / API key (alternatif header Authorization)"
+// @Success      200 {string} string "event stream"
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Failure      403 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /events/stream [get]
+func (c *RealtimeController) Stream(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	var walletIDs []uuid.UUID
+	if raw := ctx.Query("wallet_ids"); raw != "" {
+		for _, idStr := range strings.Split(raw, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(idStr))
+			if err != nil {
+				ctx.Error(invalidID("Invalid wallet ID format", err))
+				return
+			}
+			walletIDs = append(walletIDs, id)
+		}
+	}
+
+	sub, err := c.hub.Subscribe(ctx.Request.Context(), userID, walletIDs)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	defer sub.Close()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no") // matiin buffering nginx
+	ctx.Status(http.StatusOK)
+	ctx.Render(-1, sse.Event{Event: "ready", Data: gin.H{"subscription": "ok"}})
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(realtimeHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := ctx.Writer.WriteString(": ping\n\n"); err != nil {
+				return
+			}
+			ctx.Writer.Flush()
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			if !sub.Allowed(ctx.Request.Context(), event) {
+				continue
+			}
+			ctx.Render(-1, sse.Event{Id: event.ID.String(), Event: event.Type, Data: event})
+			ctx.Writer.Flush()
+		}
+	}
+}
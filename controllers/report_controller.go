@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReportController struct {
+	service services.ReportService
+}
+
+func NewReportController(s services.ReportService) *ReportController {
+	return &ReportController{service: s}
+}
+
+// CategoryReport godoc
+// @Summary      Category Report
+// @Description  Total pemasukan/pengeluaran per kategori di rentang tanggal. Transaksi split dihitung per kategori line-nya. Default bulan berjalan.
+// @Tags         Reports
+// @Produce      json
+// @Param        from query string false "Tanggal awal (YYYY-MM-DD)"
+// @Param        to query string false "Tanggal akhir, inklusif (YYYY-MM-DD)"
+// @Param        wallet_id query string false "Filter satu wallet (wallet group: semua transaksi member)"
+// @Success      200 {object} response.BaseResponse{data=response.CategoryReportResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Security 	 ApiKeyAuth
+// @Router       /reports/categories [get]
+func (c *ReportController) CategoryReport(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	var query request.CategoryReportQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	report, err := c.service.CategoryReport(ctx.Request.Context(), userID, query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Report retrieved successfully",
+		Data:    report,
+	})
+}
@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/services"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Batas ukuran file mutasi yang di-upload
+const maxStatementFileSize = 5 << 20
+
+type StatementImportController struct {
+	service services.StatementImportService
+}
+
+func NewStatementImportController(s services.StatementImportService) *StatementImportController {
+	return &StatementImportController{service: s}
+}
+
+// UploadStatement godoc
+// @Summary      Upload Bank Statement
+// @Description  Upload file mutasi (OFX/QFX, QIF, CAMT.053) ke wallet tertentu. Hasilnya baris-baris yang perlu di-review (kategori, skip) sebelum di-commit. Baris yang terdeteksi duplikat (tanggal + nominal + payee + FITID) otomatis di-skip.
+// @Tags         Imports
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        wallet_id formData string true "Wallet ID"
+// @Param        format formData string false "ofx | qif | camt (kosong = deteksi otomatis)"
+// @Param        file formData file true "File mutasi, max 5MB"
+// @Param        Idempotency-Key header string false "Key unik per request, retry dengan key sama nge-replay response pertama"
+// @Success      201 {object} response.BaseResponse{data=response.StatementImportResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /imports [post]
+func (c *StatementImportController) UploadStatement(ctx *gin.Context) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	walletID, err := uuid.Parse(ctx.PostForm("wallet_id"))
+	if err != nil {
+		ctx.Error(invalidID("Invalid wallet ID", err))
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.Error(apperror.BadRequest("file_required", "File is required").Wrap(err))
+		return
+	}
+	if fileHeader.Size > maxStatementFileSize {
+		ctx.Error(apperror.New(apperror.KindPayloadTooLarge, "file_too_large", "File terlalu besar (max 5MB)"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.Error(apperror.BadRequest("file_unreadable", "Failed to read file").Wrap(err))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxStatementFileSize))
+	if err != nil {
+		ctx.Error(apperror.BadRequest("file_unreadable", "Failed to read file").Wrap(err))
+		return
+	}
+
+	result, err := c.service.Upload(ctx.Request.Context(), userID, walletID, ctx.PostForm("format"), fileHeader.Filename, data)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.BaseResponse{
+		Status:  true,
+		Message: "Statement parsed, review the rows before committing",
+		Data:    result,
+	})
+}
+
+// GetMyImports godoc
+// @Summary      Get My Imports
+// @Tags         Imports
+// @Produce      json
+// @Success      200 {object} response.BaseResponse{data=[]response.StatementImportResponse}
+// @Failure      401 {object} response.BaseResponse
+// @Failure      500 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /imports [get]
+func (c *StatementImportController) GetMyImports(ctx *gin.Context) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	imports, err := c.service.GetMine(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Imports retrieved successfully",
+		Data:    imports,
+	})
+}
+
+// GetImport godoc
+// @Summary      Get Import Detail
+// @Description  Detail import beserta semua barisnya.
+// @Tags         Imports
+// @Produce      json
+// @Param        id path string true "Import ID"
+// @Success      200 {object} response.BaseResponse{data=response.StatementImportResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      404 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /imports/{id} [get]
+func (c *StatementImportController) GetImport(ctx *gin.Context) {
+	userID, importID, ok := c.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := c.service.GetByID(ctx.Request.Context(), userID, importID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Import retrieved successfully",
+		Data:    result,
+	})
+}
+
+// ReviewRows godoc
+// @Summary      Review Import Rows
+// @Description  Set kategori (category_name) dan/atau skip per baris. Baris duplikat bisa dipaksa masuk dengan skip=false.
+// @Tags         Imports
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Import ID"
+// @Param        request body request.ReviewStatementRowsRequest true "request body"
+// @Success      200 {object} response.BaseResponse{data=response.StatementImportResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /imports/{id}/rows [patch]
+func (c *StatementImportController) ReviewRows(ctx *gin.Context) {
+	userID, importID, ok := c.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	var input request.ReviewStatementRowsRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	result, err := c.service.ReviewRows(ctx.Request.Context(), userID, importID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Rows updated successfully",
+		Data:    result,
+	})
+}
+
+// CommitImport godoc
+// @Summary      Commit Import
+// @Description  Semua baris yang gak di-skip dimasukin jadi transaksi sekaligus (satu DB transaction) dan saldo wallet di-update. Tiap baris wajib punya kategori yang tipenya cocok sama tanda nominal.
+// @Tags         Imports
+// @Produce      json
+// @Param        id path string true "Import ID"
+// @Param        Idempotency-Key header string false "Key unik per request, retry dengan key sama nge-replay response pertama"
+// @Success      200 {object} response.BaseResponse{data=response.StatementImportResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /imports/{id}/commit [post]
+func (c *StatementImportController) CommitImport(ctx *gin.Context) {
+	userID, importID, ok := c.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := c.service.Commit(ctx.Request.Context(), userID, importID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Import committed successfully",
+		Data:    result,
+	})
+}
+
+func (c *StatementImportController) getUserID(ctx *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func (c *StatementImportController) parseIDs(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := c.getUserID(ctx)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	importID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(invalidID("Invalid import ID", err))
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, importID, true
+}
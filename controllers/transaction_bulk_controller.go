@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BulkCreate godoc
+// @Summary      Bulk Create Transactions
+// @Description  Membuat banyak transaksi sekaligus (max 500). Tiap item dicek sama seperti create satuan; item yang lolos disimpan dalam satu DB transaction dan saldo tiap wallet digeser sebesar net delta-nya. Hasil per item ada di data.items.
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        request body request.BulkCreateTransactionRequest true "request body"
+// @Param        Idempotency-Key header string false "Key unik per request, retry dengan key sama nge-replay response pertama"
+// @Success      200 {object} response.BaseResponse{data=response.BulkTransactionResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      500 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /transactions/bulk/create [post]
+func (c *TransactionController) BulkCreate(ctx *gin.Context) {
+	var input request.BulkCreateTransactionRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	userID, err := c.getUserID(ctx)
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	result, err := c.service.BulkCreate(ctx.Request.Context(), userID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	c.sendSuccess(ctx, "Bulk create processed", result)
+}
+
+// BulkRecategorize godoc
+// @Summary      Bulk Recategorize Transactions
+// @Description  Ganti kategori banyak transaksi sekaligus. Kalau tipe kategori berubah (EXPENSE <-> INCOME) tanda nominal dibalik dan saldo wallet disesuaikan.
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        request body request.BulkRecategorizeRequest true "request body"
+// @Success      200 {object} response.BaseResponse{data=response.BulkTransactionResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      500 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /transactions/bulk/recategorize [post]
+func (c *TransactionController) BulkRecategorize(ctx *gin.Context) {
+	var input request.BulkRecategorizeRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	userID, err := c.getUserID(ctx)
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	result, err := c.service.BulkRecategorize(ctx.Request.Context(), userID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	c.sendSuccess(ctx, "Bulk recategorize processed", result)
+}
+
+// BulkMove godoc
+// @Summary      Bulk Move Transactions
+// @Description  Pindahin banyak transaksi ke wallet lain. Saldo wallet asal & tujuan disesuaikan.
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        request body request.BulkMoveRequest true "request body"
+// @Param        Idempotency-Key header string false "Key unik per request, retry dengan key sama nge-replay response pertama"
+// @Success      200 {object} response.BaseResponse{data=response.BulkTransactionResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      500 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /transactions/bulk/move [post]
+func (c *TransactionController) BulkMove(ctx *gin.Context) {
+	var input request.BulkMoveRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	userID, err := c.getUserID(ctx)
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	result, err := c.service.BulkMove(ctx.Request.Context(), userID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	c.sendSuccess(ctx, "Bulk move processed", result)
+}
+
+// BulkDelete godoc
+// @Summary      Bulk Soft Delete Transactions
+// @Description  Soft delete banyak transaksi sekaligus, saldo wallet dikembalikan.
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        request body request.BulkTransactionIDsRequest true "request body"
+// @Success      200 {object} response.BaseResponse{data=response.BulkTransactionResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      500 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /transactions/bulk/delete [post]
+func (c *TransactionController) BulkDelete(ctx *gin.Context) {
+	var input request.BulkTransactionIDsRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	userID, err := c.getUserID(ctx)
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	result, err := c.service.BulkDelete(ctx.Request.Context(), userID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	c.sendSuccess(ctx, "Bulk delete processed", result)
+}
@@ -1,9 +1,10 @@
 package controllers
 
 import (
+	"cashflow_gin/apperror"
 	"cashflow_gin/dto/request"
+	"cashflow_gin/middlewares"
 	"cashflow_gin/services"
-	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,11 +19,12 @@ func NewTransactionController(service services.TransactionService) *TransactionC
 
 // Create godoc
 // @Summary      Create Transaction
-// @Description  Membuat transaksi baru.
+// @Description  Membuat transaksi baru. Isi lines buat split satu transaksi ke beberapa kategori (total lines = amount).
 // @Tags         Transactions
 // @Accept       json
 // @Produce      json
 // @Param        request body request.CreateTransactionRequest true "request body"
+// @Param        Idempotency-Key header string false "Key unik per request, retry dengan key sama nge-replay response pertama"
 // @Success      201 {object} response.BaseResponse{data=response.TransactionResponse}
 // @Failure      400 {object} response.BaseResponse
 // @Failure      500 {object} response.BaseResponse
@@ -31,20 +33,20 @@ func NewTransactionController(service services.TransactionService) *TransactionC
 func (c *TransactionController) Create(ctx *gin.Context) {
 	var input request.CreateTransactionRequest
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		c.sendError(ctx, http.StatusBadRequest, "Invalid input data", err)
+		ctx.Error(bindError(ctx, err))
 		return
 	}
 
 	// 1 baris untuk ambil UserID
 	userID, err := c.getUserID(ctx)
 	if err != nil {
-		c.sendError(ctx, http.StatusUnauthorized, "Unauthorized", err)
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
 		return
 	}
 
-	newTransaction, err := c.service.Create(userID, input)
+	newTransaction, err := c.service.Create(ctx.Request.Context(), userID, input)
 	if err != nil {
-		c.sendError(ctx, http.StatusInternalServerError, "Failed to create transaction", err)
+		ctx.Error(err)
 		return
 	}
 
@@ -64,9 +66,9 @@ func (c *TransactionController) Create(ctx *gin.Context) {
 func (c *TransactionController) FindAll(ctx *gin.Context) {
 	// Note: Harusnya FindAll juga butuh userID kan? Transaction itu private per user.
 	// Tapi aku ikutin logic code aslimu dulu.
-	transactions, err := c.service.GetAll()
+	transactions, err := c.service.GetAll(ctx.Request.Context())
 	if err != nil {
-		c.sendError(ctx, http.StatusInternalServerError, "Failed to retrieve transactions", err)
+		ctx.Error(err)
 		return
 	}
 
@@ -89,63 +91,69 @@ func (c *TransactionController) GetTransactionByID(ctx *gin.Context) {
 	// Reuse helper getParamID
 	transactionID, err := c.getParamID(ctx, "id")
 	if err != nil {
-		c.sendError(ctx, http.StatusBadRequest, "Invalid transaction ID", err)
+		ctx.Error(invalidID("Invalid transaction ID", err))
 		return
 	}
 
 	userID, err := c.getUserID(ctx)
 	if err != nil {
-		c.sendError(ctx, http.StatusUnauthorized, "Unauthorized", err)
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
 		return
 	}
 
-	transaction, err := c.service.GetTransactionByID(userID, transactionID)
+	transaction, err := c.service.GetTransactionByID(ctx.Request.Context(), userID, transactionID)
 	if err != nil {
-		c.sendError(ctx, http.StatusInternalServerError, "Failed to retrieve transaction", err)
+		ctx.Error(err)
 		return
 	}
 
+	middlewares.SetETag(ctx, transaction.Version)
 	c.sendSuccess(ctx, "Transaction retrieved successfully", transaction)
 }
 
 // UpdateTransaction godoc
 // @Summary      Update Transaction
-// @Description  Memperbarui transaksi berdasarkan ID.
+// @Description  Memperbarui transaksi berdasarkan ID. category_id bisa ganti tipe (EXPENSE <-> INCOME, tanda nominal ikut dibalik), wallet_id mindahin transaksi ke wallet lain (saldo kedua wallet disesuaikan). lines ganti semua line split ([] = hapus split), transaksi split gak bisa ganti category_id / amount tanpa lines.
 // @Tags         Transactions
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "Transaction ID"
+// @Param        If-Match header string true "ETag / version transaksi dari GET"
 // @Param        request body request.UpdateTransactionRequest true "request body"
 // @Success      200 {object} response.BaseResponse{data=response.TransactionResponse}
 // @Failure      400 {object} response.BaseResponse
+// @Failure      409 {object} response.BaseResponse
+// @Failure      412 {object} response.BaseResponse
+// @Failure      428 {object} response.BaseResponse
 // @Failure      500 {object} response.BaseResponse
 // @Security 	 BearerAuth
 // @Router       /transactions/{id}/update [patch]
 func (c *TransactionController) UpdateTransaction(ctx *gin.Context) {
 	transactionID, err := c.getParamID(ctx, "id")
 	if err != nil {
-		c.sendError(ctx, http.StatusBadRequest, "Invalid transaction ID", err)
+		ctx.Error(invalidID("Invalid transaction ID", err))
 		return
 	}
 
 	var input request.UpdateTransactionRequest
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		c.sendError(ctx, http.StatusBadRequest, "Invalid input format", err)
+		ctx.Error(bindError(ctx, err))
 		return
 	}
 
 	userID, err := c.getUserID(ctx)
 	if err != nil {
-		c.sendError(ctx, http.StatusUnauthorized, "Unauthorized", err)
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
 		return
 	}
 
-	updatedTransaction, err := c.service.UpdateTransaction(userID, transactionID, input)
+	updatedTransaction, err := c.service.UpdateTransaction(ctx.Request.Context(), userID, transactionID, middlewares.IfMatchVersion(ctx), input)
 	if err != nil {
-		c.sendError(ctx, http.StatusInternalServerError, "Failed to update transaction", err)
+		ctx.Error(err)
 		return
 	}
 
+	middlewares.SetETag(ctx, updatedTransaction.Version)
 	c.sendSuccess(ctx, "Transaction updated successfully", updatedTransaction)
 }
 
@@ -157,33 +165,37 @@ func (c *TransactionController) UpdateTransaction(ctx *gin.Context) {
 // @Produce      json
 // @Param        id path string true "Transaction ID"
 // @Param        walletid path string true "Wallet ID"
+// @Param        If-Match header string true "ETag / version transaksi dari GET"
 // @Success      200 {object} response.BaseResponse
 // @Failure      400 {object} response.BaseResponse
+// @Failure      409 {object} response.BaseResponse
+// @Failure      412 {object} response.BaseResponse
+// @Failure      428 {object} response.BaseResponse
 // @Failure      500 {object} response.BaseResponse
 // @Security 	 BearerAuth
 // @Router       /transactions/{id}/wallet/{walletid}/soft-delete [patch]
 func (c *TransactionController) SoftDeleteTransaction(ctx *gin.Context) {
 	transactionID, err := c.getParamID(ctx, "id")
 	if err != nil {
-		c.sendError(ctx, http.StatusBadRequest, "Invalid transaction ID", err)
+		ctx.Error(invalidID("Invalid transaction ID", err))
 		return
 	}
 
 	walletID, err := c.getParamID(ctx, "walletid")
 	if err != nil {
-		c.sendError(ctx, http.StatusBadRequest, "Invalid wallet ID", err)
+		ctx.Error(invalidID("Invalid wallet ID", err))
 		return
 	}
 
 	userID, err := c.getUserID(ctx)
 	if err != nil {
-		c.sendError(ctx, http.StatusUnauthorized, "Unauthorized", err)
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
 		return
 	}
 
-	err = c.service.SoftDeleteTransaction(userID, transactionID, walletID)
+	err = c.service.SoftDeleteTransaction(ctx.Request.Context(), userID, transactionID, walletID, middlewares.IfMatchVersion(ctx))
 	if err != nil {
-		c.sendError(ctx, http.StatusInternalServerError, "Failed to soft delete transaction", err)
+		ctx.Error(err)
 		return
 	}
 
@@ -1,8 +1,11 @@
 package controllers
 
 import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
 	"cashflow_gin/dto/response"
 	"cashflow_gin/services"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -28,14 +31,9 @@ func NewUserController(s services.UserService) *UserController {
 // @Security 	 BearerAuth
 // @Router       /users/ [get]
 func (c *UserController) FindAllUser(ctx *gin.Context) {
-	users, err := c.service.FindAllUser()
+	users, err := c.service.FindAllUser(ctx.Request.Context())
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError,
-			response.BaseResponse{
-				Status:  false,
-				Message: "error",
-				Errors:  err.Error(),
-			})
+		ctx.Error(err)
 		return
 	}
 
@@ -51,23 +49,13 @@ func (c *UserController) GetMyProfile(ctx *gin.Context) {
 	// 1 baris untuk ambil UserID
 	userID, err := uuid.Parse(ctx.GetString("user_id"))
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, response.BaseResponse{
-			Status:  false,
-			Message: "Unauthorized",
-			Errors:  err.Error(),
-			Data:    nil,
-		})
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
 		return
 	}
 
-	user, err := c.service.GetMyProfile(userID)
+	user, err := c.service.GetMyProfile(ctx.Request.Context(), userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.BaseResponse{
-			Status:  false,
-			Message: "Failed to retrieve profile",
-			Errors:  err.Error(),
-			Data:    nil,
-		})
+		ctx.Error(err)
 		return
 	}
 
@@ -78,3 +66,143 @@ func (c *UserController) GetMyProfile(ctx *gin.Context) {
 		Errors:  nil,
 	})
 }
+
+// UpdateMyProfile godoc
+// @Summary      Update My Profile
+// @Description  Mengubah username dan/atau email user yang sedang login.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        request body request.UpdateProfileRequest true "request body"
+// @Success      200 {object} response.BaseResponse{data=response.UserResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Failure      409 {object} response.BaseResponse "email_taken / username_taken"
+// @Security 	 BearerAuth
+// @Router       /users/me [patch]
+func (c *UserController) UpdateMyProfile(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	var input request.UpdateProfileRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	user, err := c.service.UpdateProfile(ctx.Request.Context(), userID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Profile updated successfully",
+		Data:    user,
+	})
+}
+
+// ChangePassword godoc
+// @Summary      Change Password
+// @Description  Mengganti password user yang sedang login. Wajib menyertakan password lama.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        request body request.ChangePasswordRequest true "request body"
+// @Success      200 {object} response.BaseResponse
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /users/me/password [post]
+func (c *UserController) ChangePassword(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	var input request.ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	if err := c.service.ChangePassword(ctx.Request.Context(), userID, input); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Password changed successfully",
+	})
+}
+
+// DeleteMyAccount godoc
+// @Summary      Delete My Account
+// @Description  Soft delete akun beserta wallet pribadi. Group yang dimiliki dipindah ke member lain jika transfer_ownership=true, kalau tidak request ditolak.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        request body request.DeleteAccountRequest true "request body"
+// @Success      200 {object} response.BaseResponse
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /users/me [delete]
+func (c *UserController) DeleteMyAccount(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	var input request.DeleteAccountRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	if err := c.service.DeleteAccount(ctx.Request.Context(), userID, input); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Account deleted successfully",
+	})
+}
+
+// ExportMyData godoc
+// @Summary      Export My Data
+// @Description  Download semua data milik user (profil, wallet, transaksi, kategori, group) sebagai file JSON.
+// @Tags         Users
+// @Produce      json
+// @Success      200 {object} response.UserExportResponse
+// @Failure      401 {object} response.BaseResponse
+// @Failure      500 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /users/me/export [get]
+func (c *UserController) ExportMyData(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	export, err := c.service.ExportData(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// Dikirim sebagai file, bukan dibungkus BaseResponse, biar bisa langsung disimpan
+	filename := fmt.Sprintf("cashflow-export-%s-%s.json", userID, export.ExportedAt.Format("20060102"))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.IndentedJSON(http.StatusOK, export)
+}
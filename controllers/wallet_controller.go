@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"cashflow_gin/apperror"
 	"cashflow_gin/dto/response"
 	"cashflow_gin/services"
 	"net/http"
@@ -29,14 +30,9 @@ func NewWalletController(s services.WalletService) *WalletController {
 // @Router       /wallets [get]
 func (c *WalletController) GetAllWallets(ctx *gin.Context) {
 	// Implementasi untuk mendapatkan semua wallet
-	wallets, err := c.services.GetAll()
+	wallets, err := c.services.GetAll(ctx.Request.Context())
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.BaseResponse{
-			Status:  false,
-			Message: "Failed to get wallets",
-			Errors:  err.Error(),
-			Data:    nil,
-		})
+		ctx.Error(err)
 		return
 	}
 
@@ -64,33 +60,18 @@ func (c *WalletController) GetAllWallets(ctx *gin.Context) {
 func (c *WalletController) GetWalletByID(ctx *gin.Context) {
 	reqId, exists := ctx.Get("user_id")
 	if !exists {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid user ID",
-			Errors:  "User ID not found in context",
-			Data:    nil,
-		})
+		ctx.Error(apperror.ErrUnauthorized)
 		return
 	}
 
 	userID, err := uuid.Parse(reqId.(string))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid user ID format",
-			Errors:  err.Error(),
-			Data:    nil,
-		})
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
 		return
 	}
 	walletID, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-			Status:  false,
-			Message: "Invalid wallet ID format",
-			Errors:  err.Error(),
-			Data:    nil,
-		})
+		ctx.Error(invalidID("Invalid wallet ID format", err))
 		return
 	}
 	groupIDParams, ok := ctx.GetQuery("groupid")
@@ -107,25 +88,15 @@ func (c *WalletController) GetWalletByID(ctx *gin.Context) {
 	} else {
 		groupID, err = uuid.Parse(groupIDParams)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, response.BaseResponse{
-				Status:  false,
-				Message: "Invalid group ID format",
-				Errors:  err.Error(),
-				Data:    nil,
-			})
+			ctx.Error(invalidID("Invalid group ID format", err))
 			return
 		}
 	}
 
-	wallet, err := c.services.GetWalletByID(userID, walletID, groupID)
+	wallet, err := c.services.GetWalletByID(ctx.Request.Context(), userID, walletID, groupID)
 	if err != nil {
 		// Handle the error
-		ctx.JSON(http.StatusInternalServerError, response.BaseResponse{
-			Status:  false,
-			Message: "Failed to get wallet",
-			Errors:  err.Error(),
-			Data:    nil,
-		})
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusOK, response.BaseResponse{
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WebhookController struct {
+	service services.WebhookService
+}
+
+func NewWebhookController(s services.WebhookService) *WebhookController {
+	return &WebhookController{service: s}
+}
+
+// CreateWebhook godoc
+// @Summary      Create Webhook
+// @Description  Daftarin URL yang bakal dikirimin event (transaction.created, transaction.updated, transaction.deleted, wallet.balance_changed). Payload ditandatangani HMAC-SHA256 di header X-Cashflow-Signature. Secret cuma ditampilkan sekali.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        request body request.CreateWebhookRequest true "request body"
+// @Success      201 {object} response.BaseResponse{data=response.CreatedWebhookResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      401 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /webhooks [post]
+func (c *WebhookController) CreateWebhook(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	var input request.CreateWebhookRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.Error(bindError(ctx, err))
+		return
+	}
+
+	webhook, err := c.service.Create(ctx.Request.Context(), userID, input)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.BaseResponse{
+		Status:  true,
+		Message: "Webhook created, store the secret to verify signatures",
+		Data:    webhook,
+	})
+}
+
+// GetMyWebhooks godoc
+// @Summary      Get My Webhooks
+// @Description  Daftar webhook yang dibuat user.
+// @Tags         Webhooks
+// @Produce      json
+// @Success      200 {object} response.BaseResponse{data=[]response.WebhookResponse}
+// @Failure      401 {object} response.BaseResponse
+// @Failure      500 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /webhooks [get]
+func (c *WebhookController) GetMyWebhooks(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return
+	}
+
+	webhooks, err := c.service.GetMine(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Webhooks retrieved successfully",
+		Data:    webhooks,
+	})
+}
+
+// DeleteWebhook godoc
+// @Summary      Delete Webhook
+// @Description  Menghapus webhook, event berikutnya gak akan dikirim lagi.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        id path string true "Webhook ID"
+// @Success      200 {object} response.BaseResponse
+// @Failure      400 {object} response.BaseResponse
+// @Failure      404 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /webhooks/{id} [delete]
+func (c *WebhookController) DeleteWebhook(ctx *gin.Context) {
+	userID, webhookID, ok := c.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.Delete(ctx.Request.Context(), userID, webhookID); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Webhook deleted successfully",
+	})
+}
+
+// GetDeliveries godoc
+// @Summary      Get Webhook Deliveries
+// @Description  Log pengiriman webhook (50 percobaan terakhir), termasuk retry.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        id path string true "Webhook ID"
+// @Success      200 {object} response.BaseResponse{data=[]response.WebhookDeliveryResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      404 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /webhooks/{id}/deliveries [get]
+func (c *WebhookController) GetDeliveries(ctx *gin.Context) {
+	userID, webhookID, ok := c.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	deliveries, err := c.service.GetDeliveries(ctx.Request.Context(), userID, webhookID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Deliveries retrieved successfully",
+		Data:    deliveries,
+	})
+}
+
+// TestWebhook godoc
+// @Summary      Test Webhook
+// @Description  Kirim event "ping" sekali (tanpa retry) dan balikin hasil pengirimannya.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        id path string true "Webhook ID"
+// @Success      200 {object} response.BaseResponse{data=response.WebhookDeliveryResponse}
+// @Failure      400 {object} response.BaseResponse
+// @Failure      404 {object} response.BaseResponse
+// @Security 	 BearerAuth
+// @Router       /webhooks/{id}/test [post]
+func (c *WebhookController) TestWebhook(ctx *gin.Context) {
+	userID, webhookID, ok := c.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	delivery, err := c.service.Test(ctx.Request.Context(), userID, webhookID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  true,
+		Message: "Ping sent",
+		Data:    delivery,
+	})
+}
+
+// parseIDs ambil user ID dari token & webhook ID dari path. Kalau gagal response udah dikirim.
+func (c *WebhookController) parseIDs(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.Error(apperror.ErrUnauthorized.Wrap(err))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	webhookID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(invalidID("Invalid webhook ID", err))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, webhookID, true
+}
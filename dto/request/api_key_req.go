@@ -0,0 +1,9 @@
+package request
+
+import "time"
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100" example:"Script Pembukuan"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=transactions:read transactions:write wallets:read categories:read reports:read" example:"transactions:read,reports:read"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2026-12-31T00:00:00Z"` // Opsional, kosong = gak pernah expired
+}
@@ -0,0 +1,32 @@
+package request
+
+type RuleConditionInput struct {
+	Field    string `json:"field" binding:"required,oneof=title description amount" example:"title"`
+	Operator string `json:"operator" binding:"required,oneof=contains equals starts_with regex lt lte gt gte" example:"contains"`
+	Value    string `json:"value" binding:"required,max=255" example:"GRAB"`
+}
+
+// Dipakai buat create (POST) dan replace (PUT).
+type CategoryRuleRequest struct {
+	Name         string               `json:"name" binding:"required,max=100" example:"Grab = Transport"`
+	Priority     *int                 `json:"priority" binding:"omitempty,min=0" example:"0"`
+	Active       *bool                `json:"active" example:"true"`
+	Conditions   []RuleConditionInput `json:"conditions" binding:"required,min=1,max=10,dive"`
+	CategoryName string               `json:"category_name" binding:"required,max=100" example:"Transport"`
+	Tags         []string             `json:"tags" binding:"omitempty,max=10,dive,max=30" example:"commute"`
+}
+
+type ReorderCategoryRulesRequest struct {
+	RuleIDs []string `json:"rule_ids" binding:"required,min=1,dive,uuid"`
+}
+
+type TestCategoryRuleRequest struct {
+	Title       string  `json:"title" binding:"required,max=255" example:"GRAB *TRIP JKT"`
+	Description string  `json:"description" example:"Ke kantor"`
+	Amount      float64 `json:"amount" example:"25000"`
+}
+
+// TransactionIDs kosong = semua transaksi user yang masih "Uncategorized".
+type ApplyCategoryRulesRequest struct {
+	TransactionIDs []string `json:"transaction_ids" binding:"omitempty,max=500,dive,uuid"`
+}
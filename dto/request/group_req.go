@@ -5,3 +5,8 @@ type CreateGroupRequest struct {
 	Description string   `json:"description" example:"Kelompok untuk berbagi pengeluaran keluarga"`
 	MemberIDs   []string `json:"member_ids"` // List user ID lain yg mau diajak (opsional)
 }
+
+type UpdateGroupRequest struct {
+	Name        string `json:"name" binding:"required" example:"Kelompok Keluarga"`
+	Description string `json:"description" example:"Kelompok untuk berbagi pengeluaran keluarga"`
+}
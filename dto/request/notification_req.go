@@ -0,0 +1,11 @@
+package request
+
+type NotificationPreferenceInput struct {
+	Type    string `json:"type" binding:"required" example:"group.transaction_added"`
+	Channel string `json:"channel" binding:"required" example:"email"`
+	Enabled *bool  `json:"enabled" binding:"required" example:"true"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceInput `json:"preferences" binding:"required,min=1,dive"`
+}
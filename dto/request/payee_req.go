@@ -0,0 +1,24 @@
+package request
+
+type PayeeRequest struct {
+	Name                string   `json:"name" binding:"required,max=150" example:"Starbucks"`
+	GroupID             string   `json:"group_id" binding:"omitempty,uuid"` // cuma dipakai pas create, kosong = payee pribadi
+	Aliases             []string `json:"aliases" binding:"omitempty,max=20,dive,max=150" example:"STARBUCKS JKT,SBUX"`
+	DefaultCategoryName string   `json:"default_category_name" binding:"omitempty,max=100" example:"Makanan"`
+}
+
+type PayeeSearchQuery struct {
+	Q       string `form:"q" binding:"required,max=150" example:"star"`
+	GroupID string `form:"group_id" binding:"omitempty,uuid"` // kosong = payee pribadi
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=50" example:"10"`
+}
+
+type MergePayeesRequest struct {
+	TargetID  string   `json:"target_id" binding:"required,uuid"`
+	SourceIDs []string `json:"source_ids" binding:"required,min=1,max=50,dive,uuid"`
+}
+
+type PayeeHistoryQuery struct {
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02" example:"2026-01-01"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02" example:"2026-06-30"` // inklusif
+}
@@ -0,0 +1,7 @@
+package request
+
+type CategoryReportQuery struct {
+	From     string `form:"from" binding:"omitempty,datetime=2006-01-02" example:"2026-01-01"` // default: awal bulan ini
+	To       string `form:"to" binding:"omitempty,datetime=2006-01-02" example:"2026-01-31"`   // inklusif, default: akhir bulan ini
+	WalletID string `form:"wallet_id" binding:"omitempty,uuid"`                                // kosong = semua transaksi milik user
+}
@@ -0,0 +1,11 @@
+package request
+
+type StatementRowReview struct {
+	RowID        string `json:"row_id" binding:"required,uuid"`
+	CategoryName string `json:"category_name" binding:"omitempty,max=100"`
+	Skip         *bool  `json:"skip"`
+}
+
+type ReviewStatementRowsRequest struct {
+	Rows []StatementRowReview `json:"rows" binding:"required,min=1,dive"`
+}
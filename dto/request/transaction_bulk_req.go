@@ -0,0 +1,20 @@
+package request
+
+// Semua endpoint bulk dibatasi 500 item per request.
+type BulkCreateTransactionRequest struct {
+	Items []CreateTransactionRequest `json:"items" binding:"required,min=1,max=500,dive"`
+}
+
+type BulkTransactionIDsRequest struct {
+	TransactionIDs []string `json:"transaction_ids" binding:"required,min=1,max=500,dive,uuid"`
+}
+
+type BulkRecategorizeRequest struct {
+	TransactionIDs []string `json:"transaction_ids" binding:"required,min=1,max=500,dive,uuid"`
+	CategoryName   string   `json:"category_name" binding:"required,max=100" example:"Transport"`
+}
+
+type BulkMoveRequest struct {
+	TransactionIDs []string `json:"transaction_ids" binding:"required,min=1,max=500,dive,uuid"`
+	WalletID       string   `json:"wallet_id" binding:"required,uuid"`
+}
@@ -6,18 +6,33 @@ import (
 
 type CreateTransactionRequest struct {
 	WalletID     string    `json:"wallet_id" binding:"required,uuid"`
-	CategoryName string    `json:"category_name" binding:"required,max=100"`
+	CategoryName string    `json:"category_name" binding:"omitempty,max=100"` // Kosong = default kategori payee / rule, fallback "Uncategorized"
 	Title        string    `json:"title" binding:"required,max=255"`
-	Amount       float64   `json:"amount" binding:"required,gt=0"` // Amount harus > 0
+	Amount       float64   `json:"amount" binding:"required,gt=0,money"` // Amount harus > 0, maks 2 desimal
 	Description  string    `json:"description"`
 	Date         time.Time `json:"date" binding:"required"` // Format: RFC3339 (e.g., "2026-02-02T15:04:05Z")
+	Tags         []string  `json:"tags" binding:"omitempty,max=10,dive,max=30"`
+	PayeeID      string    `json:"payee_id" binding:"omitempty,uuid"`
+	PayeeName    string    `json:"payee_name" binding:"omitempty,max=150" example:"Starbucks"` // dicocokin ke nama/alias, belum ada = dibikin
+	// Split ke beberapa kategori, total amount lines harus = amount. Kalau diisi, category_name diabaikan.
+	Lines []TransactionLineRequest `json:"lines" binding:"omitempty,max=50,dive"`
+}
+
+type TransactionLineRequest struct {
+	CategoryName string  `json:"category_name" binding:"required,max=100" example:"Makanan"`
+	Amount       float64 `json:"amount" binding:"required,gt=0,money" example:"150000"`
+	Description  string  `json:"description" binding:"omitempty,max=255" example:"Belanja dapur"`
 }
 
 // Untuk Update, biasanya field-nya optional (pake pointer)
 type UpdateTransactionRequest struct {
 	Title       string    `json:"title" binding:"omitempty,max=255"`
-	Amount      float64   `json:"amount" binding:"omitempty,gt=0"`
+	Amount      float64   `json:"amount" binding:"omitempty,gt=0,money"`
 	Description string    `json:"description"`
-	CategoryID  string    `json:"category_id" binding:"omitempty,uuid"`
+	CategoryID  string    `json:"category_id" binding:"omitempty,uuid"` // EXPENSE <-> INCOME: tanda nominal ikut dibalik
+	WalletID    string    `json:"wallet_id" binding:"omitempty,uuid"`   // Pindah wallet, saldo kedua wallet disesuaikan
+	PayeeID     string    `json:"payee_id" binding:"omitempty,uuid"`
 	Date        time.Time `json:"date"`
+	// null/gak dikirim = lines gak diubah, [] = hapus split, isi = ganti semua lines
+	Lines *[]TransactionLineRequest `json:"lines" binding:"omitempty,max=50,dive"`
 }
@@ -9,3 +9,34 @@ type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email" example:"john.doe@example.com"`
 	Password string `json:"password" binding:"required" example:"johndoeganteng"`
 }
+
+// LoginTwoFactorRequest = step kedua login kalau 2FA aktif. Code bisa kode TOTP 6 digit atau recovery code.
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required,max=32" example:"123456"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required,len=6" example:"123456"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required" example:"johndoeganteng"`
+}
+
+type UpdateProfileRequest struct {
+	Username string `json:"username" binding:"omitempty,max=100" example:"john_doe"`
+	Email    string `json:"email" binding:"omitempty,email,max=100" example:"john.doe@example.com"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required" example:"password123"`
+	NewPassword     string `json:"new_password" binding:"required,max=255" example:"passwordBaru123"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required" example:"password123"`
+	// Kalau true, group yang dimiliki dipindah ke member lain (prioritas ADMIN, lalu member paling lama).
+	// Kalau false, hapus akun ditolak selama user masih punya group.
+	TransferOwnership bool `json:"transfer_ownership" example:"true"`
+}
@@ -0,0 +1,7 @@
+package request
+
+type CreateWebhookRequest struct {
+	URL     string   `json:"url" binding:"required,url,max=500" example:"https://example.com/hooks/cashflow"`
+	Events  []string `json:"events" binding:"required,min=1" example:"transaction.created,wallet.balance_changed"`
+	GroupID string   `json:"group_id" binding:"omitempty,uuid"` // Kosong = webhook wallet pribadi
+}
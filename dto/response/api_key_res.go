@@ -0,0 +1,19 @@
+package response
+
+import "time"
+
+type APIKeyResponse struct {
+	ID         string     `json:"id" example:"123e4567-e89b-12d3-a456-426655440000"`
+	Name       string     `json:"name" example:"Script Pembukuan"`
+	Prefix     string     `json:"prefix" example:"cfk_ab12cd34"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" format:"date-time"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" format:"date-time"`
+	CreatedAt  time.Time  `json:"created_at" format:"date-time"`
+}
+
+// CreatedAPIKeyResponse: field Key cuma muncul sekali di sini, setelah itu gak bisa dilihat lagi.
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key" example:"cfk_ab12cd34_k3y5ecr3t"`
+}
@@ -0,0 +1,18 @@
+package response
+
+// LoginResponse: kalau 2FA aktif, Token kosong & client wajib lanjut ke /auth/login/2fa pakai ChallengeToken.
+type LoginResponse struct {
+	Token             string `json:"token,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required" example:"false"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret          string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	ProvisioningURI string `json:"provisioning_uri" example:"otpauth://totp/Cashflow:john.doe@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Cashflow"`
+}
+
+// RecoveryCodesResponse cuma dikirim sekali pas 2FA diaktifkan, setelah itu yang disimpan cuma hash-nya.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -6,4 +6,5 @@ type CategoryResponse struct {
 	GroupID string `json:"group_id,omitempty"`
 	Name    string `json:"name" example:"Makanan"`
 	Type    string `json:"type" example:"EXPENSE"`
+	Version int    `json:"version" example:"1"`
 }
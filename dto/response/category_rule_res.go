@@ -0,0 +1,33 @@
+package response
+
+import "time"
+
+type RuleConditionResponse struct {
+	Field    string `json:"field" example:"title"`
+	Operator string `json:"operator" example:"contains"`
+	Value    string `json:"value" example:"GRAB"`
+}
+
+type CategoryRuleResponse struct {
+	ID         string                  `json:"id"`
+	Name       string                  `json:"name" example:"Grab = Transport"`
+	Priority   int                     `json:"priority" example:"0"`
+	Active     bool                    `json:"active" example:"true"`
+	Conditions []RuleConditionResponse `json:"conditions"`
+	Category   CategoryResponse        `json:"category"`
+	Tags       []string                `json:"tags"`
+	CreatedAt  time.Time               `json:"created_at" format:"date-time"`
+}
+
+type TestCategoryRuleResponse struct {
+	Matched bool                  `json:"matched" example:"true"`
+	Rule    *CategoryRuleResponse `json:"rule,omitempty"` // rule pemenang (priority paling kecil)
+	// Semua rule aktif yang cocok, urut priority
+	MatchingRuleIDs []string `json:"matching_rule_ids"`
+}
+
+type ApplyCategoryRulesResponse struct {
+	Checked      int                   `json:"checked" example:"20"`
+	Updated      int                   `json:"updated" example:"12"`
+	Transactions []TransactionResponse `json:"transactions"`
+}
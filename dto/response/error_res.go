@@ -0,0 +1,15 @@
+package response
+
+// ErrorResponse = isi field "errors" di BaseResponse kalau request gagal.
+// Code stabil (aman dipakai client buat switch), Details opsional (misal field yang gak valid).
+type ErrorResponse struct {
+	Code    string      `json:"code" example:"transaction_not_found"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// FieldError = satu field yang gagal validasi, dikirim sebagai Details di ErrorResponse.
+type FieldError struct {
+	Field   string `json:"field" example:"items[0].amount"`
+	Rule    string `json:"rule" example:"gt"`
+	Message string `json:"message" example:"amount harus lebih dari 0"`
+}
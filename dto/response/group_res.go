@@ -10,6 +10,7 @@ type GroupResponse struct {
 	Wallet       WalletResponse        `json:"wallet"` // Group pasti punya wallet
 	Members      []GroupMemberResponse `json:"members,omitempty"`
 	TotalMembers int64                 `json:"total_members,omitempty" example:"5"`
+	Version      int                   `json:"version" example:"1"`
 }
 
 type GroupMemberResponse struct {
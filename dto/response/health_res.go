@@ -0,0 +1,7 @@
+package response
+
+type HealthCheckResponse struct {
+	Name   string `json:"name" example:"database"`
+	Status string `json:"status" example:"ok"` // ok | fail
+	Error  string `json:"error,omitempty"`
+}
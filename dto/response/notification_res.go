@@ -0,0 +1,27 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type NotificationResponse struct {
+	ID        string          `json:"id" example:"123e4567-e89b-12d3-a456-426655440000"`
+	Type      string          `json:"type" example:"group.member_added"`
+	Title     string          `json:"title" example:"Kamu ditambahkan ke group Kos Bareng"`
+	Body      string          `json:"body"`
+	Data      json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+	ReadAt    *time.Time      `json:"read_at" format:"date-time"`
+	CreatedAt time.Time       `json:"created_at" format:"date-time"`
+}
+
+type NotificationListResponse struct {
+	UnreadCount   int64                  `json:"unread_count" example:"3"`
+	Notifications []NotificationResponse `json:"notifications"`
+}
+
+type NotificationPreferenceResponse struct {
+	Type    string `json:"type" example:"group.transaction_added"`
+	Channel string `json:"channel" example:"email"`
+	Enabled bool   `json:"enabled" example:"true"`
+}
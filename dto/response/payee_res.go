@@ -0,0 +1,30 @@
+package response
+
+type PayeeResponse struct {
+	ID               string            `json:"id" example:"123e4567-e89b-12d3-a456-426655440000"`
+	Name             string            `json:"name" example:"Starbucks"`
+	GroupID          string            `json:"group_id,omitempty"`
+	Aliases          []string          `json:"aliases" example:"STARBUCKS JKT,SBUX"`
+	DefaultCategory  *CategoryResponse `json:"default_category,omitempty"`
+	TransactionCount int64             `json:"transaction_count,omitempty" example:"12"`
+}
+
+// PayeeSummaryResponse = payee versi ringkas yang nempel di TransactionResponse.
+type PayeeSummaryResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name" example:"Starbucks"`
+}
+
+type PayeeMonthlyTotal struct {
+	Month            string  `json:"month" example:"2026-01"`
+	Total            float64 `json:"total" example:"-175000"`
+	TransactionCount int     `json:"transaction_count" example:"3"`
+}
+
+type PayeeHistoryResponse struct {
+	Payee            PayeeResponse         `json:"payee"`
+	TotalAmount      float64               `json:"total_amount" example:"-540000"`
+	TransactionCount int                   `json:"transaction_count" example:"9"`
+	Monthly          []PayeeMonthlyTotal   `json:"monthly"`
+	Transactions     []TransactionResponse `json:"transactions"` // terbaru dulu, max 100
+}
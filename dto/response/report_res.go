@@ -0,0 +1,16 @@
+package response
+
+type CategoryReportItem struct {
+	Category         CategoryResponse `json:"category"`
+	Total            float64          `json:"total" example:"-350000"`
+	TransactionCount int64            `json:"transaction_count" example:"4"`
+}
+
+type CategoryReportResponse struct {
+	From         string               `json:"from" example:"2026-01-01"`
+	To           string               `json:"to" example:"2026-01-31"`
+	WalletID     string               `json:"wallet_id,omitempty"`
+	TotalIncome  float64              `json:"total_income" example:"5000000"`
+	TotalExpense float64              `json:"total_expense" example:"-1250000"`
+	Categories   []CategoryReportItem `json:"categories"`
+}
@@ -0,0 +1,29 @@
+package response
+
+import "time"
+
+type StatementImportRowResponse struct {
+	ID            string            `json:"id"`
+	Date          time.Time         `json:"date" format:"date-time"`
+	Amount        float64           `json:"amount" example:"-50000"`
+	Payee         string            `json:"payee" example:"INDOMARET JKT"`
+	Memo          string            `json:"memo"`
+	ExternalID    string            `json:"external_id" example:"20240125001"`
+	Duplicate     bool              `json:"duplicate" example:"false"`
+	Skip          bool              `json:"skip" example:"false"`
+	Category      *CategoryResponse `json:"category,omitempty"`
+	Tags          []string          `json:"tags"`
+	TransactionID string            `json:"transaction_id,omitempty"`
+}
+
+type StatementImportResponse struct {
+	ID         string                       `json:"id"`
+	WalletID   string                       `json:"wallet_id"`
+	Format     string                       `json:"format" example:"ofx"`
+	FileName   string                       `json:"file_name" example:"mutasi-jan.ofx"`
+	Status     string                       `json:"status" example:"pending"`
+	TotalRows  int                          `json:"total_rows" example:"42"`
+	Duplicates int                          `json:"duplicates" example:"3"`
+	CreatedAt  time.Time                    `json:"created_at" format:"date-time"`
+	Rows       []StatementImportRowResponse `json:"rows,omitempty"`
+}
@@ -0,0 +1,17 @@
+package response
+
+// BulkItemResult = hasil per item, urut sesuai input (Index = posisi di request).
+type BulkItemResult struct {
+	Index       int                  `json:"index" example:"0"`
+	ID          string               `json:"id,omitempty"`
+	Success     bool                 `json:"success" example:"true"`
+	Error       string               `json:"error,omitempty"`
+	Transaction *TransactionResponse `json:"transaction,omitempty"`
+}
+
+type BulkTransactionResponse struct {
+	Total     int              `json:"total" example:"10"`
+	Succeeded int              `json:"succeeded" example:"9"`
+	Failed    int              `json:"failed" example:"1"`
+	Items     []BulkItemResult `json:"items"`
+}
@@ -20,6 +20,19 @@ type TransactionResponse struct {
 	Amount      float64          `json:"amount" example:"500"`
 	Description string           `json:"description" example:"Gaji bulan Januari 2026"`
 	Date        time.Time        `json:"date" example:"2026-01-31T00:00:00Z" format:"date-time"`
+	Tags        []string         `json:"tags"`
+	Version     int              `json:"version" example:"1"`
 	Category    CategoryResponse `json:"category"`
 	User        UserResponse     `json:"user"`
+
+	Payee *PayeeSummaryResponse `json:"payee,omitempty"`
+
+	Lines []TransactionLineResponse `json:"lines,omitempty"`
+}
+
+type TransactionLineResponse struct {
+	ID          string           `json:"id" example:"123e4567-e89b-12d3-a456-426655440000"`
+	Amount      float64          `json:"amount" example:"-150000"`
+	Description string           `json:"description" example:"Belanja dapur"`
+	Category    CategoryResponse `json:"category"`
 }
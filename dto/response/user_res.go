@@ -1,6 +1,8 @@
 package response
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -20,3 +22,21 @@ type WalletResponse struct {
 	Transactions     []TransactionResponse `json:"transactions,omitempty"`
 	TransactionCount int64                 `json:"transaction_count" example:"5"`
 }
+
+type GroupMembershipResponse struct {
+	GroupID   string    `json:"group_id" example:"123e4567-e89b-12d3-a456-426655440000"`
+	GroupName string    `json:"group_name" example:"Kelompok Keluarga"`
+	Role      string    `json:"role" example:"ADMIN"`
+	IsOwner   bool      `json:"is_owner" example:"true"`
+	JoinedAt  time.Time `json:"joined_at" format:"date-time"`
+}
+
+// UserExportResponse adalah arsip semua data milik user (GDPR-style export).
+type UserExportResponse struct {
+	ExportedAt   time.Time                 `json:"exported_at" format:"date-time"`
+	Profile      UserResponse              `json:"profile"`
+	Wallets      []WalletResponse          `json:"wallets"`
+	Transactions []TransactionResponse     `json:"transactions"`
+	Categories   []CategoryResponse        `json:"categories"`
+	Groups       []GroupMembershipResponse `json:"groups"`
+}
@@ -0,0 +1,30 @@
+package response
+
+import "time"
+
+type WebhookResponse struct {
+	ID        string    `json:"id" example:"123e4567-e89b-12d3-a456-426655440000"`
+	URL       string    `json:"url" example:"https://example.com/hooks/cashflow"`
+	Events    []string  `json:"events"`
+	GroupID   string    `json:"group_id,omitempty"`
+	Active    bool      `json:"active" example:"true"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+}
+
+// CreatedWebhookResponse: Secret (buat verifikasi signature) cuma dikirim sekali pas create.
+type CreatedWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret" example:"whsec_abc123"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID         string    `json:"id"`
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type" example:"transaction.created"`
+	Attempt    int       `json:"attempt" example:"1"`
+	StatusCode int       `json:"status_code" example:"200"`
+	Success    bool      `json:"success" example:"true"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms" example:"120"`
+	CreatedAt  time.Time `json:"created_at" format:"date-time"`
+}
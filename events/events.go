@@ -0,0 +1,103 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipe event yang dipublish service. Nama ini juga yang dipakai client buat subscribe webhook.
+// Cuma daftarin tipe yang beneran dipublish, biar gak ada subscription yang gak pernah kepanggil.
+const (
+	TransactionCreated   = "transaction.created"
+	TransactionUpdated   = "transaction.updated"
+	TransactionDeleted   = "transaction.deleted"
+	WalletBalanceChanged = "wallet.balance_changed"
+)
+
+var Types = []string{
+	TransactionCreated,
+	TransactionUpdated,
+	TransactionDeleted,
+	WalletBalanceChanged,
+}
+
+// Event = sesuatu yang udah kejadian (udah ke-commit di DB).
+// WalletUserID keisi kalau wallet-nya wallet pribadi, GroupID keisi kalau wallet group.
+type Event struct {
+	ID           uuid.UUID   `json:"id"`
+	Type         string      `json:"type"`
+	OccurredAt   time.Time   `json:"occurred_at"`
+	ActorID      uuid.UUID   `json:"actor_id"`
+	WalletID     *uuid.UUID  `json:"wallet_id,omitempty"`
+	WalletUserID *uuid.UUID  `json:"wallet_user_id,omitempty"`
+	GroupID      *uuid.UUID  `json:"group_id,omitempty"`
+	Data         interface{} `json:"data"`
+}
+
+// Handler dipanggil synchronous di goroutine yang publish, jadi wajib cepat (cukup enqueue aja).
+type Handler func(event Event)
+
+type Bus interface {
+	Publish(event Event)
+	Subscribe(handler Handler) (unsubscribe func())
+}
+
+type memoryBus struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[int]Handler
+}
+
+// NewBus bikin event bus in-process. Cukup buat 1 instance; kalau nanti multi instance tinggal ganti implementasi.
+func NewBus() Bus {
+	return &memoryBus{handlers: make(map[int]Handler)}
+}
+
+func (b *memoryBus) Publish(event Event) {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handler := range b.handlers {
+		handler(event)
+	}
+}
+
+func (b *memoryBus) Subscribe(handler Handler) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = handler
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+	}
+}
+
+// IsValidType dipakai buat validasi input subscribe.
+func IsValidType(eventType string) bool {
+	for _, t := range Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WalletBalancePayload = isi Data buat event wallet.balance_changed.
+type WalletBalancePayload struct {
+	WalletID uuid.UUID `json:"wallet_id"`
+	Delta    float64   `json:"delta"`
+	Balance  float64   `json:"balance"`
+}
@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger nerusin log GORM ke slog pakai logger dari ctx (jadi query ikut punya request_id).
+// Nilai parameter query gak pernah di-log (cuma placeholder $1, $2), biar hash password / token gak bocor.
+type GormLogger struct {
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+}
+
+// NewGormLogger: level = silent | error | warn | info (info = semua query).
+func NewGormLogger(level string, slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{level: ParseGormLevel(level), slowThreshold: slowThreshold}
+}
+
+func ParseGormLevel(level string) gormlogger.LogLevel {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "silent":
+		return gormlogger.Silent
+	case "error":
+		return gormlogger.Error
+	case "info":
+		return gormlogger.Info
+	default:
+		return gormlogger.Warn
+	}
+}
+
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		FromContext(ctx).InfoContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		FromContext(ctx).WarnContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		FromContext(ctx).ErrorContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+	}
+}
+
+// ParamsFilter dipanggil GORM sebelum SQL di-render buat log: params dibuang, SQL tetap pakai placeholder.
+func (l *GormLogger) ParamsFilter(_ context.Context, sql string, _ ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
+
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	logger := FromContext(ctx)
+	attrs := func() []any {
+		sql, rows := fc()
+		return []any{
+			"component", "gorm",
+			"sql", sql,
+			"rows", rows,
+			"duration_ms", float64(elapsed.Microseconds()) / 1000,
+		}
+	}
+
+	switch {
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		logger.ErrorContext(ctx, "query failed", append(attrs(), "error", err.Error())...)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormlogger.Warn:
+		logger.WarnContext(ctx, "slow query", append(attrs(), "threshold_ms", l.slowThreshold.Milliseconds())...)
+	case l.level >= gormlogger.Info:
+		logger.InfoContext(ctx, "query", attrs()...)
+	}
+}
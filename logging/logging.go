@@ -0,0 +1,65 @@
+// Package logging = logger slog aplikasi: JSON ke stdout, field sensitif di-redact,
+// dan logger per-request (udah bawa request_id) yang ikut di context.Context.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+type ctxKey struct{}
+
+// Options diisi dari config.LogConfig.
+type Options struct {
+	Level  string // debug | info | warn | error
+	Format string // json | text
+}
+
+// New bikin logger root. Dipasang juga jadi slog.Default() di main.
+func New(opts Options) *slog.Logger {
+	return newLogger(os.Stdout, opts)
+}
+
+func newLogger(w io.Writer, opts Options) *slog.Logger {
+	handlerOpts := &slog.HandlerOptions{
+		Level:       ParseLevel(opts.Level),
+		ReplaceAttr: redactAttr,
+	}
+	if strings.EqualFold(opts.Format, "text") {
+		return slog.New(slog.NewTextHandler(w, handlerOpts))
+	}
+	return slog.New(slog.NewJSONHandler(w, handlerOpts))
+}
+
+// ParseLevel: string kosong / gak dikenal = info.
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// WithLogger nempelin logger ke ctx, dipakai middleware RequestID.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, logger)
+}
+
+// FromContext ambil logger request (udah ada request_id, user_id). Fallback slog.Default()
+// buat kode yang jalan di luar request (worker, CLI).
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
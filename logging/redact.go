@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// Nama key (lowercase, bagian dari nama juga kena) yang value-nya gak boleh masuk log.
+var sensitiveKeys = []string{
+	"password",
+	"secret",
+	"token",
+	"authorization",
+	"api_key",
+	"apikey",
+	"x-api-key",
+	"cookie",
+	"recovery_code",
+	"totp",
+}
+
+// IsSensitiveKey: "password", "new_password", "access_token", "JWT_SECRET" semua dianggap sensitif.
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactQuery ganti value query param sensitif (misal access_token buat SSE) jadi [REDACTED].
+func RedactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return redacted
+	}
+	changed := false
+	for key := range values {
+		if IsSensitiveKey(key) {
+			values[key] = []string{redacted}
+			changed = true
+		}
+	}
+	if !changed {
+		return rawQuery
+	}
+	return values.Encode()
+}
+
+// redactAttr = ReplaceAttr handler slog. Berlaku buat semua log, termasuk attr di dalam group.
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if IsSensitiveKey(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	return a
+}
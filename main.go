@@ -1,16 +1,28 @@
 package main
 
 import (
-	"cashflow_gin/config"
 	_ "cashflow_gin/docs"
+	"cashflow_gin/config"
+	"cashflow_gin/logging"
+	"cashflow_gin/metrics"
+	"cashflow_gin/middlewares"
+	"cashflow_gin/repository"
+	"cashflow_gin/repository/memory"
 	"cashflow_gin/routes"
+	"cashflow_gin/tracing"
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
 )
 
 // @title           Cashflow API Gin
@@ -24,28 +36,154 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 // @host      localhost:8080
 // @BasePath  /api
 func main(){
-	err := godotenv.Load()
+	// Config dicek di awal, salah satu setting kosong/invalid langsung berhenti dengan pesan jelas
+	cfg, err := config.Load()
 	if err != nil {
-		panic(err)
+		log.Fatal(err)
 	}
-	db, err := config.NewDatabaseConnection()
+	if cfg.IsProduction() {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	// Logger JSON jadi default, package log bawaan juga ikut keluar lewat slog
+	logger := logging.New(logging.Options{Level: cfg.Log.Level, Format: cfg.Log.Format})
+	slog.SetDefault(logger)
+
+	// Tracing (OpenTelemetry). Exporter "none" = span gak direkam sama sekali
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+		ServiceName:  cfg.Tracing.ServiceName,
+		Environment:  cfg.Env,
+	})
 	if err != nil {
-		log.Fatal("Gagal Konek Database, err")
+		fatal(logger, "Gagal setup tracing", err)
 	}
-	
 
+	// DB_DRIVER=memory: gak ada database sama sekali, semua repository in-memory (mode demo)
+	var db *gorm.DB
+	var repos repository.Repositories
+	if cfg.Database.Driver == config.DriverMemory {
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			fatal(logger, "Migration gagal", errors.New("DB_DRIVER=memory gak pakai database, gak ada yang di-migrate"))
+		}
+		logger.Warn("Mode demo (DB_DRIVER=memory): semua data disimpan di memory & hilang pas server mati")
+		repos = memory.NewRepositories()
+	} else {
+		db, err = config.NewDatabaseConnection(cfg.Database, cfg.Log)
+		if err != nil {
+			fatal(logger, "Gagal Konek Database", err)
+		}
 
-	r := gin.Default()
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	
+		// `cashflow migrate up|down|status` -> jalanin migration terus keluar, server gak dinyalain
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			if err := runMigrate(db, os.Args[2:]); err != nil {
+				fatal(logger, "Migration gagal", err)
+			}
+			return
+		}
+		if err := setupDatabase(db, cfg); err != nil {
+			fatal(logger, "Gagal setup database", err)
+		}
+		repos = repository.NewRepositories(db)
+	}
+
+	r, background, err := routes.NewEngine(db, repos, cfg, logger)
+	if err != nil {
+		fatal(logger, "Gagal setup router", err)
+	}
 
-	r.Use(gin.Recovery())
-	r.Use(gin.Logger())
+	srv := &http.Server{
+		Addr:              cfg.Server.Addr,
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		logger.Info("Server started", "addr", cfg.Server.Addr, "env", cfg.Env)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal(logger, "Server error", err)
+		}
+	}()
+
+	// METRICS_ADDR diisi -> /metrics di port sendiri (misal cuma kebuka di jaringan internal)
+	var metricsSrv *http.Server
+	if cfg.Metrics.Enabled && cfg.Metrics.Addr != "" {
+		mr := gin.New()
+		mr.Use(middlewares.Recovery())
+		routes.MetricsRoutes(mr, cfg.Metrics.Token)
+		metricsSrv = &http.Server{
+			Addr:              cfg.Metrics.Addr,
+			Handler:           mr,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			logger.Info("Metrics server started", "addr", cfg.Metrics.Addr)
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				fatal(logger, "Metrics server error", err)
+			}
+		}()
+	}
 
-	routes.SetupRoutes(db, r)
+	// Tunggu SIGINT/SIGTERM, terus drain: request yang lagi jalan (misal update saldo) dikasih waktu selesai
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	<-ctx.Done()
+	stop()
+	logger.Info("Shutting down, nunggu request yang lagi jalan", "timeout", cfg.Server.ShutdownTimeout.Std().String())
+
+	background.BeginShutdown()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.Std())
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Warn("Shutdown belum bersih", "error", err)
+	}
+	background.Stop(shutdownCtx)
+	if metricsSrv != nil {
+		metricsSrv.Shutdown(shutdownCtx)
+	}
+
+	// Flush span yang masih di buffer exporter
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Warn("Gagal flush tracing", "error", err)
+	}
+
+	if db != nil {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}
+	logger.Info("Server stopped")
+}
+
+// setupDatabase = migration (kalau MIGRATE_ON_START) + plugin metrics & tracing GORM.
+func setupDatabase(db *gorm.DB, cfg *config.Config) error {
+	if err := ensureMigrated(db, cfg.Database.MigrateOnStart); err != nil {
+		return fmt.Errorf("migration: %w", err)
+	}
+
+	// Metric query & connection pool (di-scrape lewat /metrics)
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		return fmt.Errorf("pasang metrics GORM: %w", err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		metrics.RegisterDBStats(metrics.Default, sqlDB)
+	}
+	if tracing.Enabled(cfg.Tracing.Exporter) {
+		if err := db.Use(tracing.GormPlugin{}); err != nil {
+			return fmt.Errorf("pasang tracing GORM: %w", err)
+		}
+	}
+	return nil
+}
 
-	r.Run(os.Getenv("APP_PORT"))
-}
\ No newline at end of file
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const startKey = "metrics:start"
+
+// GormPlugin nyatet durasi tiap query ke db_query_duration_seconds lewat callback GORM.
+// Parameter query gak pernah ikut, label cuma operasi & nama tabel.
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string { return "metrics" }
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("metrics:before_create", startTimer),
+		cb.Create().After("gorm:create").Register("metrics:after_create", observeQuery("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", startTimer),
+		cb.Query().After("gorm:query").Register("metrics:after_query", observeQuery("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", startTimer),
+		cb.Update().After("gorm:update").Register("metrics:after_update", observeQuery("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", startTimer),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", observeQuery("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", startTimer),
+		cb.Row().After("gorm:row").Register("metrics:after_row", observeQuery("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", startTimer),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", observeQuery("raw")),
+	)
+}
+
+func startTimer(tx *gorm.DB) {
+	tx.InstanceSet(startKey, time.Now())
+}
+
+func observeQuery(operation string) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		v, ok := tx.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := tx.Statement.Table
+		if table == "" {
+			table = "unknown" // raw SQL, nama tabel gak diketahui
+		}
+		DBQueryDuration.Observe(time.Since(start).Seconds(), operation, table)
+		if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			DBQueryErrorsTotal.Inc(operation, table)
+		}
+	}
+}
+
+// RegisterDBStats daftarin gauge connection pool; nilainya dibaca dari sqlDB.Stats() pas scrape.
+func RegisterDBStats(r *Registry, sqlDB *sql.DB) {
+	r.NewGaugeFunc("db_connections_max_open", "Batas maksimal koneksi DB (0 = unlimited).", func() float64 {
+		return float64(sqlDB.Stats().MaxOpenConnections)
+	})
+	r.NewGaugeFunc("db_connections_open", "Jumlah koneksi DB yang kebuka (in use + idle).", func() float64 {
+		return float64(sqlDB.Stats().OpenConnections)
+	})
+	r.NewGaugeFunc("db_connections_in_use", "Jumlah koneksi DB yang lagi dipakai.", func() float64 {
+		return float64(sqlDB.Stats().InUse)
+	})
+	r.NewGaugeFunc("db_connections_idle", "Jumlah koneksi DB yang idle.", func() float64 {
+		return float64(sqlDB.Stats().Idle)
+	})
+	r.NewCounterFunc("db_connections_wait_total", "Total nunggu koneksi karena pool penuh.", func() float64 {
+		return float64(sqlDB.Stats().WaitCount)
+	})
+	r.NewCounterFunc("db_connections_wait_seconds_total", "Total waktu nunggu koneksi dari pool.", func() float64 {
+		return sqlDB.Stats().WaitDuration.Seconds()
+	})
+	r.NewCounterFunc("db_connections_closed_max_lifetime_total", "Koneksi yang ditutup karena lewat conn_max_lifetime.", func() float64 {
+		return float64(sqlDB.Stats().MaxLifetimeClosed)
+	})
+}
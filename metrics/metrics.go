@@ -0,0 +1,40 @@
+package metrics
+
+import "runtime"
+
+// Default = registry yang di-expose di /metrics. Semua metric aplikasi didaftarin di sini.
+var Default = NewRegistry()
+
+// HTTP
+var (
+	HTTPRequestsTotal = Default.NewCounterVec("http_requests_total",
+		"Jumlah request HTTP per route & status.", "method", "route", "status")
+	HTTPRequestDuration = Default.NewHistogramVec("http_request_duration_seconds",
+		"Durasi request HTTP per route.", DefaultBuckets, "method", "route")
+)
+
+// Database
+var (
+	DBQueryDuration = Default.NewHistogramVec("db_query_duration_seconds",
+		"Durasi query GORM per operasi & tabel.", DefaultBuckets, "operation", "table")
+	DBQueryErrorsTotal = Default.NewCounterVec("db_query_errors_total",
+		"Jumlah query GORM yang error (selain record not found).", "operation", "table")
+)
+
+// Bisnis
+var (
+	TransactionsCreatedTotal = Default.NewCounterVec("cashflow_transactions_created_total",
+		"Jumlah transaksi yang berhasil dibuat, per sumber (single|bulk|import).", "source")
+	FailedLoginsTotal = Default.NewCounterVec("cashflow_failed_logins_total",
+		"Jumlah login gagal, per tahap (password|two_factor|locked).", "stage")
+	BalanceReconciliationMismatchesTotal = Default.NewCounterVec("cashflow_balance_reconciliation_mismatches_total",
+		"Jumlah wallet yang saldonya beda dengan total transaksinya, dihitung tiap kali reconcile jalan.")
+	BalanceReconciliationMismatchedWallets = Default.NewGaugeVec("cashflow_balance_reconciliation_mismatched_wallets",
+		"Jumlah wallet yang saldonya gak cocok di hasil reconcile terakhir.")
+)
+
+func init() {
+	Default.NewGaugeFunc("go_goroutines", "Jumlah goroutine yang lagi jalan.", func() float64 {
+		return float64(runtime.NumGoroutine())
+	})
+}
@@ -0,0 +1,325 @@
+// Package metrics = registry metric kecil yang ngeluarin format text Prometheus (exposition 0.0.4).
+//
+// Sengaja gak pakai client_golang: yang dibutuhin cuma counter, gauge & histogram berlabel,
+// dan semuanya di-scrape dari satu endpoint /metrics.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Bucket default buat durasi (detik), dari 5ms sampai 10s.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type collector interface {
+	name() string
+	write(buf *bytes.Buffer)
+}
+
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.collectors {
+		if existing.name() == c.name() {
+			panic(fmt.Sprintf("metrics: %s udah ke-register", c.name()))
+		}
+	}
+	r.collectors = append(r.collectors, c)
+}
+
+// Handler ngeluarin semua metric, urut nama biar output stabil.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write(r.Gather())
+	})
+}
+
+func (r *Registry) Gather() []byte {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].name() < collectors[j].name() })
+
+	var buf bytes.Buffer
+	for _, c := range collectors {
+		c.write(&buf)
+	}
+	return buf.Bytes()
+}
+
+// ===== Counter =====
+
+type CounterVec struct {
+	metricName string
+	help       string
+	labels     []string
+
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{metricName: name, help: help, labels: labels, series: make(map[string]*counterSeries)}
+	r.register(c)
+	return c
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add nambah counter. Nilai negatif diabaikan (counter cuma boleh naik).
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	checkLabels(c.metricName, c.labels, labelValues)
+	if v < 0 {
+		return
+	}
+	key := seriesKey(labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.series[key]
+	if !ok {
+		s = &counterSeries{labelValues: append([]string(nil), labelValues...)}
+		c.series[key] = s
+	}
+	s.value += v
+}
+
+func (c *CounterVec) name() string { return c.metricName }
+
+func (c *CounterVec) write(buf *bytes.Buffer) {
+	writeHeader(buf, c.metricName, c.help, "counter")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		writeSample(buf, c.metricName, c.labels, s.labelValues, "", "", s.value)
+	}
+}
+
+// ===== Gauge / counter yang nilainya dibaca pas scrape =====
+
+type funcMetric struct {
+	metricName string
+	help       string
+	kind       string
+	fn         func() float64
+}
+
+// NewGaugeFunc: nilai diambil dari fn tiap kali /metrics di-scrape (misal sqlDB.Stats()).
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) {
+	r.register(&funcMetric{metricName: name, help: help, kind: "gauge", fn: fn})
+}
+
+// NewCounterFunc sama kayak NewGaugeFunc, buat nilai kumulatif yang dihitung di tempat lain.
+func (r *Registry) NewCounterFunc(name, help string, fn func() float64) {
+	r.register(&funcMetric{metricName: name, help: help, kind: "counter", fn: fn})
+}
+
+func (f *funcMetric) name() string { return f.metricName }
+
+func (f *funcMetric) write(buf *bytes.Buffer) {
+	writeHeader(buf, f.metricName, f.help, f.kind)
+	writeSample(buf, f.metricName, nil, nil, "", "", f.fn())
+}
+
+// ===== Gauge =====
+
+type GaugeVec struct {
+	metricName string
+	help       string
+	labels     []string
+
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{metricName: name, help: help, labels: labels, series: make(map[string]*counterSeries)}
+	r.register(g)
+	return g
+}
+
+func (g *GaugeVec) Set(v float64, labelValues ...string) {
+	checkLabels(g.metricName, g.labels, labelValues)
+	key := seriesKey(labelValues)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	s, ok := g.series[key]
+	if !ok {
+		s = &counterSeries{labelValues: append([]string(nil), labelValues...)}
+		g.series[key] = s
+	}
+	s.value = v
+}
+
+func (g *GaugeVec) name() string { return g.metricName }
+
+func (g *GaugeVec) write(buf *bytes.Buffer) {
+	writeHeader(buf, g.metricName, g.help, "gauge")
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, key := range sortedKeys(g.series) {
+		s := g.series[key]
+		writeSample(buf, g.metricName, g.labels, s.labelValues, "", "", s.value)
+	}
+}
+
+// ===== Histogram =====
+
+type HistogramVec struct {
+	metricName string
+	help       string
+	labels     []string
+	buckets    []float64
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64 // per bucket (belum kumulatif)
+	sum         float64
+	count       uint64
+}
+
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+	h := &HistogramVec{metricName: name, help: help, labels: labels, buckets: b, series: make(map[string]*histogramSeries)}
+	r.register(h)
+	return h
+}
+
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	checkLabels(h.metricName, h.labels, labelValues)
+	key := seriesKey(labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{labelValues: append([]string(nil), labelValues...), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.sum += v
+	s.count++
+}
+
+func (h *HistogramVec) name() string { return h.metricName }
+
+func (h *HistogramVec) write(buf *bytes.Buffer) {
+	writeHeader(buf, h.metricName, h.help, "histogram")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			writeSample(buf, h.metricName+"_bucket", h.labels, s.labelValues, "le", formatFloat(upper), float64(cumulative))
+		}
+		writeSample(buf, h.metricName+"_bucket", h.labels, s.labelValues, "le", "+Inf", float64(s.count))
+		writeSample(buf, h.metricName+"_sum", h.labels, s.labelValues, "", "", s.sum)
+		writeSample(buf, h.metricName+"_count", h.labels, s.labelValues, "", "", float64(s.count))
+	}
+}
+
+// ===== Helpers =====
+
+// Jumlah label salah = bug di kode pemanggil, sama kayak client_golang langsung panic.
+func checkLabels(name string, labels, values []string) {
+	if len(labels) != len(values) {
+		panic(fmt.Sprintf("metrics: %s butuh %d label, dikasih %d", name, len(labels), len(values)))
+	}
+}
+
+func seriesKey(labelValues []string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeHeader(buf *bytes.Buffer, name, help, kind string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help))
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, kind)
+}
+
+func writeSample(buf *bytes.Buffer, name string, labels, values []string, extraLabel, extraValue string, v float64) {
+	buf.WriteString(name)
+	if len(labels) > 0 || extraLabel != "" {
+		buf.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			fmt.Fprintf(buf, `%s="%s"`, l, escapeLabel(values[i]))
+		}
+		if extraLabel != "" {
+			if len(labels) > 0 {
+				buf.WriteByte(',')
+			}
+			fmt.Fprintf(buf, `%s="%s"`, extraLabel, extraValue)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(formatFloat(v))
+	buf.WriteByte('\n')
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -1,51 +1,138 @@
 package middlewares
 
 import (
-	"cashflow_gin/dto/response"
-	"net/http"
-	"os"
+	"cashflow_gin/apperror"
+	"cashflow_gin/models"
+	"context"
+	"reflect"
+	"runtime"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
-    return func(c *gin.Context) {
-        authHeader := c.GetHeader("Authorization")
-        if !strings.Contains(authHeader, "Bearer") {
-            c.AbortWithStatusJSON(http.StatusUnauthorized, response.BaseResponse{
-                Status:  false,
-                Message: "Unauthorized: No Bearer token | Please login first",
-                Errors:  "Missing or invalid Authorization header",
-                Data:    nil,
-            })
-            return
-        }
-
-        tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-        
-        // token, err := jwt.Parse(tokenString, func(t *jwt.SimpleClaims) (interface{}, error) {
-		// 	return []byte(os.Getenv("JWT_SECRET")), nil
-        // })
+// APIKeyAuthenticator diimplement APIKeyService, dipisah jadi interface biar middleware gak tergantung package services.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (*models.APIKey, error)
+}
+
+// requireScopeHandler = nama handler hasil RequireScope (sama buat semua scope), buat dicari di chain route.
+var requireScopeHandler = runtime.FuncForPC(reflect.ValueOf(RequireScope("")).Pointer()).Name()
+
+// routeRequiresScope: true kalau chain handler route ini ada RequireScope-nya.
+func routeRequiresScope(c *gin.Context) bool {
+	for _, name := range c.HandlerNames() {
+		if name == requireScopeHandler {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthMiddleware nerima JWT dari /auth/login ATAU API key (header "Authorization: Bearer cfk_..." / "X-API-Key").
+// Request via API key cuma boleh lewat route yang pasang RequireScope: route yang lupa pasang scope
+// otomatis nolak API key (default deny), bukan malah ngasih akses penuh.
+func AuthMiddleware(apiKeys APIKeyAuthenticator, jwtSecret string) gin.HandlerFunc {
+	secret := []byte(jwtSecret)
+
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		tokenString := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+			tokenString = apiKey
+		}
+
+		if strings.HasPrefix(tokenString, models.APIKeyPrefix) {
+			key, err := apiKeys.AuthenticateAPIKey(c.Request.Context(), tokenString)
+			if err != nil {
+				AbortWithError(c, err)
+				return
+			}
+			if !routeRequiresScope(c) {
+				AbortWithError(c, errUserSessionRequired)
+				return
+			}
+
+			// Samain tipe sama claim JWT (user_role hasil decode JSON = float64)
+			c.Set("user_id", key.UserID.String())
+			c.Set("user_role", float64(key.User.UserRole))
+			c.Set("auth_method", "api_key")
+			c.Set("api_key_scopes", key.ScopeList())
+			withLogAttrs(c, "user_id", key.UserID.String(), "auth_method", "api_key")
+			c.Next()
+			return
+		}
+
+		if !strings.Contains(authHeader, "Bearer") {
+			AbortWithError(c, apperror.Unauthorized("missing_token", "Unauthorized: No Bearer token | Please login first"))
+			return
+		}
 
 		token, _ := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-    		return []byte(os.Getenv("JWT_SECRET")), nil
-		})
-
-        if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-            // Simpan UserID ke context biar bisa dipake Controller
-            c.Set("user_id", claims["user_id"])
-            c.Set("user_role", claims["user_role"])
-            c.Next()
-        } else {
-            c.AbortWithStatusJSON(http.StatusUnauthorized, response.BaseResponse{
-                Status:  false,
-                Message: "Unauthorized: Invalid token | Please login again",
-                Errors:  "Token Expired or Invalid",
-                Data:    nil,
-            })
-            return
-        }
-    }
-}
\ No newline at end of file
+			return secret, nil
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+		// Token yang punya "scope" (misal challenge token 2FA) bukan access token, tolak.
+		if token != nil && token.Valid {
+			if claims, ok := token.Claims.(jwt.MapClaims); ok && claims["scope"] == nil {
+				// Simpan UserID ke context biar bisa dipake Controller
+				c.Set("user_id", claims["user_id"])
+				c.Set("user_role", claims["user_role"])
+				c.Set("auth_method", "jwt")
+				withLogAttrs(c, "user_id", claims["user_id"], "auth_method", "jwt")
+				c.Next()
+				return
+			}
+		}
+
+		AbortWithError(c, apperror.Unauthorized("invalid_token", "Unauthorized: Invalid token | Please login again"))
+	}
+}
+
+// RequireScope wajib dipasang setelah AuthMiddleware. Login pakai JWT dianggap punya semua scope,
+// API key harus punya scope yang diminta.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("auth_method") != "api_key" {
+			c.Next()
+			return
+		}
+
+		for _, s := range c.GetStringSlice("api_key_scopes") {
+			if s == scope {
+				c.Next()
+				return
+			}
+		}
+
+		AbortWithError(c, apperror.Forbidden("missing_scope", "Forbidden: API key does not have the required scope").
+			WithDetails(gin.H{"scope": scope}))
+	}
+}
+
+var errUserSessionRequired = apperror.Forbidden("user_session_required", "Forbidden: this endpoint requires a user session")
+
+// RequireJWT nolak API key. Dipakai di route sensitif (kelola akun, group, API key itu sendiri).
+func RequireJWT() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("auth_method") == "api_key" {
+			AbortWithError(c, errUserSessionRequired)
+			return
+		}
+		c.Next()
+	}
+}
+
+// TokenFromQuery mindahin token dari query param ke header Authorization kalau header-nya kosong.
+// Cuma dipasang di route yang memang butuh (SSE), karena token di URL bisa ke-log.
+func TokenFromQuery(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query(param); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package middlewares
+
+import (
+	"cashflow_gin/models"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type fakeAPIKeys struct{ scopes string }
+
+func (f fakeAPIKeys) AuthenticateAPIKey(ctx context.Context, rawKey string) (*models.APIKey, error) {
+	return &models.APIKey{UserID: uuid.New(), Scopes: f.scopes}, nil
+}
+
+// API key cuma lolos di route yang pasang RequireScope, route tanpa scope nolak (default deny).
+func TestAuthMiddlewareAPIKeyNeedsScopedRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler())
+	auth := AuthMiddleware(fakeAPIKeys{scopes: models.ScopeTransactionsRead}, "secret")
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/scoped", auth, RequireScope(models.ScopeTransactionsRead), ok)
+	r.GET("/other-scope", auth, RequireScope(models.ScopeReportsRead), ok)
+	r.GET("/unscoped", auth, ok)
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/scoped", http.StatusOK},
+		{"/other-scope", http.StatusForbidden},
+		{"/unscoped", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("X-API-Key", models.APIKeyPrefix+"test")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("GET %s: status %d, want %d", tt.path, w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORS jawab preflight & pasang header Access-Control-* buat origin yang diizinkan.
+// allowedOrigins kosong = CORS mati (gak ada header, browser dari origin lain ditolak).
+func CORS(allowedOrigins []string, allowCredentials bool, maxAge time.Duration) gin.HandlerFunc {
+	allowAll := false
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, o := range allowedOrigins {
+		if o == "*" {
+			allowAll = true
+		}
+		allowed[strings.TrimRight(o, "/")] = true
+	}
+
+	allowHeaders := strings.Join([]string{"Authorization", "Content-Type", "Accept-Language", "X-API-Key", IdempotencyKeyHeader, "If-Match", RequestIDHeader}, ", ")
+	exposeHeaders := strings.Join([]string{"ETag", "Retry-After", "Idempotent-Replayed", RequestIDHeader}, ", ")
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" || (!allowAll && !allowed[origin]) {
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		h.Add("Vary", "Origin")
+		if allowAll {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if allowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+		h.Set("Access-Control-Expose-Headers", exposeHeaders)
+
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			h.Set("Access-Control-Allow-Headers", allowHeaders)
+			h.Set("Access-Control-Max-Age", strconv.Itoa(int(maxAge.Seconds())))
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
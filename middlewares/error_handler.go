@@ -0,0 +1,80 @@
+package middlewares
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/logging"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler = satu-satunya tempat error jadi response. Handler cukup `ctx.Error(err); return`,
+// middleware ini yang milih status dari Kind apperror & ngirim BaseResponse yang bentuknya selalu sama:
+//
+//	{"status": false, "message": "Transaction not found", "errors": {"code": "transaction_not_found"}}
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		writeError(c, c.Errors.Last().Err)
+	}
+}
+
+// AbortWithError dipakai middleware lain (auth, rate limit, dll) buat langsung stop chain dengan error.
+func AbortWithError(c *gin.Context, err error) {
+	c.Error(err)
+	writeError(c, err)
+}
+
+func writeError(c *gin.Context, err error) {
+	appErr := apperror.From(err)
+	status := StatusCode(appErr.Kind)
+
+	if status >= http.StatusInternalServerError {
+		// Detail error internal cuma masuk log, client cukup dapet code + request_id
+		ctx := c.Request.Context()
+		logging.FromContext(ctx).ErrorContext(ctx, "request error", "error", err.Error())
+	}
+	if appErr.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(appErr.RetryAfter.Seconds()))))
+	}
+
+	c.AbortWithStatusJSON(status, response.BaseResponse{
+		Status:  false,
+		Message: appErr.Message,
+		Errors:  response.ErrorResponse{Code: appErr.Code, Details: appErr.Details},
+	})
+}
+
+// StatusCode mapping Kind ke status HTTP. Kind gak dikenal = 500.
+func StatusCode(kind apperror.Kind) int {
+	switch kind {
+	case apperror.KindBadRequest:
+		return http.StatusBadRequest
+	case apperror.KindUnauthorized:
+		return http.StatusUnauthorized
+	case apperror.KindForbidden:
+		return http.StatusForbidden
+	case apperror.KindNotFound:
+		return http.StatusNotFound
+	case apperror.KindConflict:
+		return http.StatusConflict
+	case apperror.KindPrecondition:
+		return http.StatusPreconditionFailed
+	case apperror.KindPayloadTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case apperror.KindValidation:
+		return http.StatusUnprocessableEntity
+	case apperror.KindPreconditionReq:
+		return http.StatusPreconditionRequired
+	case apperror.KindTooManyRequests:
+		return http.StatusTooManyRequests
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"cashflow_gin/apperror"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ifMatchVersionKey = "if_match_version"
+
+// RequireIfMatch wajibin header If-Match (isinya version dari ETag response GET) di route yang ngubah data.
+// Format yang diterima: "3", W/"3", atau 3.
+func RequireIfMatch() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := strings.TrimSpace(c.GetHeader("If-Match"))
+		if header == "" {
+			AbortWithError(c, apperror.New(apperror.KindPreconditionReq, "if_match_required",
+				"If-Match header is required, ambil ETag dari response GET lalu kirim ulang sebagai If-Match"))
+			return
+		}
+
+		version, err := parseETagVersion(header)
+		if err != nil {
+			AbortWithError(c, apperror.BadRequest("invalid_if_match", "Invalid If-Match header").Wrap(err))
+			return
+		}
+
+		c.Set(ifMatchVersionKey, version)
+		c.Next()
+	}
+}
+
+// IfMatchVersion ngambil version hasil parsing RequireIfMatch.
+func IfMatchVersion(c *gin.Context) int {
+	return c.GetInt(ifMatchVersionKey)
+}
+
+// SetETag nempelin version resource ke header ETag.
+func SetETag(c *gin.Context, version int) {
+	c.Header("ETag", `"`+strconv.Itoa(version)+`"`)
+}
+
+func parseETagVersion(value string) (int, error) {
+	value = strings.TrimPrefix(value, "W/")
+	value = strings.Trim(value, `"`)
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, strconv.ErrSyntax
+	}
+	return version, nil
+}
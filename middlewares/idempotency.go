@@ -0,0 +1,220 @@
+package middlewares
+
+import (
+	"bytes"
+	"cashflow_gin/apperror"
+	"cashflow_gin/logging"
+	"cashflow_gin/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	maxIdempotencyKeyLen = 255
+	// Body dibaca penuh ke memori buat di-hash, jadi dibatasi. Paling gede upload mutasi (max 5MB) + overhead multipart
+	maxIdempotentBodySize = 6 << 20
+)
+
+// IdempotencyStore diimplement IdempotencyRepository, dipisah jadi interface biar middleware gak tergantung package repository.
+type IdempotencyStore interface {
+	Find(ctx context.Context, userID uuid.UUID, key string) (*models.IdempotencyKey, error)
+	Reserve(ctx context.Context, record *models.IdempotencyKey) (bool, error)
+	Complete(ctx context.Context, id uuid.UUID, statusCode int, contentType, body string) error
+	Release(ctx context.Context, id uuid.UUID) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Idempotency wajib dipasang setelah AuthMiddleware. Header Idempotency-Key opsional:
+//   - key baru            -> request diproses, response-nya disimpan selama ttl
+//   - key sama, body sama -> response pertama di-replay (header Idempotent-Replayed: true)
+//   - key sama, body beda -> 422
+//   - request pertama masih jalan -> 409, client retry belakangan
+//   - request pertama gak selesai dalam lease (server crash/restart) -> key diambil alih, request diproses ulang
+//
+// Response 4xx disimpan & di-replay kayak sukses, response 5xx gak disimpan, key dilepas lagi biar client bisa retry.
+func Idempotency(store IdempotencyStore, ttl, lease time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			AbortWithError(c, apperror.BadRequest("invalid_idempotency_key",
+				fmt.Sprintf("Invalid Idempotency-Key header, maksimal %d karakter", maxIdempotencyKeyLen)))
+			return
+		}
+
+		userID, err := uuid.Parse(fmt.Sprintf("%v", c.MustGet("user_id")))
+		if err != nil {
+			AbortWithError(c, apperror.ErrUnauthorized.Wrap(err))
+			return
+		}
+
+		hash, err := idempotencyRequestHash(c)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				AbortWithError(c, errIdempotencyBodyTooLarge.Wrap(err))
+				return
+			}
+			AbortWithError(c, apperror.BadRequest("invalid_input", "Invalid request body").Wrap(err))
+			return
+		}
+
+		existing, err := store.Find(c.Request.Context(), userID, key)
+		if err != nil {
+			AbortWithError(c, fmt.Errorf("failed to check Idempotency-Key: %w", err))
+			return
+		}
+		now := time.Now()
+		if existing != nil && (existing.Completed || existing.InProgress(now) || existing.RequestHash != hash) {
+			replayIdempotent(c, existing, hash)
+			return
+		}
+
+		// Key baru, atau key yang ditinggal request yang gak pernah selesai (Reserve yang buang)
+		lockedUntil := now.Add(lease)
+		record := &models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: hash,
+			LockedUntil: &lockedUntil,
+			ExpiresAt:   now.Add(ttl),
+		}
+		created, err := store.Reserve(c.Request.Context(), record)
+		if err != nil {
+			AbortWithError(c, fmt.Errorf("failed to store Idempotency-Key: %w", err))
+			return
+		}
+		if !created {
+			// Kalah balapan sama retry lain yang masuk barengan
+			AbortWithError(c, errIdempotencyInProgress)
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// Handler cuma `ctx.Error(err)`, body error-nya biasanya baru ditulis ErrorHandler di luar.
+		// Dirender di sini biar status & body error ikut ketangkep writer (ErrorHandler skip karena udah Written)
+		if len(c.Errors) > 0 && !writer.Written() {
+			writeError(c, c.Errors.Last().Err)
+		}
+
+		// Client bisa aja udah putus; hasilnya tetap harus disimpan, jadi context-nya jangan ikut ke-cancel
+		ctx := context.WithoutCancel(c.Request.Context())
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			if err := store.Release(ctx, record.ID); err != nil {
+				logging.FromContext(ctx).ErrorContext(ctx, "idempotency: gagal release key", "key", key, "error", err)
+			}
+			return
+		}
+		if err := store.Complete(ctx, record.ID, status, writer.Header().Get("Content-Type"), writer.body.String()); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "idempotency: gagal simpan response", "key", key, "error", err)
+		}
+	}
+}
+
+// StartIdempotencyJanitor bersihin key expired tiap interval. Return func buat stop.
+func StartIdempotencyJanitor(store IdempotencyStore, interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := store.DeleteExpired(context.Background(), time.Now()); err != nil {
+					slog.Error("idempotency: gagal hapus key expired", "error", err)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+var (
+	errIdempotencyInProgress   = apperror.Conflict("idempotency_in_progress", "Request with this Idempotency-Key is still in progress, tunggu sebentar lalu retry")
+	errIdempotencyKeyReused    = apperror.Validation("idempotency_key_reused", "Idempotency-Key already used with a different request, pakai key baru")
+	errIdempotencyBodyTooLarge = apperror.New(apperror.KindPayloadTooLarge, "payload_too_large",
+		fmt.Sprintf("Request body terlalu besar (max %dMB)", maxIdempotentBodySize>>20))
+)
+
+func replayIdempotent(c *gin.Context, existing *models.IdempotencyKey, hash string) {
+	if existing.RequestHash != hash {
+		AbortWithError(c, errIdempotencyKeyReused)
+		return
+	}
+	if !existing.Completed {
+		AbortWithError(c, errIdempotencyInProgress)
+		return
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	contentType := existing.ContentType
+	if contentType == "" {
+		contentType = "application/json; charset=utf-8"
+	}
+	c.Data(existing.StatusCode, contentType, []byte(existing.ResponseBody))
+	c.Abort()
+}
+
+// idempotencyRequestHash = sha256(method, path, body). Body dibalikin lagi buat handler.
+// Boundary multipart dibuang dulu karena tiap retry client bisa generate boundary baru.
+func idempotencyRequestHash(c *gin.Context) (string, error) {
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodySize))
+		if err != nil {
+			return "", err
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	hashed := body
+	if mediaType, params, err := mime.ParseMediaType(c.GetHeader("Content-Type")); err == nil &&
+		mediaType == "multipart/form-data" && params["boundary"] != "" {
+		hashed = bytes.ReplaceAll(body, []byte(params["boundary"]), nil)
+	}
+
+	h := sha256.New()
+	h.Write([]byte(c.Request.Method + "\n" + c.Request.URL.Path + "\n"))
+	h.Write(hashed)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// idempotencyWriter nyalin body response biar bisa disimpan buat replay.
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/metrics"
+	"crypto/subtle"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics catat jumlah & durasi request per route. Label route pakai pola (/api/wallets/:id),
+// bukan path asli, biar jumlah series gak meledak.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched" // 404, path asli sengaja gak dipakai
+		}
+		method := c.Request.Method
+		metrics.HTTPRequestsTotal.Inc(method, route, strconv.Itoa(c.Writer.Status()))
+		metrics.HTTPRequestDuration.Observe(time.Since(start).Seconds(), method, route)
+	}
+}
+
+// MetricsAuth: token kosong = gak dicek (misal /metrics cuma di listener internal).
+// Kalau diisi, Prometheus harus kirim "Authorization: Bearer <token>".
+func MetricsAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		given := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			AbortWithError(c, apperror.Unauthorized("invalid_metrics_token", "Unauthorized: Invalid metrics token"))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,94 @@
+package middlewares
+
+import (
+	"bytes"
+	"cashflow_gin/apperror"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitRule = satu aturan limit per route, misal "login per email: 5x / menit".
+// KeyFunc return "" kalau request ini gak relevan buat rule tsb (rule di-skip).
+type RateLimitRule struct {
+	Name    string
+	Limit   int64
+	Window  time.Duration
+	KeyFunc func(ctx *gin.Context) string
+}
+
+// RateLimit nge-cek semua rule secara berurutan, rule pertama yang kelewat limit langsung 429.
+// Kalau store-nya error (misal Redis mati) request tetap lolos (fail-open) biar login gak ikut down.
+func RateLimit(store RateLimitStore, rules ...RateLimitRule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, rule := range rules {
+			key := rule.KeyFunc(c)
+			if key == "" {
+				continue
+			}
+
+			count, resetIn, err := store.Hit(c.Request.Context(), "ratelimit:"+rule.Name+":"+key, rule.Window)
+			if err != nil {
+				continue
+			}
+
+			remaining := rule.Limit - count
+			if remaining < 0 {
+				remaining = 0
+			}
+			c.Header("X-RateLimit-Limit", strconv.FormatInt(rule.Limit, 10))
+			c.Header("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+
+			if count > rule.Limit {
+				retryAfter := int64(math.Ceil(resetIn.Seconds()))
+				if retryAfter < 1 {
+					retryAfter = 1
+				}
+				AbortWithError(c, apperror.TooManyRequests("rate_limited", "Too many requests, please try again later").
+					WithDetails(gin.H{"rule": rule.Name}).
+					WithRetryAfter(time.Duration(retryAfter)*time.Second))
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// KeyByIP pakai IP client sebagai key. X-Forwarded-For cuma dipakai kalau dari trusted proxy
+// (TRUSTED_PROXIES), jadi client gak bisa ngarang IP sendiri.
+func KeyByIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// maxKeyBodySize = batas body yang dibaca KeyByEmail. Route auth gak butuh body gede,
+// dan ini endpoint tanpa login jadi jangan sampai bisa disuruh nampung body berapa pun.
+const maxKeyBodySize = 8 << 10
+
+// KeyByEmail ambil field "email" dari body JSON tanpa ngerusak body buat handler selanjutnya.
+// Body kegedean = gak ada key, dan binding di controller ikut gagal (reader-nya udah error).
+func KeyByEmail(c *gin.Context) string {
+	if c.Request.Body == nil {
+		return ""
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxKeyBodySize)
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return ""
+	}
+	// Balikin body biar ShouldBindJSON di controller masih bisa baca
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var payload struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(payload.Email))
+}
@@ -0,0 +1,107 @@
+package middlewares
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimitStore = backend counter rate limit. Implementasi: in-memory (1 instance) & Redis (multi instance).
+type RateLimitStore interface {
+	// Hit nambah counter key di window sekarang (fixed window),
+	// return jumlah hit sejauh ini & sisa waktu sampai window di-reset.
+	Hit(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error)
+}
+
+// NewRateLimitStore pakai Redis kalau addr diisi, kalau gak fallback ke memory.
+func NewRateLimitStore(addr, password string) RateLimitStore {
+	if addr == "" {
+		return NewMemoryRateLimitStore()
+	}
+	return NewRedisRateLimitStore(redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+	}))
+}
+
+// --- IN-MEMORY ---
+
+type memoryCounter struct {
+	count     int64
+	expiresAt time.Time
+}
+
+type memoryRateLimitStore struct {
+	mu       sync.Mutex
+	counters map[string]*memoryCounter
+	lastGC   time.Time
+}
+
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &memoryRateLimitStore{counters: make(map[string]*memoryCounter), lastGC: time.Now()}
+}
+
+func (s *memoryRateLimitStore) Hit(_ context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.gc(now)
+
+	counter, ok := s.counters[key]
+	if !ok || now.After(counter.expiresAt) {
+		counter = &memoryCounter{expiresAt: now.Add(window)}
+		s.counters[key] = counter
+	}
+	counter.count++
+
+	return counter.count, counter.expiresAt.Sub(now), nil
+}
+
+// gc buang counter yang udah expired, maksimal sekali per menit biar map gak bengkak.
+func (s *memoryRateLimitStore) gc(now time.Time) {
+	if now.Sub(s.lastGC) < time.Minute {
+		return
+	}
+	for key, counter := range s.counters {
+		if now.After(counter.expiresAt) {
+			delete(s.counters, key)
+		}
+	}
+	s.lastGC = now
+}
+
+// --- REDIS ---
+
+// hitScript: INCR, pasang expire cuma pas key baru dibuat, balikin [count, pttl].
+// Pakai Lua biar atomic & tetap jalan di server Redis-compatible yang belum support EXPIRE NX.
+var hitScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {count, redis.call("PTTL", KEYS[1])}
+`)
+
+type redisRateLimitStore struct {
+	client redis.UniversalClient
+}
+
+func NewRedisRateLimitStore(client redis.UniversalClient) RateLimitStore {
+	return &redisRateLimitStore{client: client}
+}
+
+func (s *redisRateLimitStore) Hit(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	res, err := hitScript.Run(ctx, s.client, []string{key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	resetIn := time.Duration(res[1]) * time.Millisecond
+	if resetIn < 0 {
+		resetIn = window
+	}
+	return res[0], resetIn, nil
+}
@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"cashflow_gin/logging"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIDHeader  = "X-Request-ID"
+	maxRequestIDLen  = 128
+	requestIDContext = "request_id"
+)
+
+// RequestID pakai X-Request-ID dari client / proxy kalau ada (dan wajar), kalau gak generate UUID.
+// ID-nya dibalikin di response header & dipasang ke logger request di context,
+// jadi semua log controller/service/repository (termasuk query GORM) bisa dirunut per request.
+func RequestID(base *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(requestIDContext, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		logger := base.With("request_id", requestID)
+		c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), logger))
+		c.Next()
+	}
+}
+
+// validRequestID: ID dari luar cuma diterima kalau pendek & isinya karakter aman (biar gak bisa nyuntik log).
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' || r == ':') {
+			return false
+		}
+	}
+	return true
+}
+
+// withLogAttrs nambah attr ke logger request (misal user_id setelah auth).
+func withLogAttrs(c *gin.Context, args ...any) {
+	ctx := c.Request.Context()
+	c.Request = c.Request.WithContext(logging.WithLogger(ctx, logging.FromContext(ctx).With(args...)))
+}
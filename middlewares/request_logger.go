@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/logging"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLogger = pengganti gin.Logger(): satu log JSON per request, query sensitif (access_token) di-redact.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		case c.Request.URL.Path == "/healthz" || c.Request.URL.Path == "/readyz":
+			level = slog.LevelDebug // probe tiap beberapa detik, jangan menuhin log
+		}
+
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"duration_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"client_ip", c.ClientIP(),
+			"bytes", c.Writer.Size(),
+		}
+		if query := logging.RedactQuery(c.Request.URL.RawQuery); query != "" {
+			attrs = append(attrs, "query", query)
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.String())
+		}
+
+		ctx := c.Request.Context()
+		logging.FromContext(ctx).Log(ctx, level, "request", attrs...)
+	}
+}
+
+// Recovery = gin.Recovery() versi slog: panic di-log lengkap sama request_id, client dapet 500 format BaseResponse.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		ctx := c.Request.Context()
+		logging.FromContext(ctx).ErrorContext(ctx, "panic recovered",
+			"panic", recovered,
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"stack", string(debug.Stack()),
+		)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.BaseResponse{
+			Status:  false,
+			Message: "Internal server error",
+			Errors:  response.ErrorResponse{Code: apperror.ErrInternal.Code},
+		})
+	})
+}
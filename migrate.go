@@ -0,0 +1,107 @@
+package main
+
+import (
+	"cashflow_gin/migrations"
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const migrateUsage = `Usage: cashflow migrate <command>
+
+Commands:
+  up          jalanin semua migration yang belum ke-apply
+  down [N]    rollback N migration terakhir (default 1)
+  status      lihat migration mana yang udah / belum ke-apply`
+
+// runMigrate = subcommand `migrate up|down|status`.
+func runMigrate(db *gorm.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("command migrate kosong\n\n%s", migrateUsage)
+	}
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied  %06d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("Skema udah up to date")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("jumlah step %q gak valid", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %06d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Println("Gak ada migration yang bisa di-rollback")
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%06d_%-40s %s\n", s.Version, s.Name, applied)
+		}
+	default:
+		return fmt.Errorf("command migrate %q gak dikenal\n\n%s", args[0], migrateUsage)
+	}
+	return nil
+}
+
+// ensureMigrated dipanggil pas server start. Kalau ada migration pending: migrateOnStart (MIGRATE_ON_START) -> langsung
+// di-apply (aman walau banyak replica, ada advisory lock), selain itu server nolak jalan.
+func ensureMigrated(db *gorm.DB, migrateOnStart bool) error {
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if !migrateOnStart {
+		return fmt.Errorf("ada %d migration pending (terbaru %06d_%s), jalanin `cashflow migrate up` dulu atau set MIGRATE_ON_START=true",
+			len(pending), pending[len(pending)-1].Version, pending[len(pending)-1].Name)
+	}
+
+	applied, err := migrator.Up(ctx)
+	for _, m := range applied {
+		slog.Info("migration applied", "version", m.Version, "name", m.Name)
+	}
+	return err
+}
@@ -0,0 +1,319 @@
+// Package migrations nyimpen migration SQL berversi (di-embed ke binary) + runner-nya.
+//
+// Format nama file: <versi>_<nama>.up.sql dan <versi>_<nama>.down.sql, misal 000002_add_budgets.up.sql.
+// Versi harus unik & naik terus. Tiap migration jalan di dalam satu DB transaction, jadi kalau
+// gagal di tengah jalan skemanya balik lagi kayak sebelum migration itu.
+//
+// Tiap migration ditulis per database: postgres/ dan sqlite/ (versi & nama harus sama di dua folder).
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// Key pg_advisory_lock ("cash" dalam hex), sama di semua instance biar cuma satu yang migrate dalam satu waktu.
+const advisoryLockKey int64 = 0x63617368
+
+var createTableSQL = map[string]string{
+	"postgres": `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version    bigint PRIMARY KEY,
+	name       varchar(255) NOT NULL,
+	applied_at timestamptz NOT NULL DEFAULT now()
+)`,
+	"sqlite": `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version    bigint PRIMARY KEY,
+	name       varchar(255) NOT NULL,
+	applied_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`,
+}
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status satu migration. AppliedAt nil = belum dijalanin.
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	dialect    string // nama dialector GORM: postgres | sqlite
+	migrations []Migration
+}
+
+func New(db *gorm.DB) (*Migrator, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	dialect := db.Dialector.Name()
+	migrations, err := loadDialect(dialect)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: sqlDB, dialect: dialect, migrations: migrations}, nil
+}
+
+// Versions = versi semua migration yang di-embed buat dialect ini (nama dialector GORM), urut naik.
+// Cuma baca file embed, gak nyentuh database (dipakai health check yang gak boleh jalanin DDL).
+func Versions(dialect string) ([]int64, error) {
+	migrations, err := loadDialect(dialect)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]int64, len(migrations))
+	for i, mig := range migrations {
+		versions[i] = mig.Version
+	}
+	return versions, nil
+}
+
+func loadDialect(dialect string) ([]Migration, error) {
+	if _, ok := createTableSQL[dialect]; !ok {
+		return nil, fmt.Errorf("migration buat database %q belum ada", dialect)
+	}
+	fsys, err := fs.Sub(files, dialect)
+	if err != nil {
+		return nil, err
+	}
+	return load(fsys)
+}
+
+// Up jalanin semua migration yang belum ke-apply, urut dari versi terkecil.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			if err := run(ctx, conn, mig.Up, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", mig.Version, mig.Name); err != nil {
+				return fmt.Errorf("migration %06d_%s up: %w", mig.Version, mig.Name, err)
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rollback `steps` migration terakhir yang udah ke-apply.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, errors.New("steps minimal 1")
+	}
+
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := done[mig.Version]; !ok {
+				continue
+			}
+			if err := run(ctx, conn, mig.Down, "DELETE FROM schema_migrations WHERE version = $1", mig.Version); err != nil {
+				return fmt.Errorf("migration %06d_%s down: %w", mig.Version, mig.Name, err)
+			}
+			reverted = append(reverted, mig)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status semua migration yang ada di binary, urut versi.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	done, err := m.appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if at, ok := done[mig.Version]; ok {
+			s.AppliedAt = &at
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// Pending = migration yang belum dijalanin. Dipakai pas server start buat ngecek skema udah up to date.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for i, s := range statuses {
+		if s.AppliedAt == nil {
+			pending = append(pending, m.migrations[i])
+		}
+	}
+	return pending, nil
+}
+
+// withLock pegang advisory lock di satu koneksi khusus (lock-nya nempel ke session postgres,
+// jadi gak boleh lewat pool biasa). Instance lain yang migrate barengan nunggu di sini.
+// SQLite gak punya advisory lock, tapi emang cuma satu proses yang pegang file DB-nya.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if m.dialect != "postgres" {
+		return fn(conn)
+	}
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryLockKey); err != nil {
+		return fmt.Errorf("gagal ambil advisory lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", advisoryLockKey)
+
+	return fn(conn)
+}
+
+// run jalanin script migration + update schema_migrations dalam satu transaction.
+func run(ctx context.Context, conn *sql.Conn, script, bookkeeping string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Tanpa argumen -> pgx pakai simple protocol, jadi satu file boleh isi banyak statement
+	if strings.TrimSpace(script) != "" {
+		if _, err := tx.ExecContext(ctx, script); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	if _, err := conn.ExecContext(ctx, createTableSQL[m.dialect]); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	done := make(map[int64]time.Time)
+	for rows.Next() {
+		var (
+			version   int64
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		done[version] = appliedAt
+	}
+	return done, rows.Err()
+}
+
+// load baca pasangan file up/down dari fsys. Migration tanpa file down tetap boleh (down-nya no-op).
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s: nama file harus diakhiri .up.sql / .down.sql", name)
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		versionStr, migName, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: format nama harus <versi>_<nama>", name)
+		}
+		version, err := strconv.ParseInt(versionStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: versi %q bukan angka", name, versionStr)
+		}
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		mig, exists := byVersion[version]
+		if !exists {
+			mig = &Migration{Version: version, Name: migName}
+			byVersion[version] = mig
+		} else if mig.Name != migName {
+			return nil, fmt.Errorf("migration versi %d dipakai dua nama: %s & %s", version, mig.Name, migName)
+		}
+		if direction == "up" {
+			mig.Up = string(content)
+		} else {
+			mig.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %06d_%s gak punya file .up.sql", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
@@ -0,0 +1,21 @@
+-- Hapus SEMUA tabel aplikasi. Hati-hati, datanya ikut hilang.
+DROP TABLE IF EXISTS
+    idempotency_keys,
+    category_rules,
+    statement_import_rows,
+    statement_imports,
+    notification_preferences,
+    notifications,
+    webhook_deliveries,
+    webhook_subscriptions,
+    api_keys,
+    recovery_codes,
+    transaction_lines,
+    transactions,
+    payee_aliases,
+    payees,
+    wallets,
+    categories,
+    group_members,
+    groups,
+    users;
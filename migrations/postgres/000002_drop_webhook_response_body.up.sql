@@ -0,0 +1,2 @@
+-- Body response webhook gak disimpen lagi (isinya dari server luar & sempat ikut dibalikin ke user)
+ALTER TABLE webhook_deliveries DROP COLUMN response_body;
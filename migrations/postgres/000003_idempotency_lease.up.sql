@@ -0,0 +1,2 @@
+-- Batas waktu request pertama dianggap masih jalan. Lewat ini & belum completed = prosesnya mati, key boleh diambil alih
+ALTER TABLE idempotency_keys ADD COLUMN locked_until timestamptz;
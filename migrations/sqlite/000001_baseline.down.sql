@@ -0,0 +1,21 @@
+-- Hapus SEMUA tabel aplikasi. Hati-hati, datanya ikut hilang.
+-- SQLite gak bisa DROP banyak tabel sekaligus, jadi satu-satu (anak dulu baru induk).
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS category_rules;
+DROP TABLE IF EXISTS statement_import_rows;
+DROP TABLE IF EXISTS statement_imports;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS transaction_lines;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS payee_aliases;
+DROP TABLE IF EXISTS payees;
+DROP TABLE IF EXISTS wallets;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS groups;
+DROP TABLE IF EXISTS users;
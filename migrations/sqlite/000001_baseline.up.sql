@@ -0,0 +1,320 @@
+-- Baseline versi SQLite, isinya sama dengan postgres/000001_baseline.up.sql.
+-- Bedanya: timestamptz -> datetime (biar driver sqlite scan jadi time.Time) & gak ada default gen_random_uuid(),
+-- ID diisi di Go (models.Base.BeforeCreate).
+
+CREATE TABLE IF NOT EXISTS users (
+    id                      uuid PRIMARY KEY,
+    created_at              datetime,
+    updated_at              datetime,
+    deleted_at              datetime,
+    username                varchar(100),
+    email                   varchar(100),
+    password                varchar(255),
+    user_role               smallint,
+    subscription_plan       varchar(100),
+    subscription_expired_at datetime,
+    failed_login_attempts   bigint NOT NULL DEFAULT 0,
+    locked_until            datetime,
+    two_factor_enabled      boolean NOT NULL DEFAULT false,
+    two_factor_secret       varchar(64),
+    CONSTRAINT uni_users_username UNIQUE (username),
+    CONSTRAINT uni_users_email UNIQUE (email)
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS groups (
+    id          uuid PRIMARY KEY,
+    created_at  datetime,
+    updated_at  datetime,
+    deleted_at  datetime,
+    name        varchar(200) NOT NULL,
+    description text,
+    owner_id    uuid NOT NULL,
+    version     bigint NOT NULL DEFAULT 1
+);
+CREATE INDEX IF NOT EXISTS idx_groups_deleted_at ON groups (deleted_at);
+
+CREATE TABLE IF NOT EXISTS group_members (
+    id           uuid PRIMARY KEY,
+    created_at   datetime,
+    updated_at   datetime,
+    deleted_at   datetime,
+    members_role smallint NOT NULL DEFAULT 2,
+    group_id     uuid NOT NULL,
+    user_id      uuid NOT NULL,
+    CONSTRAINT fk_groups_members FOREIGN KEY (group_id) REFERENCES groups (id),
+    CONSTRAINT fk_group_members_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_group_members_deleted_at ON group_members (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_member_group ON group_members (group_id, user_id);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id         uuid PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    user_id    uuid,
+    group_id   uuid,
+    name       varchar(100),
+    type       varchar(20),
+    version    bigint NOT NULL DEFAULT 1,
+    CONSTRAINT uni_categories_name UNIQUE (name)
+);
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories (deleted_at);
+
+CREATE TABLE IF NOT EXISTS wallets (
+    id         uuid PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    user_id    uuid,
+    group_id   uuid,
+    name       varchar(100),
+    balance    decimal(16,2) DEFAULT 0,
+    currency   varchar(10) DEFAULT 'IDR',
+    CONSTRAINT fk_users_wallets FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_groups_wallet FOREIGN KEY (group_id) REFERENCES groups (id)
+);
+CREATE INDEX IF NOT EXISTS idx_wallets_deleted_at ON wallets (deleted_at);
+
+CREATE TABLE IF NOT EXISTS payees (
+    id                  uuid PRIMARY KEY,
+    created_at          datetime,
+    updated_at          datetime,
+    deleted_at          datetime,
+    user_id             uuid NOT NULL,
+    group_id            uuid,
+    name                varchar(150) NOT NULL,
+    normalized_name     varchar(150) NOT NULL,
+    default_category_id uuid,
+    CONSTRAINT fk_payees_default_category FOREIGN KEY (default_category_id) REFERENCES categories (id)
+);
+CREATE INDEX IF NOT EXISTS idx_payees_deleted_at ON payees (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_payees_user_id ON payees (user_id);
+CREATE INDEX IF NOT EXISTS idx_payees_group_id ON payees (group_id);
+CREATE INDEX IF NOT EXISTS idx_payees_normalized_name ON payees (normalized_name);
+
+CREATE TABLE IF NOT EXISTS payee_aliases (
+    id               uuid PRIMARY KEY,
+    created_at       datetime,
+    updated_at       datetime,
+    deleted_at       datetime,
+    payee_id         uuid NOT NULL,
+    alias            varchar(150) NOT NULL,
+    normalized_alias varchar(150) NOT NULL,
+    CONSTRAINT fk_payees_aliases FOREIGN KEY (payee_id) REFERENCES payees (id)
+);
+CREATE INDEX IF NOT EXISTS idx_payee_aliases_deleted_at ON payee_aliases (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_payee_aliases_payee_id ON payee_aliases (payee_id);
+CREATE INDEX IF NOT EXISTS idx_payee_aliases_normalized_alias ON payee_aliases (normalized_alias);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id          uuid PRIMARY KEY,
+    created_at  datetime,
+    updated_at  datetime,
+    deleted_at  datetime,
+    user_id     uuid NOT NULL,
+    wallet_id   uuid NOT NULL,
+    category_id uuid NOT NULL,
+    payee_id    uuid,
+    title       varchar(255),
+    amount      decimal(16,2),
+    description text,
+    date        datetime,
+    tags        varchar(255),
+    version     bigint NOT NULL DEFAULT 1,
+    external_id varchar(100),
+    fingerprint varchar(64),
+    CONSTRAINT fk_transactions_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_wallets_transactions FOREIGN KEY (wallet_id) REFERENCES wallets (id),
+    CONSTRAINT fk_categories_transaction FOREIGN KEY (category_id) REFERENCES categories (id),
+    CONSTRAINT fk_transactions_payee FOREIGN KEY (payee_id) REFERENCES payees (id)
+);
+CREATE INDEX IF NOT EXISTS idx_transactions_deleted_at ON transactions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_payee_id ON transactions (payee_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_fingerprint ON transactions (fingerprint);
+
+CREATE TABLE IF NOT EXISTS transaction_lines (
+    id             uuid PRIMARY KEY,
+    created_at     datetime,
+    updated_at     datetime,
+    deleted_at     datetime,
+    transaction_id uuid NOT NULL,
+    category_id    uuid NOT NULL,
+    amount         decimal(16,2),
+    description    varchar(255),
+    position       bigint NOT NULL DEFAULT 0,
+    CONSTRAINT fk_transactions_lines FOREIGN KEY (transaction_id) REFERENCES transactions (id),
+    CONSTRAINT fk_transaction_lines_category FOREIGN KEY (category_id) REFERENCES categories (id)
+);
+CREATE INDEX IF NOT EXISTS idx_transaction_lines_deleted_at ON transaction_lines (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_transaction_lines_transaction_id ON transaction_lines (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_lines_category_id ON transaction_lines (category_id);
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id         uuid PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    user_id    uuid NOT NULL,
+    code_hash  varchar(64) NOT NULL,
+    used_at    datetime
+);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_deleted_at ON recovery_codes (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes (user_id);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           uuid PRIMARY KEY,
+    created_at   datetime,
+    updated_at   datetime,
+    deleted_at   datetime,
+    user_id      uuid NOT NULL,
+    name         varchar(100) NOT NULL,
+    prefix       varchar(16) NOT NULL,
+    key_hash     varchar(64) NOT NULL,
+    scopes       text,
+    expires_at   datetime,
+    last_used_at datetime,
+    CONSTRAINT fk_api_keys_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_api_keys_deleted_at ON api_keys (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_prefix ON api_keys (prefix);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_key_hash ON api_keys (key_hash);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id         uuid PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    user_id    uuid NOT NULL,
+    group_id   uuid,
+    url        varchar(500) NOT NULL,
+    secret     varchar(100) NOT NULL,
+    events     text NOT NULL,
+    active     boolean NOT NULL DEFAULT true
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_deleted_at ON webhook_subscriptions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_user_id ON webhook_subscriptions (user_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_group_id ON webhook_subscriptions (group_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              uuid PRIMARY KEY,
+    created_at      datetime,
+    updated_at      datetime,
+    deleted_at      datetime,
+    subscription_id uuid NOT NULL,
+    event_id        uuid NOT NULL,
+    event_type      varchar(100) NOT NULL,
+    payload         text,
+    attempt         bigint NOT NULL,
+    status_code     bigint,
+    response_body   text,
+    error           text,
+    success         boolean NOT NULL DEFAULT false,
+    duration_ms     bigint
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_deleted_at ON webhook_deliveries (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_id ON webhook_deliveries (subscription_id);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id         uuid PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    user_id    uuid NOT NULL,
+    type       varchar(50) NOT NULL,
+    title      varchar(200) NOT NULL,
+    body       text,
+    data       text,
+    read_at    datetime
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_deleted_at ON notifications (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    id         uuid PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    user_id    uuid NOT NULL,
+    type       varchar(50) NOT NULL,
+    channel    varchar(30) NOT NULL,
+    enabled    boolean NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_notification_preferences_deleted_at ON notification_preferences (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_pref ON notification_preferences (user_id, type, channel);
+
+CREATE TABLE IF NOT EXISTS statement_imports (
+    id         uuid PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    user_id    uuid NOT NULL,
+    wallet_id  uuid NOT NULL,
+    format     varchar(10) NOT NULL,
+    file_name  varchar(255),
+    status     varchar(20) NOT NULL DEFAULT 'pending'
+);
+CREATE INDEX IF NOT EXISTS idx_statement_imports_deleted_at ON statement_imports (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_statement_imports_user_id ON statement_imports (user_id);
+
+CREATE TABLE IF NOT EXISTS statement_import_rows (
+    id             uuid PRIMARY KEY,
+    created_at     datetime,
+    updated_at     datetime,
+    deleted_at     datetime,
+    import_id      uuid NOT NULL,
+    date           datetime,
+    amount         decimal(16,2),
+    payee          varchar(255),
+    memo           text,
+    external_id    varchar(100),
+    fingerprint    varchar(64),
+    duplicate      boolean NOT NULL DEFAULT false,
+    skip           boolean NOT NULL DEFAULT false,
+    category_id    uuid,
+    tags           varchar(255),
+    transaction_id uuid,
+    CONSTRAINT fk_statement_imports_rows FOREIGN KEY (import_id) REFERENCES statement_imports (id),
+    CONSTRAINT fk_statement_import_rows_category FOREIGN KEY (category_id) REFERENCES categories (id)
+);
+CREATE INDEX IF NOT EXISTS idx_statement_import_rows_deleted_at ON statement_import_rows (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_statement_import_rows_import_id ON statement_import_rows (import_id);
+
+CREATE TABLE IF NOT EXISTS category_rules (
+    id          uuid PRIMARY KEY,
+    created_at  datetime,
+    updated_at  datetime,
+    deleted_at  datetime,
+    user_id     uuid NOT NULL,
+    name        varchar(100) NOT NULL,
+    priority    bigint NOT NULL DEFAULT 0,
+    active      boolean NOT NULL DEFAULT true,
+    conditions  text NOT NULL,
+    category_id uuid NOT NULL,
+    tags        varchar(255),
+    CONSTRAINT fk_category_rules_category FOREIGN KEY (category_id) REFERENCES categories (id)
+);
+CREATE INDEX IF NOT EXISTS idx_category_rules_deleted_at ON category_rules (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_category_rules_user_id ON category_rules (user_id);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id            uuid PRIMARY KEY,
+    created_at    datetime,
+    updated_at    datetime,
+    deleted_at    datetime,
+    user_id       uuid NOT NULL,
+    key           varchar(255) NOT NULL,
+    method        varchar(10) NOT NULL,
+    path          varchar(255) NOT NULL,
+    request_hash  varchar(64) NOT NULL,
+    completed     boolean NOT NULL DEFAULT false,
+    status_code   bigint,
+    response_body text,
+    content_type  varchar(100),
+    expires_at    datetime NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_deleted_at ON idempotency_keys (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_idempotency_user_key ON idempotency_keys (user_id, key);
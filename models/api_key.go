@@ -0,0 +1,59 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix = awalan semua API key, biar middleware bisa bedain API key vs JWT.
+const APIKeyPrefix = "cfk_"
+
+// Scope yang bisa dikasih ke API key. Request pakai JWT login dianggap punya semua scope.
+const (
+	ScopeTransactionsRead  = "transactions:read"
+	ScopeTransactionsWrite = "transactions:write"
+	ScopeWalletsRead       = "wallets:read"
+	ScopeCategoriesRead    = "categories:read"
+	ScopeReportsRead       = "reports:read"
+)
+
+var APIKeyScopes = []string{
+	ScopeTransactionsRead,
+	ScopeTransactionsWrite,
+	ScopeWalletsRead,
+	ScopeCategoriesRead,
+	ScopeReportsRead,
+}
+
+// APIKey = personal access token buat script/integrasi. Key aslinya cuma ditampilkan sekali,
+// yang disimpan hash SHA-256 + prefix (buat identifikasi di UI).
+type APIKey struct {
+	Base
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name       string     `gorm:"type:varchar(100);not null" json:"name"`
+	Prefix     string     `gorm:"type:varchar(16);not null;uniqueIndex" json:"prefix"`
+	KeyHash    string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	Scopes     string     `gorm:"type:text" json:"scopes"` // dipisah koma, misal "transactions:read,reports:read"
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
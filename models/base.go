@@ -9,8 +9,17 @@ import (
 
 // Base struct buat semua model yang pake UUID
 type Base struct {
-	ID        uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ID        uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
-}
\ No newline at end of file
+}
+
+// BeforeCreate isi ID di Go, bukan lewat default gen_random_uuid() postgres, biar jalan juga di SQLite.
+// ID yang udah diisi duluan (misal dari import / test) gak ditimpa.
+func (b *Base) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Kategori fallback kalau transaksi dibuat tanpa category_name dan gak ada rule yang cocok.
+const (
+	UncategorizedCategoryName = "Uncategorized"
+	UncategorizedCategoryType = "EXPENSE"
+)
+
+// Field & operator yang bisa dipakai di kondisi rule.
+const (
+	RuleFieldTitle       = "title"
+	RuleFieldDescription = "description"
+	RuleFieldAmount      = "amount" // dibandingin pakai nilai absolut
+
+	RuleOpContains   = "contains"
+	RuleOpEquals     = "equals"
+	RuleOpStartsWith = "starts_with"
+	RuleOpRegex      = "regex"
+	RuleOpLT         = "lt"
+	RuleOpLTE        = "lte"
+	RuleOpGT         = "gt"
+	RuleOpGTE        = "gte"
+)
+
+type RuleCondition struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// CategoryRule: kalau SEMUA kondisi cocok -> set kategori + tambah tag.
+// Dievaluasi urut Priority ASC (angka kecil duluan), rule pertama yang cocok menang.
+type CategoryRule struct {
+	Base
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name       string    `gorm:"type:varchar(100);not null" json:"name"`
+	Priority   int       `gorm:"not null;default:0" json:"priority"`
+	Active     bool      `gorm:"not null;default:true" json:"active"`
+	Conditions string    `gorm:"type:text;not null" json:"-"` // JSON []RuleCondition
+	CategoryID uuid.UUID `gorm:"type:uuid;not null" json:"category_id"`
+	Tags       string    `gorm:"type:varchar(255)" json:"-"` // dipisah koma
+
+	Category Category `gorm:"foreignKey:CategoryID" json:"category"`
+}
+
+func (r *CategoryRule) ConditionList() []RuleCondition {
+	var conditions []RuleCondition
+	if err := json.Unmarshal([]byte(r.Conditions), &conditions); err != nil {
+		return nil
+	}
+	return conditions
+}
+
+func (r *CategoryRule) TagList() []string {
+	return SplitTags(r.Tags)
+}
+
+// SplitTags / JoinTags dipakai bareng Transaction.Tags & CategoryRule.Tags.
+func SplitTags(tags string) []string {
+	var list []string
+	for _, t := range strings.Split(tags, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			list = append(list, t)
+		}
+	}
+	return list
+}
+
+// JoinTags normalisasi (lowercase, trim, tanpa duplikat) terus digabung pakai koma.
+func JoinTags(tags ...[]string) string {
+	seen := make(map[string]bool)
+	var list []string
+	for _, group := range tags {
+		for _, t := range group {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if t == "" || seen[t] {
+				continue
+			}
+			seen[t] = true
+			list = append(list, t)
+		}
+	}
+	return strings.Join(list, ",")
+}
@@ -32,6 +32,7 @@ type Group struct {
 
 	OwnerID     uuid.UUID `gorm:"type:uuid;not null" json:"group_owner_id"`
 	MemberCount int64     `gorm:"-:migration;->" json:"member_count"`
+	Version     int       `gorm:"not null;default:1" json:"version"` // naik tiap group / member-nya diubah
 	// WalletID uuid.UUID `gorm:"type:uuid;not null" json:"group_wallet_id"`
 
 	Members []GroupMember `gorm:"foreignKey:GroupID" json:"members"`
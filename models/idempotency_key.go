@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKey nyimpen hasil request POST yang bawa header Idempotency-Key,
+// biar retry dari client (jaringan putus-nyambung) gak bikin transaksi dobel.
+type IdempotencyKey struct {
+	Base
+	UserID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_idempotency_user_key" json:"user_id"`
+	Key          string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_user_key" json:"key"`
+	Method       string    `gorm:"type:varchar(10);not null" json:"method"`
+	Path         string    `gorm:"type:varchar(255);not null" json:"path"`
+	RequestHash  string    `gorm:"type:varchar(64);not null" json:"request_hash"`
+	Completed    bool      `gorm:"not null;default:false" json:"completed"` // false = request pertama masih diproses
+	StatusCode   int       `json:"status_code"`
+	ResponseBody string    `gorm:"type:text" json:"response_body"`
+	ContentType  string    `gorm:"type:varchar(100)" json:"content_type"`
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expires_at"`
+	// LockedUntil = batas request pertama dianggap masih jalan. Lewat ini tapi belum completed berarti prosesnya
+	// mati di tengah jalan (crash/restart), key boleh diambil alih request berikutnya. nil = data sebelum ada lease
+	LockedUntil *time.Time `json:"locked_until"`
+}
+
+// InProgress: request pertama masih diproses dan lease-nya belum habis.
+func (k *IdempotencyKey) InProgress(now time.Time) bool {
+	return !k.Completed && k.LockedUntil != nil && k.LockedUntil.After(now)
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipe notifikasi yang beneran dikirim service. Preference user disimpan per tipe + channel.
+const (
+	NotificationGroupMemberAdded      = "group.member_added"
+	NotificationGroupTransactionAdded = "group.transaction_added"
+)
+
+var NotificationTypes = []string{
+	NotificationGroupMemberAdded,
+	NotificationGroupTransactionAdded,
+}
+
+type Notification struct {
+	Base
+	UserID uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Type   string     `gorm:"type:varchar(50);not null" json:"type"`
+	Title  string     `gorm:"type:varchar(200);not null" json:"title"`
+	Body   string     `gorm:"type:text" json:"body"`
+	Data   string     `gorm:"type:text" json:"data"` // JSON, isinya tergantung tipe
+	ReadAt *time.Time `json:"read_at"`
+}
+
+// NotificationPreference cuma disimpan kalau user ngubah default channel-nya.
+type NotificationPreference struct {
+	Base
+	UserID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_notification_pref" json:"user_id"`
+	Type    string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_notification_pref" json:"type"`
+	Channel string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_notification_pref" json:"channel"`
+	Enabled bool      `gorm:"not null" json:"enabled"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "github.com/google/uuid"
+
+// Payee = penerima/merchant transaksi ("Starbucks"). Variasi penulisan ("STARBUCKS JKT")
+// dicatat sebagai alias biar nyambung ke payee yang sama.
+type Payee struct {
+	Base
+	UserID            uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"` // pembuat, payee pribadi = pemiliknya
+	GroupID           *uuid.UUID `gorm:"type:uuid;index" json:"group_id"`         // diisi = payee milik group, dipakai bareng member
+	Name              string     `gorm:"type:varchar(150);not null" json:"name"`
+	NormalizedName    string     `gorm:"type:varchar(150);not null;index" json:"-"`
+	DefaultCategoryID *uuid.UUID `gorm:"type:uuid" json:"default_category_id"` // dipakai kalau transaksi gak nyebut kategori
+	TransactionCount  int64      `gorm:"-:migration;->" json:"transaction_count"`
+
+	DefaultCategory *Category    `gorm:"foreignKey:DefaultCategoryID" json:"default_category,omitempty"`
+	Aliases         []PayeeAlias `gorm:"foreignKey:PayeeID" json:"aliases,omitempty"`
+}
+
+type PayeeAlias struct {
+	Base
+	PayeeID         uuid.UUID `gorm:"type:uuid;not null;index" json:"payee_id"`
+	Alias           string    `gorm:"type:varchar(150);not null" json:"alias"`
+	NormalizedAlias string    `gorm:"type:varchar(150);not null;index" json:"-"`
+}
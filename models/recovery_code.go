@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecoveryCode = kode cadangan 2FA sekali pakai, disimpan dalam bentuk hash.
+type RecoveryCode struct {
+	Base
+	UserID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	CodeHash string     `gorm:"type:varchar(64);not null" json:"-"`
+	UsedAt   *time.Time `json:"used_at"`
+}
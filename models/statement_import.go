@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	StatementImportPending   = "pending"
+	StatementImportCommitted = "committed"
+)
+
+// StatementImport = satu file mutasi yang di-upload. Baris-barisnya di-review dulu sebelum di-commit
+// jadi transaksi beneran.
+type StatementImport struct {
+	Base
+	UserID   uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	WalletID uuid.UUID `gorm:"type:uuid;not null" json:"wallet_id"`
+	Format   string    `gorm:"type:varchar(10);not null" json:"format"`
+	FileName string    `gorm:"type:varchar(255)" json:"file_name"`
+	Status   string    `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+
+	Rows []StatementImportRow `gorm:"foreignKey:ImportID" json:"rows,omitempty"`
+}
+
+type StatementImportRow struct {
+	Base
+	ImportID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"import_id"`
+	Date        time.Time  `json:"date"`
+	Amount      float64    `gorm:"type:decimal(16,2)" json:"amount"` // bertanda, negatif = keluar
+	Payee       string     `gorm:"type:varchar(255)" json:"payee"`
+	Memo        string     `gorm:"type:text" json:"memo"`
+	ExternalID  string     `gorm:"type:varchar(100)" json:"external_id"`
+	Fingerprint string     `gorm:"type:varchar(64)" json:"fingerprint"`
+	Duplicate   bool       `gorm:"not null;default:false" json:"duplicate"`
+	Skip        bool       `gorm:"not null;default:false" json:"skip"`
+	CategoryID  *uuid.UUID `gorm:"type:uuid" json:"category_id"`
+	Tags        string     `gorm:"type:varchar(255)" json:"tags"`
+
+	TransactionID *uuid.UUID `gorm:"type:uuid" json:"transaction_id"` // keisi setelah commit
+
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+}
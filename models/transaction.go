@@ -11,15 +11,26 @@ type Transaction struct {
 	UserID   uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
 	WalletID uuid.UUID `gorm:"type:uuid;not null" json:"wallet_id"`
 
-	CategoryID uuid.UUID `gorm:"type:uuid;not null" json:"category_id"`
+	CategoryID uuid.UUID  `gorm:"type:uuid;not null" json:"category_id"`
+	PayeeID    *uuid.UUID `gorm:"type:uuid;index" json:"payee_id"`
 
 	Title            string    `gorm:"type:varchar(255)" json:"title"`
 	Amount           float64   `gorm:"type:decimal(16,2)" json:"amount"`
 	Description      string    `gorm:"type:text" json:"description"`
 	Date             time.Time `json:"date"`
 	TransactionCount int64     `gorm:"-:migration;->" json:"transaction_count"`
+	Tags             string    `gorm:"type:varchar(255)" json:"tags"`     // dipisah koma
+	Version          int       `gorm:"not null;default:1" json:"version"` // optimistic locking, naik tiap update
+
+	// Diisi kalau transaksi berasal dari import mutasi bank
+	ExternalID  string `gorm:"type:varchar(100)" json:"external_id,omitempty"`
+	Fingerprint string `gorm:"type:varchar(64);index" json:"-"`
 
 	User     User     `gorm:"foreignKey:UserID"`
 	Wallet   Wallet   `gorm:"foreignKey:WalletID"`
 	Category Category `gorm:"foreignKey:CategoryID"`
+	Payee    *Payee   `gorm:"foreignKey:PayeeID"`
+
+	// Kosong = transaksi biasa. Kalau ada, CategoryID di atas = kategori line terbesar.
+	Lines []TransactionLine `gorm:"foreignKey:TransactionID" json:"lines,omitempty"`
 }
@@ -0,0 +1,16 @@
+package models
+
+import "github.com/google/uuid"
+
+// TransactionLine = pecahan satu transaksi ke beberapa kategori (misal struk supermarket:
+// sebagian belanja dapur, sebagian alat rumah tangga). Total Amount semua line = Amount transaksinya.
+type TransactionLine struct {
+	Base
+	TransactionID uuid.UUID `gorm:"type:uuid;not null;index" json:"transaction_id"`
+	CategoryID    uuid.UUID `gorm:"type:uuid;not null;index" json:"category_id"`
+	Amount        float64   `gorm:"type:decimal(16,2)" json:"amount"` // bertanda, sama kayak Transaction.Amount
+	Description   string    `gorm:"type:varchar(255)" json:"description"`
+	Position      int       `gorm:"not null;default:0" json:"position"` // urutan line sesuai input
+
+	Category Category `gorm:"foreignKey:CategoryID" json:"category"`
+}
@@ -30,6 +30,14 @@ type User struct {
 	UserRole UserRole `gorm:"type:smallint" json:"user_role" default:"3"`
 	SubscriptionPlan string `gorm:"type:varchar(100)" json:"subscription_plan"`
 	SubscriptionExpiredAt *time.Time `json:"subscription_expired_at" `
+
+	// Brute-force protection, dihitung di authService.Login
+	FailedLoginAttempts int        `gorm:"not null;default:0" json:"-"`
+	LockedUntil         *time.Time `json:"-"`
+
+	// 2FA (TOTP). Secret udah keisi pas enroll, tapi baru aktif setelah kode pertama diverifikasi
+	TwoFactorEnabled bool   `gorm:"not null;default:false" json:"two_factor_enabled"`
+	TwoFactorSecret  string `gorm:"type:varchar(64)" json:"-"`
 	
 	// Relations
 	Wallets      []Wallet      `gorm:"foreignKey:UserID"`
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// WebhookSubscription milik user (GroupID nil -> event wallet pribadi user)
+// atau milik group (GroupID diisi -> event wallet group tsb).
+type WebhookSubscription struct {
+	Base
+	UserID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	GroupID *uuid.UUID `gorm:"type:uuid;index" json:"group_id,omitempty"`
+	URL     string     `gorm:"type:varchar(500);not null" json:"url"`
+	Secret  string     `gorm:"type:varchar(100);not null" json:"-"`
+	Events  string     `gorm:"type:text;not null" json:"events"` // dipisah koma
+	Active  bool       `gorm:"not null;default:true" json:"active"`
+}
+
+func (w *WebhookSubscription) EventList() []string {
+	if w.Events == "" {
+		return []string{}
+	}
+	return strings.Split(w.Events, ",")
+}
+
+func (w *WebhookSubscription) HasEvent(eventType string) bool {
+	for _, e := range w.EventList() {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery = log tiap percobaan kirim (1 row per attempt).
+type WebhookDelivery struct {
+	Base
+	SubscriptionID uuid.UUID `gorm:"type:uuid;not null;index" json:"subscription_id"`
+	EventID        uuid.UUID `gorm:"type:uuid;not null" json:"event_id"`
+	EventType      string    `gorm:"type:varchar(100);not null" json:"event_type"`
+	Payload        string    `gorm:"type:text" json:"payload"`
+	Attempt        int       `gorm:"not null" json:"attempt"`
+	StatusCode     int       `json:"status_code"`
+	Error          string    `gorm:"type:text" json:"error,omitempty"`
+	Success        bool      `gorm:"not null;default:false" json:"success"`
+	DurationMs     int64     `json:"duration_ms"`
+}
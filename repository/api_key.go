@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"cashflow_gin/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error)
+	FindByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	Delete(ctx context.Context, userID, keyID uuid.UUID) (bool, error)
+	TouchLastUsed(ctx context.Context, keyID uuid.UUID, usedAt time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+func (r *apiKeyRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// FindByHash sekalian join User biar middleware bisa isi role tanpa query lagi.
+// Kalau user-nya udah di-soft delete, User bakal kosong (LEFT JOIN) -> dicek di Service.
+func (r *apiKeyRepository) FindByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.WithContext(ctx).Joins("User").First(&key, "api_keys.key_hash = ?", keyHash).Error
+	return &key, err
+}
+
+func (r *apiKeyRepository) Delete(ctx context.Context, userID, keyID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", keyID, userID).Delete(&models.APIKey{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, keyID uuid.UUID, usedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.APIKey{}).Where("id = ?", keyID).UpdateColumn("last_used_at", usedAt).Error
+}
@@ -3,15 +3,28 @@ package repository
 import (
 	"cashflow_gin/dto/request"
 	"cashflow_gin/models"
+	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type AuthRepository interface {
-	Login(input *request.LoginRequest) (*models.User, error)
-	Register(input *request.CreateUserRequest) (*models.User, error)
-	FindByEmail(email string) (*models.User, error)
-	CreateUserWithWallet(user *models.User, wallet *models.Wallet) error
+	Login(ctx context.Context, input *request.LoginRequest) (*models.User, error)
+	Register(ctx context.Context, input *request.CreateUserRequest) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	CreateUserWithWallet(ctx context.Context, user *models.User, wallet *models.Wallet) error
+
+	IncrementFailedLogin(ctx context.Context, userID uuid.UUID) (int, error)
+	LockAccount(ctx context.Context, userID uuid.UUID, until time.Time) error
+	ResetFailedLogin(ctx context.Context, userID uuid.UUID) error
+
+	FindByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	SaveTwoFactorSecret(ctx context.Context, userID uuid.UUID, secret string) error
+	EnableTwoFactor(ctx context.Context, userID uuid.UUID, codes []models.RecoveryCode) error
+	DisableTwoFactor(ctx context.Context, userID uuid.UUID) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
 }
 
 type authRepository struct {
@@ -22,28 +35,28 @@ func NewAuthRepository(db *gorm.DB) AuthRepository {
 	return &authRepository{db: db}
 }
 
-func (r *authRepository) Login(input *request.LoginRequest) (*models.User, error) {
+func (r *authRepository) Login(ctx context.Context, input *request.LoginRequest) (*models.User, error) {
 	var user models.User
-	err := r.db.First(&user, "email = ? ", input.Email).Error
+	err := r.db.WithContext(ctx).First(&user, "email = ? ", input.Email).Error
 
 	return &user, err
 }
 
-func (r *authRepository) Register(input *request.CreateUserRequest) (*models.User, error) {
+func (r *authRepository) Register(ctx context.Context, input *request.CreateUserRequest) (*models.User, error) {
 	var user models.User
-	err := r.db.Create(&user).Error
+	err := r.db.WithContext(ctx).Create(&user).Error
 
 	return &user, err
 }
 
-func (r *authRepository) FindByEmail(email string) (*models.User, error) {
+func (r *authRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	err := r.db.First(&user, "email = ?", email).Error
+	err := r.db.WithContext(ctx).First(&user, "email = ?", email).Error
 	return &user, err
 }
 
-func (r *authRepository) CreateUserWithWallet(user *models.User, wallet *models.Wallet) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *authRepository) CreateUserWithWallet(ctx context.Context, user *models.User, wallet *models.Wallet) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(user).Error; err != nil {
 			return err
 		}
@@ -54,3 +67,78 @@ func (r *authRepository) CreateUserWithWallet(user *models.User, wallet *models.
 		return nil
 	})
 }
+
+// IncrementFailedLogin nambah counter gagal login secara atomic & balikin nilai terbarunya.
+func (r *authRepository) IncrementFailedLogin(ctx context.Context, userID uuid.UUID) (int, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).
+			Where("id = ?", userID).
+			Update("failed_login_attempts", gorm.Expr("failed_login_attempts + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Select("failed_login_attempts").First(&user, "id = ?", userID).Error
+	})
+	return user.FailedLoginAttempts, err
+}
+
+func (r *authRepository) LockAccount(ctx context.Context, userID uuid.UUID, until time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"failed_login_attempts": 0,
+		"locked_until":          until,
+	}).Error
+}
+
+func (r *authRepository) ResetFailedLogin(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"failed_login_attempts": 0,
+		"locked_until":          nil,
+	}).Error
+}
+
+func (r *authRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error
+	return &user, err
+}
+
+func (r *authRepository) SaveTwoFactorSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Update("two_factor_secret", secret).Error
+}
+
+// EnableTwoFactor nyalain 2FA & ganti semua recovery code lama dengan yang baru (atomic).
+func (r *authRepository) EnableTwoFactor(ctx context.Context, userID uuid.UUID, codes []models.RecoveryCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Update("two_factor_enabled", true).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		for i := range codes {
+			codes[i].UserID = userID
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *authRepository) DisableTwoFactor(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"two_factor_enabled": false,
+			"two_factor_secret":  "",
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+	})
+}
+
+// UseRecoveryCode nandain recovery code kepake. Return false kalau kode gak ada / udah dipakai.
+// Pakai conditional UPDATE biar 2 request barengan gak bisa pakai kode yang sama.
+func (r *authRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
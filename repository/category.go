@@ -2,22 +2,24 @@ package repository
 
 import (
 	"cashflow_gin/models"
+	"context"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type CategoryRepository interface {
-	FindByID(id uuid.UUID) (*models.Category, error)
-	CreateDefaultCategories() (*[]models.Category, error)
-	FindAll() (*[]models.Category, error)
-	FindByName(name string) (*models.Category, error)
-	FindByUserID(userID uuid.UUID) (*[]models.Category, error)
-	FindByGroupID(groupID uuid.UUID) (*[]models.Category, error)
-	Create(category *models.Category) (*models.Category, error)
-	Update(category *models.Category) (*models.Category, error)
-	Delete(category *models.Category) error
-	FindByIDAndUserID(id uuid.UUID, userID uuid.UUID) (*models.Category, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Category, error)
+	CreateDefaultCategories(ctx context.Context) (*[]models.Category, error)
+	FindAll(ctx context.Context) (*[]models.Category, error)
+	FindByName(ctx context.Context, name string) (*models.Category, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*[]models.Category, error)
+	FindByGroupID(ctx context.Context, groupID uuid.UUID) (*[]models.Category, error)
+	Create(ctx context.Context, category *models.Category) (*models.Category, error)
+	Update(ctx context.Context, category *models.Category) (*models.Category, error)
+	Delete(ctx context.Context, category *models.Category) error
+	FindByIDAndUserID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Category, error)
+	FindOrCreate(ctx context.Context, name, categoryType string) (*models.Category, error)
 }
 
 type categoryRepository struct {
@@ -28,15 +30,15 @@ func NewCategoryRepository(db *gorm.DB) CategoryRepository {
 	return &categoryRepository{db: db}
 }
 
-func (r *categoryRepository) FindByID(id uuid.UUID) (*models.Category, error) {
+func (r *categoryRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Category, error) {
 	var category models.Category
 	// Cek kategori berdasarkan ID dan pastikan user_id juga cocok (security)
 	// Note: Parameter userID bisa lu tambah nanti buat validasi ownership
-	err := r.db.First(&category, "id = ?", id).Error
+	err := r.db.WithContext(ctx).First(&category, "id = ?", id).Error
 	return &category, err
 }
 
-func (r *categoryRepository) CreateDefaultCategories() (*[]models.Category, error) {
+func (r *categoryRepository) CreateDefaultCategories(ctx context.Context) (*[]models.Category, error) {
 	categories := []models.Category{
 		{Name: "Salary", Type: "INCOME"},
 		{Name: "Freelance", Type: "INCOME"},
@@ -52,50 +54,80 @@ func (r *categoryRepository) CreateDefaultCategories() (*[]models.Category, erro
 		{Name: "Transport", Type: "EXPENSE"},
 		{Name: "Entertainment", Type: "EXPENSE"},
 	}
-	return &categories, r.db.Create(&categories).Error
+	return &categories, r.db.WithContext(ctx).Create(&categories).Error
 }
 
-func (r *categoryRepository) FindAll() (*[]models.Category, error) {
+func (r *categoryRepository) FindAll(ctx context.Context) (*[]models.Category, error) {
 	var categories []models.Category
-	err := r.db.Find(&categories).Error
+	err := r.db.WithContext(ctx).Find(&categories).Error
 	return &categories, err
 }
 
-func (r *categoryRepository) FindByName(name string) (*models.Category, error) {
+func (r *categoryRepository) FindByName(ctx context.Context, name string) (*models.Category, error) {
 	var category models.Category
-	err := r.db.First(&category, "name = ?", name).Error
+	err := r.db.WithContext(ctx).First(&category, "name = ?", name).Error
 	return &category, err
 }
 
-func (r *categoryRepository) FindByUserID(userID uuid.UUID) (*[]models.Category, error) {
+func (r *categoryRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*[]models.Category, error) {
 	var categories []models.Category
-	err := r.db.Where("user_id = ?", userID).Find(&categories).Error
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&categories).Error
 	return &categories, err
 }
 
-func (r *categoryRepository) FindByGroupID(groupID uuid.UUID) (*[]models.Category, error) {
+func (r *categoryRepository) FindByGroupID(ctx context.Context, groupID uuid.UUID) (*[]models.Category, error) {
 	var categories []models.Category
-	err := r.db.Where("group_id = ?", groupID).Find(&categories).Error
+	err := r.db.WithContext(ctx).Where("group_id = ?", groupID).Find(&categories).Error
 	return &categories, err
 }
 
-func (r *categoryRepository) Create(category *models.Category) (*models.Category, error) {
-	err := r.db.Create(&category).Error
+func (r *categoryRepository) Create(ctx context.Context, category *models.Category) (*models.Category, error) {
+	err := r.db.WithContext(ctx).Create(&category).Error
 	return category, err
 }
 
-func (r *categoryRepository) Update(category *models.Category) (*models.Category, error) {
-	err := r.db.Save(&category).Error
-	return category, err
+// Update & Delete cek version (optimistic locking), ErrStaleVersion kalau udah diubah orang lain.
+func (r *categoryRepository) Update(ctx context.Context, category *models.Category) (*models.Category, error) {
+	result := r.db.WithContext(ctx).Model(&models.Category{}).
+		Where("id = ? AND version = ?", category.ID, category.Version).
+		Updates(map[string]interface{}{
+			"name":     category.Name,
+			"type":     category.Type,
+			"group_id": category.GroupID,
+			"version":  gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return category, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return category, ErrStaleVersion
+	}
+	category.Version++
+	return category, nil
 }
 
-func (r *categoryRepository) Delete(category *models.Category) error {
-	err := r.db.Delete(&category).Error
-	return err
+func (r *categoryRepository) Delete(ctx context.Context, category *models.Category) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND version = ?", category.ID, category.Version).Delete(&models.Category{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+	return nil
+}
+
+func (r *categoryRepository) FindByIDAndUserID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Category, error) {
+	var category models.Category
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&category).Error
+	return &category, err
 }
 
-func (r *categoryRepository) FindByIDAndUserID(id uuid.UUID, userID uuid.UUID) (*models.Category, error) {
+// FindOrCreate dipakai buat kategori sistem (mis. "Uncategorized") yang dibikin on demand.
+func (r *categoryRepository) FindOrCreate(ctx context.Context, name, categoryType string) (*models.Category, error) {
 	var category models.Category
-	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&category).Error
+	err := r.db.WithContext(ctx).Where(models.Category{Name: name}).
+		Attrs(models.Category{Type: categoryType}).
+		FirstOrCreate(&category).Error
 	return &category, err
 }
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"cashflow_gin/models"
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CategoryRuleRepository interface {
+	Create(ctx context.Context, rule *models.CategoryRule) error
+	Update(ctx context.Context, rule *models.CategoryRule) error
+	Delete(ctx context.Context, userID, ruleID uuid.UUID) (bool, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID, activeOnly bool) ([]models.CategoryRule, error)
+	FindByIDAndUserID(ctx context.Context, ruleID, userID uuid.UUID) (*models.CategoryRule, error)
+	NextPriority(ctx context.Context, userID uuid.UUID) (int, error)
+	// UpdatePriorities set priority = index di slice (0, 1, 2, ...).
+	UpdatePriorities(ctx context.Context, userID uuid.UUID, ruleIDs []uuid.UUID) error
+}
+
+type categoryRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRuleRepository(db *gorm.DB) CategoryRuleRepository {
+	return &categoryRuleRepository{db: db}
+}
+
+func (r *categoryRuleRepository) Create(ctx context.Context, rule *models.CategoryRule) error {
+	return r.db.WithContext(ctx).Omit("Category").Create(rule).Error
+}
+
+func (r *categoryRuleRepository) Update(ctx context.Context, rule *models.CategoryRule) error {
+	return r.db.WithContext(ctx).Omit("Category").Save(rule).Error
+}
+
+func (r *categoryRuleRepository) Delete(ctx context.Context, userID, ruleID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", ruleID, userID).Delete(&models.CategoryRule{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *categoryRuleRepository) FindByUserID(ctx context.Context, userID uuid.UUID, activeOnly bool) ([]models.CategoryRule, error) {
+	var rules []models.CategoryRule
+	query := r.db.WithContext(ctx).Preload("Category").Where("user_id = ?", userID)
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	err := query.Order("priority ASC, created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *categoryRuleRepository) FindByIDAndUserID(ctx context.Context, ruleID, userID uuid.UUID) (*models.CategoryRule, error) {
+	var rule models.CategoryRule
+	err := r.db.WithContext(ctx).Preload("Category").First(&rule, "id = ? AND user_id = ?", ruleID, userID).Error
+	return &rule, err
+}
+
+func (r *categoryRuleRepository) NextPriority(ctx context.Context, userID uuid.UUID) (int, error) {
+	var max *int
+	err := r.db.WithContext(ctx).Model(&models.CategoryRule{}).
+		Where("user_id = ?", userID).
+		Select("MAX(priority)").
+		Scan(&max).Error
+	if err != nil || max == nil {
+		return 0, err
+	}
+	return *max + 1, nil
+}
+
+func (r *categoryRuleRepository) UpdatePriorities(ctx context.Context, userID uuid.UUID, ruleIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range ruleIDs {
+			if err := tx.Model(&models.CategoryRule{}).
+				Where("id = ? AND user_id = ?", id, userID).
+				Update("priority", i).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
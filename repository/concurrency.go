@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/models"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrStaleVersion: UPDATE ... WHERE version = ? gak kena baris apa pun, artinya data udah
+// diubah request lain di antara baca & tulis.
+var ErrStaleVersion = apperror.Conflict("stale_version", "data sudah diubah oleh request lain, ambil ulang lalu coba lagi")
+
+// lockWallets = SELECT ... FOR UPDATE ke wallet yang saldonya mau diubah. Urutannya disortir
+// biar dua transaksi yang ngunci wallet yang sama gak saling deadlock.
+func lockWallets(tx *gorm.DB, walletIDs ...uuid.UUID) error {
+	if len(walletIDs) == 0 {
+		return nil
+	}
+	ids := append([]uuid.UUID(nil), walletIDs...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+
+	var locked []uuid.UUID
+	return tx.Model(&models.Wallet{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).
+		Order("id").
+		Pluck("id", &locked).Error
+}
+
+// applyWalletDeltas kunci semua wallet yang kena, terus geser saldonya sesuai delta.
+func applyWalletDeltas(tx *gorm.DB, walletDeltas map[uuid.UUID]float64) error {
+	var ids []uuid.UUID
+	for walletID, delta := range walletDeltas {
+		if delta != 0 {
+			ids = append(ids, walletID)
+		}
+	}
+	if err := lockWallets(tx, ids...); err != nil {
+		return err
+	}
+
+	for _, walletID := range ids {
+		if err := tx.Model(&models.Wallet{}).
+			Where("id = ?", walletID).
+			Updates(map[string]interface{}{
+				"balance":    gorm.Expr("balance + ?", walletDeltas[walletID]),
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateTransactionVersioned pengganti db.Save: cuma nulis kalau version di DB masih sama
+// dengan yang dibaca, lalu version dinaikin. Lines != nil = lines transaksi ikut diganti.
+func updateTransactionVersioned(tx *gorm.DB, transaction *models.Transaction) error {
+	result := tx.Model(&models.Transaction{}).
+		Where("id = ? AND version = ?", transaction.ID, transaction.Version).
+		Updates(map[string]interface{}{
+			"title":       transaction.Title,
+			"amount":      transaction.Amount,
+			"description": transaction.Description,
+			"date":        transaction.Date,
+			"category_id": transaction.CategoryID,
+			"payee_id":    transaction.PayeeID,
+			"wallet_id":   transaction.WalletID,
+			"tags":        transaction.Tags,
+			"version":     gorm.Expr("version + 1"),
+			"updated_at":  time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+	transaction.Version++
+
+	if transaction.Lines != nil {
+		return replaceTransactionLines(tx, transaction)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"cashflow_gin/apperror"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrorTranslator = plugin GORM yang nerjemahin error database jadi apperror di batas repository,
+// jadi service & controller gak perlu kenal gorm.ErrRecordNotFound / kode error postgres.
+// Error aslinya tetap ke-wrap, errors.Is(err, gorm.ErrRecordNotFound) masih jalan.
+// Butuh gorm.Config{TranslateError: true} biar unique/FK violation jadi gorm.ErrDuplicatedKey dkk.
+type ErrorTranslator struct{}
+
+func (ErrorTranslator) Name() string { return "apperror" }
+
+func (ErrorTranslator) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().After("gorm:create").Register("apperror:create", translateError),
+		cb.Query().After("gorm:query").Register("apperror:query", translateError),
+		cb.Update().After("gorm:update").Register("apperror:update", translateError),
+		cb.Delete().After("gorm:delete").Register("apperror:delete", translateError),
+	)
+}
+
+func translateError(tx *gorm.DB) {
+	if tx.Error != nil {
+		tx.Error = TranslateError(tx.Error)
+	}
+}
+
+// TranslateError dipakai juga langsung buat error yang gak lewat callback (misal Row().Scan()).
+func TranslateError(err error) error {
+	var appErr *apperror.Error
+	switch {
+	case err == nil || errors.As(err, &appErr):
+		return err
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return apperror.ErrNotFound.Wrap(err)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return apperror.Conflict("duplicate", "Data sudah ada").Wrap(err)
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return apperror.Validation("invalid_reference", "Data yang dirujuk tidak ada atau masih dipakai").Wrap(err)
+	case errors.Is(err, gorm.ErrCheckConstraintViolated):
+		return apperror.Validation("constraint_violated", "Data tidak memenuhi aturan").Wrap(err)
+	}
+	return err
+}
@@ -2,24 +2,25 @@ package repository
 
 import (
 	"cashflow_gin/models"
-	"fmt"
+	"context"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type GroupRepository interface {
-	CreateGroupWithWalletAndMembers(group *models.Group, wallet *models.Wallet, members *[]models.GroupMember) error
-	GetAllGroups() (*[]models.Group, error)
+	CreateGroupWithWalletAndMembers(ctx context.Context, group *models.Group, wallet *models.Wallet, members *[]models.GroupMember) error
+	GetAllGroups(ctx context.Context) (*[]models.Group, error)
 
-	IsGroupWallet(walletID uuid.UUID) (bool, error)
-	IsGroupMember(groupID, userID uuid.UUID) (bool, error)
-	GetGroupByID(groupID uuid.UUID) (*models.Group, error)
-	UpdateGroup(group *models.Group) error
-	DeleteGroup(groupID uuid.UUID) error
+	IsGroupWallet(ctx context.Context, walletID uuid.UUID) (bool, error)
+	IsGroupMember(ctx context.Context, groupID, userID uuid.UUID) (bool, error)
+	GetGroupByID(ctx context.Context, groupID uuid.UUID) (*models.Group, error)
+	UpdateGroup(ctx context.Context, group *models.Group) error
+	DeleteGroup(ctx context.Context, groupID uuid.UUID) error
 
-	CreateMembers(members []models.GroupMember) error
-	RemoveUserFromGroup(groupID, userID uuid.UUID) error
+	CreateMembers(ctx context.Context, members []models.GroupMember) error
+	RemoveUserFromGroup(ctx context.Context, groupID, userID uuid.UUID, version int) error
 }
 
 type groupRepository struct {
@@ -30,8 +31,8 @@ func NewGroupRepository(db *gorm.DB) GroupRepository {
 	return &groupRepository{db: db}
 }
 
-func (r *groupRepository) CreateGroupWithWalletAndMembers(group *models.Group, wallet *models.Wallet, members *[]models.GroupMember) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *groupRepository) CreateGroupWithWalletAndMembers(ctx context.Context, group *models.Group, wallet *models.Wallet, members *[]models.GroupMember) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// A. Create Group dulu (biar dapet ID Group)
 		if err := tx.Create(group).Error; err != nil {
 			return err
@@ -57,56 +58,81 @@ func (r *groupRepository) CreateGroupWithWalletAndMembers(group *models.Group, w
 	})
 }
 
-func (r *groupRepository) GetAllGroups() (*[]models.Group, error) {
+func (r *groupRepository) GetAllGroups(ctx context.Context) (*[]models.Group, error) {
 	var groups []models.Group
 
-	err := r.db.
-		Table("groups").
-		Select(`
-			groups.*,(
-				SELECT COUNT(*)
-				FROM group_members
-				WHERE group_members.group_id = groups.id
-			) AS member_count
-		`).Preload("Wallet").Find(&groups).Error
+	// "groups" keyword di SQLite, jadi nama tabel harus di-quote sesuai dialect (clause.Table / clause.Column)
+	memberCount := r.db.Model(&models.GroupMember{}).
+		Select("COUNT(*)").
+		Where("group_members.group_id = ?", clause.Column{Table: "groups", Name: "id"})
+	err := r.db.WithContext(ctx).
+		Select("?.*, (?) AS member_count", clause.Table{Name: "groups"}, memberCount).
+		Preload("Wallet").
+		Find(&groups).Error
 
 	return &groups, err
 }
 
-func (r *groupRepository) GetGroupByID(groupID uuid.UUID) (*models.Group, error) {
+func (r *groupRepository) GetGroupByID(ctx context.Context, groupID uuid.UUID) (*models.Group, error) {
 	var group models.Group
-	err := r.db.Preload("Wallet").Preload("Members").Preload("Members.User").First(&group, "id = ?", groupID).Error
+	err := r.db.WithContext(ctx).Preload("Wallet").Preload("Members").Preload("Members.User").First(&group, "id = ?", groupID).Error
 	return &group, err
 }
 
-func (r *groupRepository) UpdateGroup(group *models.Group) error {
-	return r.db.Save(group).Error
+func (r *groupRepository) UpdateGroup(ctx context.Context, group *models.Group) error {
+	result := r.db.WithContext(ctx).Model(&models.Group{}).
+		Where("id = ? AND version = ?", group.ID, group.Version).
+		Updates(map[string]interface{}{
+			"name":        group.Name,
+			"description": group.Description,
+			"version":     gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+	group.Version++
+	return nil
 }
 
-func (r *groupRepository) DeleteGroup(groupID uuid.UUID) error {
-	return r.db.Delete(&models.Group{}, "id = ?", groupID).Error
+func (r *groupRepository) DeleteGroup(ctx context.Context, groupID uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Group{}, "id = ?", groupID).Error
 }
 
 // repository/group.go
-func (r *groupRepository) CreateMembers(members []models.GroupMember) error {
+func (r *groupRepository) CreateMembers(ctx context.Context, members []models.GroupMember) error {
 	// Langsung gas simpan.
 	// Gak perlu cek GroupID ada atau gak, karena Foreign Key Database bakal nolak otomatis kalau gak ada.
-	return r.db.Create(&members).Error
+	return r.db.WithContext(ctx).Create(&members).Error
 }
 
-func (r *groupRepository) RemoveUserFromGroup(groupID, userID uuid.UUID) error {
-	return r.db.Where("group_id = ? AND user_id = ?", groupID, userID).Delete(&models.GroupMember{}).Error
+// RemoveUserFromGroup ikut naikin version group (cek version dulu) biar perubahan member gak balapan.
+func (r *groupRepository) RemoveUserFromGroup(ctx context.Context, groupID, userID uuid.UUID, version int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Group{}).
+			Where("id = ? AND version = ?", groupID, version).
+			Update("version", gorm.Expr("version + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStaleVersion
+		}
+
+		return tx.Where("group_id = ? AND user_id = ?", groupID, userID).Delete(&models.GroupMember{}).Error
+	})
 }
 
-func (r *groupRepository) IsGroupWallet(walletID uuid.UUID) (bool, error) {
+func (r *groupRepository) IsGroupWallet(ctx context.Context, walletID uuid.UUID) (bool, error) {
 	var count int64
-	err := r.db.Model(&models.Wallet{}).Where("id = ? AND group_id IS NOT NULL", walletID).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&models.Wallet{}).Where("id = ? AND group_id IS NOT NULL", walletID).Count(&count).Error
 	return count > 0, err
 }
 
-func (r *groupRepository) IsGroupMember(groupID, userID uuid.UUID) (bool, error) {
+func (r *groupRepository) IsGroupMember(ctx context.Context, groupID, userID uuid.UUID) (bool, error) {
 	var count int64
-	err := r.db.Model(&models.GroupMember{}).Where("group_id = ? AND user_id = ?", groupID, userID).Count(&count).Error
-	fmt.Println("Count : ", count)
+	err := r.db.WithContext(ctx).Model(&models.GroupMember{}).Where("group_id = ? AND user_id = ?", groupID, userID).Count(&count).Error
 	return count > 0, err
 }
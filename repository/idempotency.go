@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"cashflow_gin/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository interface {
+	Find(ctx context.Context, userID uuid.UUID, key string) (*models.IdempotencyKey, error)
+	Reserve(ctx context.Context, record *models.IdempotencyKey) (bool, error)
+	Complete(ctx context.Context, id uuid.UUID, statusCode int, contentType, body string) error
+	Release(ctx context.Context, id uuid.UUID) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// Find cuma balikin key yang belum expired, nil kalau gak ada.
+func (r *idempotencyRepository) Find(ctx context.Context, userID uuid.UUID, key string) (*models.IdempotencyKey, error) {
+	var record models.IdempotencyKey
+	err := r.db.WithContext(ctx).Where("user_id = ? AND key = ? AND expires_at > ?", userID, key, time.Now()).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Reserve insert key baru. false = key udah dipegang request lain (balapan retry).
+// Sisa key lama yang udah expired, atau yang ditinggal request yang mati di tengah jalan (belum completed
+// & lease-nya habis), dibuang dulu biar unique index gak nahan.
+func (r *idempotencyRepository) Reserve(ctx context.Context, record *models.IdempotencyKey) (bool, error) {
+	var created bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Unscoped().
+			Where("user_id = ? AND key = ?", record.UserID, record.Key).
+			Where("expires_at <= ? OR (completed = ? AND (locked_until IS NULL OR locked_until <= ?))", now, false, now).
+			Delete(&models.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if result.Error != nil {
+			return result.Error
+		}
+		created = result.RowsAffected > 0
+		return nil
+	})
+	return created, err
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, id uuid.UUID, statusCode int, contentType, body string) error {
+	return r.db.WithContext(ctx).Model(&models.IdempotencyKey{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"completed":     true,
+		"status_code":   statusCode,
+		"content_type":  contentType,
+		"response_body": body,
+	}).Error
+}
+
+// Release hapus key (dipakai kalau request gagal 5xx) biar client boleh retry pakai key yang sama.
+func (r *idempotencyRepository) Release(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Unscoped().Where("id = ?", id).Delete(&models.IdempotencyKey{}).Error
+}
+
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().Where("expires_at <= ?", now).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type apiKeyRepository struct {
+	s *Store
+}
+
+func NewAPIKeyRepository(s *Store) repository.APIKeyRepository {
+	return &apiKeyRepository{s: s}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if !r.s.users.exists(key.UserID) {
+		return errInvalidReference
+	}
+	taken := r.s.apiKeys.whereAll(func(k *models.APIKey) bool { return k.Prefix == key.Prefix || k.KeyHash == key.KeyHash })
+	if len(taken) > 0 {
+		return errDuplicate
+	}
+	stamp(&key.Base, time.Now())
+	row := *key
+	row.User = models.User{}
+	r.s.apiKeys.insert(row.ID, row)
+	return nil
+}
+
+func (r *apiKeyRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	keys := values(r.s.apiKeys.where(func(k *models.APIKey) bool { return k.UserID == userID }))
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+// FindByHash sekalian isi User. Kalau user-nya udah dihapus, User kosong (kayak LEFT JOIN) -> dicek di Service.
+func (r *apiKeyRepository) FindByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	stored, ok := r.s.apiKeys.first(func(k *models.APIKey) bool { return k.KeyHash == keyHash })
+	if !ok {
+		return &models.APIKey{}, errNotFound
+	}
+	key := *stored
+	if user, ok := r.s.users.get(key.UserID); ok {
+		key.User = *user
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) Delete(ctx context.Context, userID, keyID uuid.UUID) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key, ok := r.s.apiKeys.get(keyID)
+	if !ok || key.UserID != userID {
+		return false, nil
+	}
+	return r.s.apiKeys.softDelete(keyID), nil
+}
+
+// TouchLastUsed gak ikut ngubah updated_at (UpdateColumn di versi GORM).
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, keyID uuid.UUID, usedAt time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if key, ok := r.s.apiKeys.get(keyID); ok {
+		key.LastUsedAt = &usedAt
+	}
+	return nil
+}
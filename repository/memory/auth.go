@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"cashflow_gin/dto/request"
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type authRepository struct {
+	s *Store
+}
+
+func NewAuthRepository(s *Store) repository.AuthRepository {
+	return &authRepository{s: s}
+}
+
+func (r *authRepository) Login(ctx context.Context, input *request.LoginRequest) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.findUserByEmail(input.Email)
+}
+
+// Register sama kayak versi GORM: cuma bikin user kosong, input-nya gak dipakai.
+func (r *authRepository) Register(ctx context.Context, input *request.CreateUserRequest) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var user models.User
+	if err := r.s.insertUser(&user, time.Now()); err != nil {
+		return &user, err
+	}
+	return &user, nil
+}
+
+func (r *authRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.findUserByEmail(email)
+}
+
+func (r *authRepository) CreateUserWithWallet(ctx context.Context, user *models.User, wallet *models.Wallet) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+
+	if err := r.s.insertUser(user, now); err != nil {
+		return err
+	}
+	wallet.UserID = &user.ID
+	return r.s.insertWallet(wallet, now)
+}
+
+func (r *authRepository) IncrementFailedLogin(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users.get(userID)
+	if !ok {
+		return 0, errNotFound
+	}
+	user.FailedLoginAttempts++
+	user.UpdatedAt = time.Now()
+	return user.FailedLoginAttempts, nil
+}
+
+func (r *authRepository) LockAccount(ctx context.Context, userID uuid.UUID, until time.Time) error {
+	return r.updateUser(userID, func(u *models.User) {
+		u.FailedLoginAttempts = 0
+		u.LockedUntil = &until
+	})
+}
+
+func (r *authRepository) ResetFailedLogin(ctx context.Context, userID uuid.UUID) error {
+	return r.updateUser(userID, func(u *models.User) {
+		u.FailedLoginAttempts = 0
+		u.LockedUntil = nil
+	})
+}
+
+func (r *authRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.findUser(id)
+}
+
+func (r *authRepository) SaveTwoFactorSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	return r.updateUser(userID, func(u *models.User) {
+		u.TwoFactorSecret = secret
+	})
+}
+
+// EnableTwoFactor nyalain 2FA & ganti semua recovery code lama dengan yang baru (atomic).
+func (r *authRepository) EnableTwoFactor(ctx context.Context, userID uuid.UUID, codes []models.RecoveryCode) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+
+	if user, ok := r.s.users.get(userID); ok {
+		user.TwoFactorEnabled = true
+		user.UpdatedAt = now
+	}
+	r.s.deleteRecoveryCodes(userID)
+	for i := range codes {
+		codes[i].UserID = userID
+		stamp(&codes[i].Base, now)
+		r.s.recoveryCodes.insert(codes[i].ID, codes[i])
+	}
+	return nil
+}
+
+func (r *authRepository) DisableTwoFactor(ctx context.Context, userID uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if user, ok := r.s.users.get(userID); ok {
+		user.TwoFactorEnabled = false
+		user.TwoFactorSecret = ""
+		user.UpdatedAt = time.Now()
+	}
+	r.s.deleteRecoveryCodes(userID)
+	return nil
+}
+
+// UseRecoveryCode nandain recovery code kepake. Return false kalau kode gak ada / udah dipakai.
+func (r *authRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	code, ok := r.s.recoveryCodes.first(func(c *models.RecoveryCode) bool {
+		return c.UserID == userID && c.CodeHash == codeHash && c.UsedAt == nil
+	})
+	if !ok {
+		return false, nil
+	}
+	now := time.Now()
+	code.UsedAt = &now
+	code.UpdatedAt = now
+	return true, nil
+}
+
+func (r *authRepository) updateUser(userID uuid.UUID, update func(u *models.User)) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if user, ok := r.s.users.get(userID); ok {
+		update(user)
+		user.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (s *Store) insertUser(user *models.User, now time.Time) error {
+	if s.userTaken(user.ID, user.Username, user.Email) {
+		return errDuplicate
+	}
+	stamp(&user.Base, now)
+	if s.users.exists(user.ID) {
+		return errDuplicate
+	}
+	row := *user
+	row.Wallets = nil
+	s.users.insert(row.ID, row)
+	return nil
+}
+
+func (s *Store) deleteRecoveryCodes(userID uuid.UUID) {
+	for _, c := range s.recoveryCodes.whereAll(func(c *models.RecoveryCode) bool { return c.UserID == userID }) {
+		s.recoveryCodes.hardDelete(c.ID)
+	}
+}
@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type categoryRepository struct {
+	s *Store
+}
+
+func NewCategoryRepository(s *Store) repository.CategoryRepository {
+	return &categoryRepository{s: s}
+}
+
+func (r *categoryRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Category, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.findCategory(func(c *models.Category) bool { return c.ID == id })
+}
+
+func (r *categoryRepository) CreateDefaultCategories(ctx context.Context) (*[]models.Category, error) {
+	categories := []models.Category{
+		{Name: "Salary", Type: "INCOME"},
+		{Name: "Freelance", Type: "INCOME"},
+		{Name: "Payment Received", Type: "INCOME"},
+		{Name: "Gift", Type: "INCOME"},
+
+		{Name: "Groceries", Type: "EXPENSE"},
+		{Name: "Food", Type: "EXPENSE"},
+		{Name: "Clothing", Type: "EXPENSE"},
+		{Name: "Debt", Type: "EXPENSE"},
+		{Name: "Subscription", Type: "EXPENSE"},
+		{Name: "Utilities", Type: "EXPENSE"},
+		{Name: "Transport", Type: "EXPENSE"},
+		{Name: "Entertainment", Type: "EXPENSE"},
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	// Batch insert: satu nama bentrok = gak ada yang masuk
+	for _, c := range categories {
+		if r.s.categoryNameTaken(uuid.Nil, c.Name) {
+			return &categories, errDuplicate
+		}
+	}
+	now := time.Now()
+	for i := range categories {
+		if err := r.s.insertCategory(&categories[i], now); err != nil {
+			return &categories, err
+		}
+	}
+	return &categories, nil
+}
+
+func (r *categoryRepository) FindAll(ctx context.Context) (*[]models.Category, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	categories := values(r.s.categories.where(nil))
+	return &categories, nil
+}
+
+func (r *categoryRepository) FindByName(ctx context.Context, name string) (*models.Category, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.findCategory(func(c *models.Category) bool { return c.Name == name })
+}
+
+func (r *categoryRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*[]models.Category, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	categories := values(r.s.categories.where(func(c *models.Category) bool { return c.UserID == userID }))
+	return &categories, nil
+}
+
+func (r *categoryRepository) FindByGroupID(ctx context.Context, groupID uuid.UUID) (*[]models.Category, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	categories := values(r.s.categories.where(func(c *models.Category) bool { return c.GroupID != nil && *c.GroupID == groupID }))
+	return &categories, nil
+}
+
+func (r *categoryRepository) Create(ctx context.Context, category *models.Category) (*models.Category, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	err := r.s.insertCategory(category, time.Now())
+	return category, err
+}
+
+// Update & Delete cek version (optimistic locking), ErrStaleVersion kalau udah diubah orang lain.
+func (r *categoryRepository) Update(ctx context.Context, category *models.Category) (*models.Category, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.categories.get(category.ID)
+	if !ok || stored.Version != category.Version {
+		return category, repository.ErrStaleVersion
+	}
+	if r.s.categoryNameTaken(category.ID, category.Name) {
+		return category, errDuplicate
+	}
+	stored.Name = category.Name
+	stored.Type = category.Type
+	stored.GroupID = category.GroupID
+	stored.Version++
+	stored.UpdatedAt = time.Now()
+	category.Version++
+	return category, nil
+}
+
+func (r *categoryRepository) Delete(ctx context.Context, category *models.Category) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.categories.get(category.ID)
+	if !ok || stored.Version != category.Version {
+		return repository.ErrStaleVersion
+	}
+	r.s.categories.softDelete(category.ID)
+	return nil
+}
+
+func (r *categoryRepository) FindByIDAndUserID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Category, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.findCategory(func(c *models.Category) bool { return c.ID == id && c.UserID == userID })
+}
+
+// FindOrCreate dipakai buat kategori sistem (mis. "Uncategorized") yang dibikin on demand.
+func (r *categoryRepository) FindOrCreate(ctx context.Context, name, categoryType string) (*models.Category, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if category, ok := r.s.categories.first(func(c *models.Category) bool { return c.Name == name }); ok {
+		return ptr(*category), nil
+	}
+	category := &models.Category{Name: name, Type: categoryType}
+	err := r.s.insertCategory(category, time.Now())
+	return category, err
+}
+
+func (s *Store) findCategory(match func(c *models.Category) bool) (*models.Category, error) {
+	category, ok := s.categories.first(match)
+	if !ok {
+		return &models.Category{}, errNotFound
+	}
+	return ptr(*category), nil
+}
+
+// category = preload relasi Category, kosong kalau kategorinya udah dihapus.
+func (s *Store) category(id uuid.UUID) models.Category {
+	if category, ok := s.categories.get(id); ok {
+		return *category
+	}
+	return models.Category{}
+}
+
+func (s *Store) insertCategory(category *models.Category, now time.Time) error {
+	if s.categoryNameTaken(category.ID, category.Name) {
+		return errDuplicate
+	}
+	stamp(&category.Base, now)
+	if category.Version == 0 {
+		category.Version = 1 // default kolom version
+	}
+	row := *category
+	row.Transaction = nil
+	s.categories.insert(row.ID, row)
+	return nil
+}
+
+// categoryNameTaken = unique kolom name (global, bukan per user), termasuk kategori yang udah dihapus.
+func (s *Store) categoryNameTaken(id uuid.UUID, name string) bool {
+	return len(s.categories.whereAll(func(c *models.Category) bool { return c.ID != id && c.Name == name })) > 0
+}
@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type categoryRuleRepository struct {
+	s *Store
+}
+
+func NewCategoryRuleRepository(s *Store) repository.CategoryRuleRepository {
+	return &categoryRuleRepository{s: s}
+}
+
+func (r *categoryRuleRepository) Create(ctx context.Context, rule *models.CategoryRule) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if !r.s.categories.exists(rule.CategoryID) {
+		return errInvalidReference
+	}
+	stamp(&rule.Base, time.Now())
+	row := *rule
+	row.Category = models.Category{}
+	r.s.categoryRules.insert(row.ID, row)
+	return nil
+}
+
+// Update = db.Save: semua kolom ditimpa, belum ada = di-insert.
+func (r *categoryRuleRepository) Update(ctx context.Context, rule *models.CategoryRule) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if !r.s.categories.exists(rule.CategoryID) {
+		return errInvalidReference
+	}
+	now := time.Now()
+	row := *rule
+	row.Category = models.Category{}
+	row.UpdatedAt = now
+	rule.UpdatedAt = now
+	if stored, ok := r.s.categoryRules.get(rule.ID); ok {
+		*stored = row
+		return nil
+	}
+	stamp(&rule.Base, now)
+	row.Base = rule.Base
+	r.s.categoryRules.insert(row.ID, row)
+	return nil
+}
+
+func (r *categoryRuleRepository) Delete(ctx context.Context, userID, ruleID uuid.UUID) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	rule, ok := r.s.categoryRules.get(ruleID)
+	if !ok || rule.UserID != userID {
+		return false, nil
+	}
+	return r.s.categoryRules.softDelete(ruleID), nil
+}
+
+func (r *categoryRuleRepository) FindByUserID(ctx context.Context, userID uuid.UUID, activeOnly bool) ([]models.CategoryRule, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	rules := values(r.s.categoryRules.where(func(rule *models.CategoryRule) bool {
+		return rule.UserID == userID && (!activeOnly || rule.Active)
+	}))
+	for i := range rules {
+		rules[i].Category = r.s.category(rules[i].CategoryID)
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority < rules[j].Priority
+		}
+		return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+	})
+	return rules, nil
+}
+
+func (r *categoryRuleRepository) FindByIDAndUserID(ctx context.Context, ruleID, userID uuid.UUID) (*models.CategoryRule, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	stored, ok := r.s.categoryRules.get(ruleID)
+	if !ok || stored.UserID != userID {
+		return &models.CategoryRule{}, errNotFound
+	}
+	rule := *stored
+	rule.Category = r.s.category(rule.CategoryID)
+	return &rule, nil
+}
+
+func (r *categoryRuleRepository) NextPriority(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	rules := r.s.categoryRules.where(func(rule *models.CategoryRule) bool { return rule.UserID == userID })
+	if len(rules) == 0 {
+		return 0, nil
+	}
+	max := rules[0].Priority
+	for _, rule := range rules[1:] {
+		if rule.Priority > max {
+			max = rule.Priority
+		}
+	}
+	return max + 1, nil
+}
+
+func (r *categoryRuleRepository) UpdatePriorities(ctx context.Context, userID uuid.UUID, ruleIDs []uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	for i, id := range ruleIDs {
+		if rule, ok := r.s.categoryRules.get(id); ok && rule.UserID == userID {
+			rule.Priority = i
+			rule.UpdatedAt = now
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package memory
+
+import (
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type groupRepository struct {
+	s *Store
+}
+
+func NewGroupRepository(s *Store) repository.GroupRepository {
+	return &groupRepository{s: s}
+}
+
+func (r *groupRepository) CreateGroupWithWalletAndMembers(ctx context.Context, group *models.Group, wallet *models.Wallet, members *[]models.GroupMember) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+
+	// Semua constraint dicek di depan, biar gak ada yang setengah jadi kalau salah satu gagal
+	if wallet.UserID != nil && !r.s.users.exists(*wallet.UserID) {
+		return errInvalidReference
+	}
+	seen := make(map[uuid.UUID]bool)
+	for _, m := range *members {
+		if !r.s.users.exists(m.UserID) {
+			return errInvalidReference
+		}
+		if seen[m.UserID] {
+			return errDuplicate
+		}
+		seen[m.UserID] = true
+	}
+
+	r.s.insertGroup(group, now)
+
+	wallet.GroupID = &group.ID
+	if err := r.s.insertWallet(wallet, now); err != nil {
+		return err
+	}
+
+	for i := range *members {
+		(*members)[i].GroupID = group.ID
+		if err := r.s.insertGroupMember(&(*members)[i], now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *groupRepository) GetAllGroups(ctx context.Context) (*[]models.Group, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	groups := values(r.s.groups.where(nil))
+	for i := range groups {
+		groups[i].MemberCount = int64(len(r.s.members(groups[i].ID)))
+		groups[i].Wallet = r.s.groupWallets(groups[i].ID)
+	}
+	return &groups, nil
+}
+
+func (r *groupRepository) GetGroupByID(ctx context.Context, groupID uuid.UUID) (*models.Group, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	stored, ok := r.s.groups.get(groupID)
+	if !ok {
+		return &models.Group{}, errNotFound
+	}
+	group := *stored
+	group.Wallet = r.s.groupWallets(groupID)
+	group.Members = values(r.s.members(groupID))
+	for i := range group.Members {
+		if user, ok := r.s.users.get(group.Members[i].UserID); ok {
+			group.Members[i].User = *user
+		}
+	}
+	return &group, nil
+}
+
+func (r *groupRepository) UpdateGroup(ctx context.Context, group *models.Group) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.groups.get(group.ID)
+	if !ok || stored.Version != group.Version {
+		return repository.ErrStaleVersion
+	}
+	stored.Name = group.Name
+	stored.Description = group.Description
+	stored.Version++
+	stored.UpdatedAt = time.Now()
+	group.Version++
+	return nil
+}
+
+func (r *groupRepository) DeleteGroup(ctx context.Context, groupID uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.groups.softDelete(groupID)
+	return nil
+}
+
+func (r *groupRepository) CreateMembers(ctx context.Context, members []models.GroupMember) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+
+	seen := make(map[uuid.UUID]bool)
+	for _, m := range members {
+		if !r.s.groups.exists(m.GroupID) || !r.s.users.exists(m.UserID) {
+			return errInvalidReference
+		}
+		if seen[m.UserID] || r.s.memberTaken(m.GroupID, m.UserID) {
+			return errDuplicate
+		}
+		seen[m.UserID] = true
+	}
+	for i := range members {
+		if err := r.s.insertGroupMember(&members[i], now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveUserFromGroup ikut naikin version group (cek version dulu) biar perubahan member gak balapan.
+func (r *groupRepository) RemoveUserFromGroup(ctx context.Context, groupID, userID uuid.UUID, version int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	group, ok := r.s.groups.get(groupID)
+	if !ok || group.Version != version {
+		return repository.ErrStaleVersion
+	}
+	group.Version++
+	group.UpdatedAt = time.Now()
+
+	for _, m := range r.s.groupMembers.where(func(m *models.GroupMember) bool { return m.GroupID == groupID && m.UserID == userID }) {
+		r.s.groupMembers.softDelete(m.ID)
+	}
+	return nil
+}
+
+func (r *groupRepository) IsGroupWallet(ctx context.Context, walletID uuid.UUID) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	wallet, ok := r.s.wallets.get(walletID)
+	return ok && wallet.GroupID != nil, nil
+}
+
+func (r *groupRepository) IsGroupMember(ctx context.Context, groupID, userID uuid.UUID) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.isMember(groupID, userID), nil
+}
+
+func (s *Store) insertGroup(group *models.Group, now time.Time) {
+	stamp(&group.Base, now)
+	if group.Version == 0 {
+		group.Version = 1 // default kolom version
+	}
+	row := *group
+	row.Members = nil
+	row.Wallet = nil
+	row.MemberCount = 0
+	s.groups.insert(row.ID, row)
+}
+
+func (s *Store) insertGroupMember(member *models.GroupMember, now time.Time) error {
+	if s.memberTaken(member.GroupID, member.UserID) {
+		return errDuplicate
+	}
+	stamp(&member.Base, now)
+	if member.MembersRole == 0 {
+		member.MembersRole = models.GroupParticipant // default kolom members_role
+	}
+	row := *member
+	row.User = models.User{}
+	row.Group = models.Group{}
+	s.groupMembers.insert(row.ID, row)
+	return nil
+}
+
+// memberTaken = unique index (group_id, user_id), termasuk member yang udah di-soft delete.
+func (s *Store) memberTaken(groupID, userID uuid.UUID) bool {
+	return len(s.groupMembers.whereAll(func(m *models.GroupMember) bool {
+		return m.GroupID == groupID && m.UserID == userID
+	})) > 0
+}
+
+func (s *Store) members(groupID uuid.UUID) []*models.GroupMember {
+	return s.groupMembers.where(func(m *models.GroupMember) bool { return m.GroupID == groupID })
+}
+
+func (s *Store) isMember(groupID, userID uuid.UUID) bool {
+	_, ok := s.groupMembers.first(func(m *models.GroupMember) bool { return m.GroupID == groupID && m.UserID == userID })
+	return ok
+}
+
+func (s *Store) groupWallets(groupID uuid.UUID) []models.Wallet {
+	return values(s.wallets.where(func(w *models.Wallet) bool { return w.GroupID != nil && *w.GroupID == groupID }))
+}
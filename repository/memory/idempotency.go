@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type idempotencyRepository struct {
+	s *Store
+}
+
+func NewIdempotencyRepository(s *Store) repository.IdempotencyRepository {
+	return &idempotencyRepository{s: s}
+}
+
+// Find cuma balikin key yang belum expired, nil kalau gak ada.
+func (r *idempotencyRepository) Find(ctx context.Context, userID uuid.UUID, key string) (*models.IdempotencyKey, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	now := time.Now()
+	record, ok := r.s.idempotencyKeys.first(func(k *models.IdempotencyKey) bool {
+		return k.UserID == userID && k.Key == key && k.ExpiresAt.After(now)
+	})
+	if !ok {
+		return nil, nil
+	}
+	return ptr(*record), nil
+}
+
+// Reserve insert key baru. false = key udah dipegang request lain (balapan retry).
+// Sisa key lama yang udah expired, atau yang ditinggal request yang mati di tengah jalan (belum completed
+// & lease-nya habis), dibuang dulu biar unique (user_id, key) gak nahan.
+func (r *idempotencyRepository) Reserve(ctx context.Context, record *models.IdempotencyKey) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	for _, k := range r.s.idempotencyKeys.whereAll(func(k *models.IdempotencyKey) bool {
+		return k.UserID == record.UserID && k.Key == record.Key &&
+			(!k.ExpiresAt.After(now) || (!k.Completed && !k.InProgress(now)))
+	}) {
+		r.s.idempotencyKeys.hardDelete(k.ID)
+	}
+
+	taken := r.s.idempotencyKeys.whereAll(func(k *models.IdempotencyKey) bool {
+		return k.UserID == record.UserID && k.Key == record.Key
+	})
+	if len(taken) > 0 {
+		return false, nil // ON CONFLICT DO NOTHING
+	}
+	stamp(&record.Base, now)
+	r.s.idempotencyKeys.insert(record.ID, *record)
+	return true, nil
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, id uuid.UUID, statusCode int, contentType, body string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if record, ok := r.s.idempotencyKeys.get(id); ok {
+		record.Completed = true
+		record.StatusCode = statusCode
+		record.ContentType = contentType
+		record.ResponseBody = body
+		record.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// Release hapus key (dipakai kalau request gagal 5xx) biar client boleh retry pakai key yang sama.
+func (r *idempotencyRepository) Release(ctx context.Context, id uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.idempotencyKeys.hardDelete(id)
+	return nil
+}
+
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var deleted int64
+	for _, k := range r.s.idempotencyKeys.whereAll(func(k *models.IdempotencyKey) bool { return !k.ExpiresAt.After(now) }) {
+		if r.s.idempotencyKeys.hardDelete(k.ID) {
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type notificationRepository struct {
+	s *Store
+}
+
+func NewNotificationRepository(s *Store) repository.NotificationRepository {
+	return &notificationRepository{s: s}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stamp(&notification.Base, time.Now())
+	r.s.notifications.insert(notification.ID, *notification)
+	return nil
+}
+
+func (r *notificationRepository) FindByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]models.Notification, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	notifications := values(r.s.notifications.where(func(n *models.Notification) bool {
+		return n.UserID == userID && (!unreadOnly || n.ReadAt == nil)
+	}))
+	sort.SliceStable(notifications, func(i, j int) bool { return notifications[i].CreatedAt.After(notifications[j].CreatedAt) })
+	return take(notifications, limit), nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return int64(len(r.s.unread(userID))), nil
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	notification, ok := r.s.notifications.get(notificationID)
+	if !ok || notification.UserID != userID {
+		return false, nil
+	}
+	if notification.ReadAt == nil {
+		now := time.Now()
+		notification.ReadAt = &now
+		notification.UpdatedAt = now
+	}
+	return true, nil
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	unread := r.s.unread(userID)
+	for _, n := range unread {
+		n.ReadAt = &now
+		n.UpdatedAt = now
+	}
+	return int64(len(unread)), nil
+}
+
+func (r *notificationRepository) FindPreferences(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return values(r.s.notificationPrefs.where(func(p *models.NotificationPreference) bool { return p.UserID == userID })), nil
+}
+
+// UpsertPreferences insert atau update berdasarkan unique (user_id, type, channel).
+func (r *notificationRepository) UpsertPreferences(ctx context.Context, preferences []models.NotificationPreference) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	for i := range preferences {
+		p := &preferences[i]
+		existing, ok := r.s.notificationPrefs.first(func(e *models.NotificationPreference) bool {
+			return e.UserID == p.UserID && e.Type == p.Type && e.Channel == p.Channel
+		})
+		if ok {
+			existing.Enabled = p.Enabled
+			existing.UpdatedAt = now
+			continue
+		}
+		stamp(&p.Base, now)
+		r.s.notificationPrefs.insert(p.ID, *p)
+	}
+	return nil
+}
+
+func (s *Store) unread(userID uuid.UUID) []*models.Notification {
+	return s.notifications.where(func(n *models.Notification) bool { return n.UserID == userID && n.ReadAt == nil })
+}
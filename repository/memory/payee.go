@@ -0,0 +1,252 @@
+package memory
+
+import (
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type payeeRepository struct {
+	s *Store
+}
+
+func NewPayeeRepository(s *Store) repository.PayeeRepository {
+	return &payeeRepository{s: s}
+}
+
+// Create ikut nyimpen Aliases (GORM juga otomatis insert asosiasi has-many).
+func (r *payeeRepository) Create(ctx context.Context, payee *models.Payee) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if payee.DefaultCategoryID != nil && !r.s.categories.exists(*payee.DefaultCategoryID) {
+		return errInvalidReference
+	}
+	now := time.Now()
+	stamp(&payee.Base, now)
+	row := *payee
+	row.DefaultCategory = nil
+	row.Aliases = nil
+	row.TransactionCount = 0
+	r.s.payees.insert(row.ID, row)
+	r.s.insertAliases(payee.ID, payee.Aliases, now)
+	return nil
+}
+
+// Update nulis field payee + ganti semua alias (alias lama di-hard delete).
+func (r *payeeRepository) Update(ctx context.Context, payee *models.Payee) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if payee.DefaultCategoryID != nil && !r.s.categories.exists(*payee.DefaultCategoryID) {
+		return errInvalidReference
+	}
+	now := time.Now()
+	if stored, ok := r.s.payees.get(payee.ID); ok {
+		stored.Name = payee.Name
+		stored.NormalizedName = payee.NormalizedName
+		stored.DefaultCategoryID = payee.DefaultCategoryID
+		stored.UpdatedAt = now
+	}
+
+	r.s.deleteAliases(payee.ID)
+	for i := range payee.Aliases {
+		payee.Aliases[i].ID = uuid.Nil
+	}
+	r.s.insertAliases(payee.ID, payee.Aliases, now)
+	return nil
+}
+
+// Delete: transaksinya gak ikut kehapus, cuma dilepas dari payee.
+func (r *payeeRepository) Delete(ctx context.Context, payeeID uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	for _, t := range r.s.transactions.where(func(t *models.Transaction) bool { return t.PayeeID != nil && *t.PayeeID == payeeID }) {
+		t.PayeeID = nil
+		t.UpdatedAt = now
+	}
+	r.s.deleteAliases(payeeID)
+	r.s.payees.softDelete(payeeID)
+	return nil
+}
+
+func (r *payeeRepository) FindByID(ctx context.Context, payeeID uuid.UUID) (*models.Payee, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	stored, ok := r.s.payees.get(payeeID)
+	if !ok {
+		return &models.Payee{}, errNotFound
+	}
+	payee := *stored
+	payee.Aliases = r.s.aliases(payeeID)
+	payee.DefaultCategory = r.s.defaultCategory(payee.DefaultCategoryID)
+	return &payee, nil
+}
+
+func (r *payeeRepository) FindByIDs(ctx context.Context, payeeIDs []uuid.UUID) ([]models.Payee, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	ids := idSet(payeeIDs)
+	payees := values(r.s.payees.where(func(p *models.Payee) bool { return ids[p.ID] }))
+	for i := range payees {
+		payees[i].Aliases = r.s.aliases(payees[i].ID)
+	}
+	return payees, nil
+}
+
+// FindByNormalized cari payee di scope yang nama ATAU salah satu alias-nya cocok. nil kalau gak ada.
+func (r *payeeRepository) FindByNormalized(ctx context.Context, scope repository.PayeeScope, normalized string) (*models.Payee, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	candidates := values(r.s.payees.where(func(p *models.Payee) bool {
+		return inScope(p, scope) && (p.NormalizedName == normalized || r.s.hasAlias(p.ID, func(alias string) bool { return alias == normalized }))
+	}))
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].CreatedAt.Before(candidates[j].CreatedAt) })
+	payee := candidates[0]
+	payee.DefaultCategory = r.s.defaultCategory(payee.DefaultCategoryID)
+	return &payee, nil
+}
+
+// Search buat autocomplete: nama/alias diawali query (atau ada kata yang diawali query),
+// payee yang paling sering dipakai muncul duluan.
+func (r *payeeRepository) Search(ctx context.Context, scope repository.PayeeScope, normalizedQuery string, limit int) ([]models.Payee, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	matches := func(s string) bool {
+		return strings.HasPrefix(s, normalizedQuery) || strings.Contains(s, " "+normalizedQuery)
+	}
+	payees := values(r.s.payees.where(func(p *models.Payee) bool {
+		return inScope(p, scope) && (matches(p.NormalizedName) || r.s.hasAlias(p.ID, matches))
+	}))
+	for i := range payees {
+		payeeID := payees[i].ID
+		payees[i].TransactionCount = int64(len(r.s.transactions.where(func(t *models.Transaction) bool {
+			return t.PayeeID != nil && *t.PayeeID == payeeID
+		})))
+		payees[i].Aliases = r.s.aliases(payeeID)
+		payees[i].DefaultCategory = r.s.defaultCategory(payees[i].DefaultCategoryID)
+	}
+	sort.SliceStable(payees, func(i, j int) bool {
+		if payees[i].TransactionCount != payees[j].TransactionCount {
+			return payees[i].TransactionCount > payees[j].TransactionCount
+		}
+		return payees[i].Name < payees[j].Name
+	})
+	return take(payees, limit), nil
+}
+
+// Merge pindahin transaksi sources ke target, alias sources diganti newAliases, lalu sources dihapus.
+func (r *payeeRepository) Merge(ctx context.Context, targetID uuid.UUID, sourceIDs []uuid.UUID, newAliases []models.PayeeAlias) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if !r.s.payees.exists(targetID) {
+		return errInvalidReference
+	}
+	now := time.Now()
+	sources := idSet(sourceIDs)
+	for _, t := range r.s.transactions.where(func(t *models.Transaction) bool { return t.PayeeID != nil && sources[*t.PayeeID] }) {
+		t.PayeeID = &targetID
+		t.UpdatedAt = now
+	}
+	for _, sourceID := range sourceIDs {
+		r.s.deleteAliases(sourceID)
+	}
+	r.s.insertAliases(targetID, newAliases, now)
+	for _, sourceID := range sourceIDs {
+		r.s.payees.softDelete(sourceID)
+	}
+	return nil
+}
+
+// FindTransactions = riwayat transaksi payee, terbaru dulu. from/to opsional (to exclusive).
+func (r *payeeRepository) FindTransactions(ctx context.Context, payeeID, userID uuid.UUID, from, to *time.Time) ([]models.Transaction, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	// Sama kayak walletRepository.FindAccessibleByUserID
+	memberGroups := make(map[uuid.UUID]bool)
+	for _, m := range r.s.groupMembers.where(func(m *models.GroupMember) bool { return m.UserID == userID }) {
+		memberGroups[m.GroupID] = true
+	}
+	accessibleWallets := make(map[uuid.UUID]bool)
+	for _, w := range r.s.wallets.where(func(w *models.Wallet) bool {
+		return isPersonalWallet(w, userID) || (w.GroupID != nil && memberGroups[*w.GroupID])
+	}) {
+		accessibleWallets[w.ID] = true
+	}
+
+	transactions := values(r.s.transactions.where(func(t *models.Transaction) bool {
+		return t.PayeeID != nil && *t.PayeeID == payeeID && accessibleWallets[t.WalletID] &&
+			(from == nil || !t.Date.Before(*from)) &&
+			(to == nil || t.Date.Before(*to))
+	}))
+	for i := range transactions {
+		transactions[i].Category = r.s.category(transactions[i].CategoryID)
+		transactions[i].Lines = r.s.linesOf(transactions[i].ID)
+		for j := range transactions[i].Lines {
+			transactions[i].Lines[j].Category = r.s.category(transactions[i].Lines[j].CategoryID)
+		}
+	}
+	sort.SliceStable(transactions, func(i, j int) bool { return transactions[i].Date.After(transactions[j].Date) })
+	return transactions, nil
+}
+
+// inScope = PayeeScope.apply: GroupID diisi = payee milik group itu, kosong = payee pribadi UserID.
+func inScope(p *models.Payee, scope repository.PayeeScope) bool {
+	if scope.GroupID != nil {
+		return sameID(p.GroupID, scope.GroupID)
+	}
+	return p.UserID == scope.UserID && p.GroupID == nil
+}
+
+func (s *Store) aliases(payeeID uuid.UUID) []models.PayeeAlias {
+	aliases := values(s.payeeAliases.where(func(a *models.PayeeAlias) bool { return a.PayeeID == payeeID }))
+	if len(aliases) == 0 {
+		return nil
+	}
+	return aliases
+}
+
+func (s *Store) hasAlias(payeeID uuid.UUID, match func(normalizedAlias string) bool) bool {
+	_, ok := s.payeeAliases.first(func(a *models.PayeeAlias) bool { return a.PayeeID == payeeID && match(a.NormalizedAlias) })
+	return ok
+}
+
+func (s *Store) insertAliases(payeeID uuid.UUID, aliases []models.PayeeAlias, now time.Time) {
+	for i := range aliases {
+		aliases[i].PayeeID = payeeID
+		stamp(&aliases[i].Base, now)
+		s.payeeAliases.insert(aliases[i].ID, aliases[i])
+	}
+}
+
+func (s *Store) deleteAliases(payeeID uuid.UUID) {
+	for _, a := range s.payeeAliases.whereAll(func(a *models.PayeeAlias) bool { return a.PayeeID == payeeID }) {
+		s.payeeAliases.hardDelete(a.ID)
+	}
+}
+
+func (s *Store) defaultCategory(id *uuid.UUID) *models.Category {
+	if id == nil {
+		return nil
+	}
+	if category, ok := s.categories.get(*id); ok {
+		return ptr(*category)
+	}
+	return nil
+}
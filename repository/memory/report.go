@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+type reportRepository struct {
+	s *Store
+}
+
+func NewReportRepository(s *Store) repository.ReportRepository {
+	return &reportRepository{s: s}
+}
+
+func (r *reportRepository) CategoryTotals(ctx context.Context, filter repository.ReportFilter) ([]repository.CategoryTotal, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	transactions := r.s.transactions.where(func(t *models.Transaction) bool {
+		if t.Date.Before(filter.From) || !t.Date.Before(filter.To) {
+			return false
+		}
+		if filter.WalletID != nil {
+			return t.WalletID == *filter.WalletID
+		}
+		return t.UserID == filter.UserID
+	})
+
+	totals := make(map[uuid.UUID]*repository.CategoryTotal)
+	counted := make(map[uuid.UUID]map[uuid.UUID]bool) // kategori -> transaksi (COUNT DISTINCT)
+	add := func(transactionID, categoryID uuid.UUID, amount float64) {
+		// JOIN categories: kategori yang udah di-soft delete tetap ikut dihitung
+		category, ok := r.s.categories.getUnscoped(categoryID)
+		if !ok {
+			return
+		}
+		total, ok := totals[categoryID]
+		if !ok {
+			total = &repository.CategoryTotal{CategoryID: categoryID, Name: category.Name, Type: category.Type}
+			totals[categoryID] = total
+			counted[categoryID] = make(map[uuid.UUID]bool)
+		}
+		total.Total += amount
+		if !counted[categoryID][transactionID] {
+			counted[categoryID][transactionID] = true
+			total.TransactionCount++
+		}
+	}
+
+	// Satu entri per line (kalau split) atau per transaksi (kalau gak)
+	for _, t := range transactions {
+		lines := r.s.linesOf(t.ID)
+		if len(lines) == 0 {
+			add(t.ID, t.CategoryID, t.Amount)
+			continue
+		}
+		for _, line := range lines {
+			add(t.ID, line.CategoryID, line.Amount)
+		}
+	}
+
+	result := make([]repository.CategoryTotal, 0, len(totals))
+	for _, total := range totals {
+		total.Total = roundMoney(total.Total)
+		result = append(result, *total)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Type != result[j].Type {
+			return result[i].Type < result[j].Type
+		}
+		return result[i].Total < result[j].Total
+	})
+	return result, nil
+}
@@ -0,0 +1,201 @@
+package memory
+
+import (
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type statementImportRepository struct {
+	s *Store
+}
+
+func NewStatementImportRepository(s *Store) repository.StatementImportRepository {
+	return &statementImportRepository{s: s}
+}
+
+// Create ikut nyimpen Rows (GORM juga otomatis insert asosiasi has-many).
+func (r *statementImportRepository) Create(ctx context.Context, statementImport *models.StatementImport) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, row := range statementImport.Rows {
+		if row.CategoryID != nil && !r.s.categories.exists(*row.CategoryID) {
+			return errInvalidReference
+		}
+	}
+
+	now := time.Now()
+	stamp(&statementImport.Base, now)
+	if statementImport.Status == "" {
+		statementImport.Status = models.StatementImportPending // default kolom status
+	}
+	stored := *statementImport
+	stored.Rows = nil
+	r.s.statementImports.insert(stored.ID, stored)
+
+	for i := range statementImport.Rows {
+		row := &statementImport.Rows[i]
+		row.ImportID = statementImport.ID
+		stamp(&row.Base, now)
+		storedRow := *row
+		storedRow.Category = nil
+		r.s.statementImportRows.insert(storedRow.ID, storedRow)
+	}
+	return nil
+}
+
+func (r *statementImportRepository) FindByIDAndUserID(ctx context.Context, importID, userID uuid.UUID) (*models.StatementImport, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	stored, ok := r.s.statementImports.get(importID)
+	if !ok || stored.UserID != userID {
+		return &models.StatementImport{}, errNotFound
+	}
+	statementImport := *stored
+	statementImport.Rows = r.s.importRows(importID)
+	sort.SliceStable(statementImport.Rows, func(i, j int) bool {
+		a, b := statementImport.Rows[i], statementImport.Rows[j]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	for i := range statementImport.Rows {
+		statementImport.Rows[i].Category = r.s.defaultCategory(statementImport.Rows[i].CategoryID)
+	}
+	return &statementImport, nil
+}
+
+func (r *statementImportRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.StatementImport, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	imports := values(r.s.statementImports.where(func(i *models.StatementImport) bool { return i.UserID == userID }))
+	for i := range imports {
+		imports[i].Rows = r.s.importRows(imports[i].ID)
+	}
+	sort.SliceStable(imports, func(i, j int) bool { return imports[i].CreatedAt.After(imports[j].CreatedAt) })
+	return imports, nil
+}
+
+func (r *statementImportRepository) UpdateRows(ctx context.Context, rows []models.StatementImportRow) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, row := range rows {
+		if row.CategoryID != nil && !r.s.categories.exists(*row.CategoryID) {
+			return errInvalidReference
+		}
+	}
+	now := time.Now()
+	for _, row := range rows {
+		if stored, ok := r.s.statementImportRows.get(row.ID); ok {
+			stored.CategoryID = row.CategoryID
+			stored.Skip = row.Skip
+			stored.TransactionID = row.TransactionID
+			stored.UpdatedAt = now
+		}
+	}
+	return nil
+}
+
+// Commit: status, cek duplikat, insert transaksi & saldo semuanya di bawah satu lock (= satu DB transaction).
+func (r *statementImportRepository) Commit(ctx context.Context, statementImport *models.StatementImport, walletID uuid.UUID, items []repository.ImportCommitItem) ([]models.Transaction, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.statementImports.get(statementImport.ID)
+	if !ok || stored.Status != models.StatementImportPending {
+		return nil, repository.ErrImportNotPending
+	}
+
+	wanted := make(map[string]bool, len(items))
+	for _, item := range items {
+		wanted[item.Transaction.Fingerprint] = true
+	}
+	existing := make(map[string]bool)
+	for _, t := range r.s.transactions.where(func(t *models.Transaction) bool { return t.WalletID == walletID && wanted[t.Fingerprint] }) {
+		existing[t.Fingerprint] = true
+	}
+
+	var (
+		rows       []*models.StatementImportRow
+		created    []models.Transaction
+		duplicates []*models.StatementImportRow
+	)
+	for _, item := range items {
+		// Row.Duplicate = user udah tau duplikat tapi sengaja gak di-skip, tetap dibikin
+		if existing[item.Transaction.Fingerprint] && !item.Row.Duplicate {
+			duplicates = append(duplicates, item.Row)
+			continue
+		}
+		rows = append(rows, item.Row)
+		created = append(created, item.Transaction)
+	}
+	for i := range created {
+		if err := r.s.checkTransactionRefs(&created[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	stored.Status = models.StatementImportCommitted
+	stored.UpdatedAt = now
+	for _, row := range duplicates {
+		row.Duplicate, row.Skip = true, true
+		if storedRow, ok := r.s.statementImportRows.get(row.ID); ok {
+			storedRow.Duplicate, storedRow.Skip = true, true
+			storedRow.UpdatedAt = now
+		}
+	}
+	deltas := make(map[uuid.UUID]float64)
+	for i := range created {
+		r.s.insertTransaction(&created[i], now)
+		deltas[created[i].WalletID] += created[i].Amount
+
+		id := created[i].ID
+		rows[i].TransactionID = &id
+		if storedRow, ok := r.s.statementImportRows.get(rows[i].ID); ok {
+			storedRow.TransactionID = &id
+			storedRow.UpdatedAt = now
+		}
+	}
+	r.s.applyWalletDeltas(deltas, now)
+
+	statementImport.Status = models.StatementImportCommitted
+	return created, nil
+}
+
+func (r *statementImportRepository) ExistingFingerprints(ctx context.Context, walletID uuid.UUID, fingerprints []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(fingerprints) == 0 {
+		return existing, nil
+	}
+
+	wanted := make(map[string]bool, len(fingerprints))
+	for _, fp := range fingerprints {
+		wanted[fp] = true
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, t := range r.s.transactions.where(func(t *models.Transaction) bool { return t.WalletID == walletID && wanted[t.Fingerprint] }) {
+		existing[t.Fingerprint] = true
+	}
+	return existing, nil
+}
+
+func (s *Store) importRows(importID uuid.UUID) []models.StatementImportRow {
+	rows := values(s.statementImportRows.where(func(r *models.StatementImportRow) bool { return r.ImportID == importID }))
+	if len(rows) == 0 {
+		return nil
+	}
+	return rows
+}
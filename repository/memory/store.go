@@ -0,0 +1,245 @@
+// Package memory = implementasi in-memory semua interface di package repository, tanpa database.
+// Dipakai buat mode demo (DB_DRIVER=memory) dan buat jalanin seluruh HTTP API di dalam proses
+// (httptest) tanpa postgres.
+//
+// Semua tabel ada di satu Store yang dijaga satu RWMutex. Method yang nulis pegang lock sampai
+// selesai, jadi tiap method = satu "DB transaction": semua dicek dulu, baru diubah, dan gak ada
+// request lain yang bisa nyelip di tengah (pengganti SELECT ... FOR UPDATE di versi GORM).
+// Aturan yang di DB dijaga constraint (unique, foreign key, version) ikut dicek di sini dan
+// error-nya sama persis dengan versi GORM (lewat repository.TranslateError).
+package memory
+
+import (
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Error yang di versi GORM datang dari database, diterjemahin dengan cara yang sama.
+var (
+	errNotFound         = repository.TranslateError(gorm.ErrRecordNotFound)
+	errDuplicate        = repository.TranslateError(gorm.ErrDuplicatedKey)
+	errInvalidReference = repository.TranslateError(gorm.ErrForeignKeyViolated)
+)
+
+// Store = "database"-nya. Satu Store dipakai bareng semua repository biar relasi antar tabel
+// (wallet <-> transaksi, group <-> member, dst) konsisten.
+type Store struct {
+	mu sync.RWMutex
+
+	users               *table[models.User]
+	recoveryCodes       *table[models.RecoveryCode]
+	groups              *table[models.Group]
+	groupMembers        *table[models.GroupMember]
+	wallets             *table[models.Wallet]
+	categories          *table[models.Category]
+	categoryRules       *table[models.CategoryRule]
+	transactions        *table[models.Transaction]
+	transactionLines    *table[models.TransactionLine]
+	payees              *table[models.Payee]
+	payeeAliases        *table[models.PayeeAlias]
+	apiKeys             *table[models.APIKey]
+	webhooks            *table[models.WebhookSubscription]
+	webhookDeliveries   *table[models.WebhookDelivery]
+	notifications       *table[models.Notification]
+	notificationPrefs   *table[models.NotificationPreference]
+	idempotencyKeys     *table[models.IdempotencyKey]
+	statementImports    *table[models.StatementImport]
+	statementImportRows *table[models.StatementImportRow]
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:               newTable[models.User](),
+		recoveryCodes:       newTable[models.RecoveryCode](),
+		groups:              newTable[models.Group](),
+		groupMembers:        newTable[models.GroupMember](),
+		wallets:             newTable[models.Wallet](),
+		categories:          newTable[models.Category](),
+		categoryRules:       newTable[models.CategoryRule](),
+		transactions:        newTable[models.Transaction](),
+		transactionLines:    newTable[models.TransactionLine](),
+		payees:              newTable[models.Payee](),
+		payeeAliases:        newTable[models.PayeeAlias](),
+		apiKeys:             newTable[models.APIKey](),
+		webhooks:            newTable[models.WebhookSubscription](),
+		webhookDeliveries:   newTable[models.WebhookDelivery](),
+		notifications:       newTable[models.Notification](),
+		notificationPrefs:   newTable[models.NotificationPreference](),
+		idempotencyKeys:     newTable[models.IdempotencyKey](),
+		statementImports:    newTable[models.StatementImport](),
+		statementImportRows: newTable[models.StatementImportRow](),
+	}
+}
+
+// NewRepositories = semua repository di atas satu Store baru yang masih kosong.
+func NewRepositories() repository.Repositories {
+	return NewStore().Repositories()
+}
+
+func (s *Store) Repositories() repository.Repositories {
+	return repository.Repositories{
+		User:            NewUserRepository(s),
+		Auth:            NewAuthRepository(s),
+		Transaction:     NewTransactionRepository(s),
+		Category:        NewCategoryRepository(s),
+		Group:           NewGroupRepository(s),
+		Wallet:          NewWalletRepository(s),
+		APIKey:          NewAPIKeyRepository(s),
+		Webhook:         NewWebhookRepository(s),
+		Notification:    NewNotificationRepository(s),
+		StatementImport: NewStatementImportRepository(s),
+		CategoryRule:    NewCategoryRuleRepository(s),
+		Idempotency:     NewIdempotencyRepository(s),
+		Report:          NewReportRepository(s),
+		Payee:           NewPayeeRepository(s),
+	}
+}
+
+// table = satu tabel. Baris yang di-soft delete dipindah ke deleted: gak kelihatan lagi di query
+// biasa, tapi masih dihitung buat unique & foreign key (sama kayak baris deleted_at di DB).
+type table[T any] struct {
+	rows    map[uuid.UUID]*T
+	deleted map[uuid.UUID]*T
+	order   []uuid.UUID // urutan insert, biar query tanpa ORDER BY hasilnya stabil
+}
+
+func newTable[T any]() *table[T] {
+	return &table[T]{rows: make(map[uuid.UUID]*T), deleted: make(map[uuid.UUID]*T)}
+}
+
+// get balikin pointer ke baris yang tersimpan, cuma boleh diubah selama Store masih di-lock.
+func (t *table[T]) get(id uuid.UUID) (*T, bool) {
+	row, ok := t.rows[id]
+	return row, ok
+}
+
+// getUnscoped = get termasuk baris yang udah di-soft delete (JOIN biasa di DB juga gak nyaring deleted_at).
+func (t *table[T]) getUnscoped(id uuid.UUID) (*T, bool) {
+	if row, ok := t.rows[id]; ok {
+		return row, true
+	}
+	row, ok := t.deleted[id]
+	return row, ok
+}
+
+// exists termasuk baris yang udah di-soft delete (foreign key di DB juga gak peduli deleted_at).
+func (t *table[T]) exists(id uuid.UUID) bool {
+	_, ok := t.getUnscoped(id)
+	return ok
+}
+
+func (t *table[T]) insert(id uuid.UUID, row T) *T {
+	t.rows[id] = &row
+	t.order = append(t.order, id)
+	return &row
+}
+
+func (t *table[T]) softDelete(id uuid.UUID) bool {
+	row, ok := t.rows[id]
+	if !ok {
+		return false
+	}
+	delete(t.rows, id)
+	t.deleted[id] = row
+	t.removeOrder(id)
+	return true
+}
+
+func (t *table[T]) hardDelete(id uuid.UUID) bool {
+	if _, ok := t.deleted[id]; ok {
+		delete(t.deleted, id)
+		return true
+	}
+	if _, ok := t.rows[id]; !ok {
+		return false
+	}
+	delete(t.rows, id)
+	t.removeOrder(id)
+	return true
+}
+
+func (t *table[T]) removeOrder(id uuid.UUID) {
+	for i, v := range t.order {
+		if v == id {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			return
+		}
+	}
+}
+
+// where = baris yang belum dihapus & lolos filter, urut sesuai insert. match nil = semua.
+func (t *table[T]) where(match func(*T) bool) []*T {
+	var rows []*T
+	for _, id := range t.order {
+		if row := t.rows[id]; match == nil || match(row) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// whereAll = where tapi ikut baris yang udah di-soft delete (buat cek unique).
+func (t *table[T]) whereAll(match func(*T) bool) []*T {
+	rows := t.where(match)
+	for _, row := range t.deleted {
+		if match(row) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func (t *table[T]) first(match func(*T) bool) (*T, bool) {
+	for _, id := range t.order {
+		if row := t.rows[id]; match(row) {
+			return row, true
+		}
+	}
+	return nil, false
+}
+
+// values = salinan baris, yang dibalikin ke service (biar gak bisa ngubah Store tanpa lock).
+func values[T any](rows []*T) []T {
+	list := make([]T, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, *row)
+	}
+	return list
+}
+
+// stamp = yang di versi GORM diisi otomatis pas Create: ID (BeforeCreate) & created_at/updated_at.
+func stamp(base *models.Base, now time.Time) {
+	if base.ID == uuid.Nil {
+		base.ID = uuid.New()
+	}
+	if base.CreatedAt.IsZero() {
+		base.CreatedAt = now
+	}
+	if base.UpdatedAt.IsZero() {
+		base.UpdatedAt = now
+	}
+}
+
+func sameID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// take = LIMIT n. n < 0 = tanpa limit (sama kayak GORM).
+func take[T any](rows []T, n int) []T {
+	if n >= 0 && len(rows) > n {
+		return rows[:n]
+	}
+	return rows
+}
@@ -0,0 +1,317 @@
+package memory
+
+import (
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type transactionRepository struct {
+	s *Store
+}
+
+func NewTransactionRepository(s *Store) repository.TransactionRepository {
+	return &transactionRepository{s: s}
+}
+
+// CreateWithWalletUpdate: insert transaksi (+ lines) dan geser saldo wallet di bawah lock yang sama,
+// jadi gak ada request lain yang bisa baca saldo di antara dua langkah itu.
+func (r *transactionRepository) CreateWithWalletUpdate(ctx context.Context, transaction *models.Transaction) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := r.s.checkTransactionRefs(transaction); err != nil {
+		return err // gak ada yang ditulis, sama kayak rollback
+	}
+	now := time.Now()
+	r.s.insertTransaction(transaction, now)
+	r.s.applyWalletDeltas(map[uuid.UUID]float64{transaction.WalletID: transaction.Amount}, now)
+	return nil
+}
+
+// CreateManyWithWalletUpdate = CreateWithWalletUpdate versi banyak, all or nothing.
+func (r *transactionRepository) CreateManyWithWalletUpdate(ctx context.Context, transactions []models.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for i := range transactions {
+		if err := r.s.checkTransactionRefs(&transactions[i]); err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	deltas := make(map[uuid.UUID]float64)
+	for i := range transactions {
+		r.s.insertTransaction(&transactions[i], now)
+		deltas[transactions[i].WalletID] += transactions[i].Amount
+	}
+	r.s.applyWalletDeltas(deltas, now)
+	return nil
+}
+
+// UpdateManyWithWalletUpdate: satu aja yang versinya basi -> semuanya batal (ErrStaleVersion).
+func (r *transactionRepository) UpdateManyWithWalletUpdate(ctx context.Context, transactions []models.Transaction, walletDeltas map[uuid.UUID]float64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for i := range transactions {
+		if err := r.s.checkTransactionUpdate(&transactions[i]); err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	for i := range transactions {
+		r.s.updateTransaction(&transactions[i], now)
+	}
+	r.s.applyWalletDeltas(walletDeltas, now)
+	return nil
+}
+
+// SoftDeleteManyWithWalletUpdate: ada yang udah kehapus / diubah duluan -> delta saldo-nya basi, batalin.
+func (r *transactionRepository) SoftDeleteManyWithWalletUpdate(ctx context.Context, transactions []models.Transaction, walletDeltas map[uuid.UUID]float64) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, t := range transactions {
+		current, ok := r.s.transactions.get(t.ID)
+		if !ok || current.Version != t.Version {
+			return repository.ErrStaleVersion
+		}
+	}
+	for _, t := range transactions {
+		r.s.transactions.softDelete(t.ID)
+	}
+	r.s.applyWalletDeltas(walletDeltas, time.Now())
+	return nil
+}
+
+func (r *transactionRepository) FindByIDs(ctx context.Context, transactionIDs []uuid.UUID) ([]models.Transaction, error) {
+	ids := idSet(transactionIDs)
+	return r.find(func(t *models.Transaction) bool { return ids[t.ID] }), nil
+}
+
+func (r *transactionRepository) FindByIDsAndUserID(ctx context.Context, transactionIDs []uuid.UUID, userID uuid.UUID) ([]models.Transaction, error) {
+	ids := idSet(transactionIDs)
+	return r.find(func(t *models.Transaction) bool { return ids[t.ID] && t.UserID == userID }), nil
+}
+
+func (r *transactionRepository) FindByUserIDAndCategoryID(ctx context.Context, userID, categoryID uuid.UUID) ([]models.Transaction, error) {
+	return r.find(func(t *models.Transaction) bool { return t.UserID == userID && t.CategoryID == categoryID }), nil
+}
+
+func (r *transactionRepository) FindAll(ctx context.Context) ([]models.Transaction, error) {
+	return r.find(nil), nil
+}
+
+func (r *transactionRepository) IsOwner(ctx context.Context, userID uuid.UUID, walletID string) bool {
+	id, err := uuid.Parse(walletID)
+	if err != nil {
+		return false
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	wallet, ok := r.s.wallets.get(id)
+	return ok && wallet.UserID != nil && *wallet.UserID == userID
+}
+
+func (r *transactionRepository) FindByID(ctx context.Context, transactionID uuid.UUID) (*models.Transaction, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	transaction, ok := r.s.transactions.get(transactionID)
+	if !ok {
+		return &models.Transaction{}, errNotFound
+	}
+	return ptr(r.s.loadTransaction(*transaction)), nil
+}
+
+// UpdateTransaction cuma nulis kalau version masih sama dengan yang dibaca service.
+func (r *transactionRepository) UpdateTransaction(ctx context.Context, transaction *models.Transaction) error {
+	return r.update(transaction, nil)
+}
+
+func (r *transactionRepository) UpdateTransactionWithWalletBallance(ctx context.Context, transaction *models.Transaction, delta float64) error {
+	return r.update(transaction, map[uuid.UUID]float64{transaction.WalletID: delta})
+}
+
+// MoveTransactionWithWalletUpdate: nominal lama keluar dari wallet asal, nominal baru masuk ke wallet tujuan.
+func (r *transactionRepository) MoveTransactionWithWalletUpdate(ctx context.Context, transaction *models.Transaction, fromWalletID uuid.UUID, oldAmount float64) error {
+	return r.update(transaction, map[uuid.UUID]float64{
+		fromWalletID:         -oldAmount,
+		transaction.WalletID: transaction.Amount,
+	})
+}
+
+// SoftDeleteTransaction cuma jalan kalau version masih sama dengan yang dibaca service.
+func (r *transactionRepository) SoftDeleteTransaction(ctx context.Context, transactionID uuid.UUID, version int, delta float64, walletID uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	transaction, ok := r.s.transactions.get(transactionID)
+	if !ok || transaction.Version != version {
+		return repository.ErrStaleVersion
+	}
+	r.s.transactions.softDelete(transactionID)
+	r.s.applyWalletDeltas(map[uuid.UUID]float64{walletID: delta}, time.Now())
+	return nil
+}
+
+func (r *transactionRepository) find(match func(t *models.Transaction) bool) []models.Transaction {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	transactions := values(r.s.transactions.where(match))
+	for i := range transactions {
+		transactions[i] = r.s.loadTransaction(transactions[i])
+	}
+	return transactions
+}
+
+func (r *transactionRepository) update(transaction *models.Transaction, walletDeltas map[uuid.UUID]float64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := r.s.checkTransactionUpdate(transaction); err != nil {
+		return err
+	}
+	now := time.Now()
+	r.s.updateTransaction(transaction, now)
+	r.s.applyWalletDeltas(walletDeltas, now)
+	return nil
+}
+
+// loadTransaction = preload Category, Wallet, Lines (+ Category, urut position) & Payee.
+func (s *Store) loadTransaction(t models.Transaction) models.Transaction {
+	t.Category = s.category(t.CategoryID)
+	if wallet, ok := s.wallets.get(t.WalletID); ok {
+		t.Wallet = *wallet
+	}
+	t.Lines = s.linesOf(t.ID)
+	for i := range t.Lines {
+		t.Lines[i].Category = s.category(t.Lines[i].CategoryID)
+	}
+	t.Payee = nil
+	if t.PayeeID != nil {
+		if payee, ok := s.payees.get(*t.PayeeID); ok {
+			t.Payee = ptr(*payee)
+		}
+	}
+	return t
+}
+
+func (s *Store) linesOf(transactionID uuid.UUID) []models.TransactionLine {
+	lines := values(s.transactionLines.where(func(l *models.TransactionLine) bool { return l.TransactionID == transactionID }))
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Position < lines[j].Position })
+	if len(lines) == 0 {
+		return nil
+	}
+	return lines
+}
+
+// checkTransactionRefs = foreign key transaksi & lines-nya.
+func (s *Store) checkTransactionRefs(t *models.Transaction) error {
+	if !s.users.exists(t.UserID) || !s.wallets.exists(t.WalletID) || !s.categories.exists(t.CategoryID) {
+		return errInvalidReference
+	}
+	if t.PayeeID != nil && !s.payees.exists(*t.PayeeID) {
+		return errInvalidReference
+	}
+	for _, line := range t.Lines {
+		if !s.categories.exists(line.CategoryID) {
+			return errInvalidReference
+		}
+	}
+	return nil
+}
+
+// checkTransactionUpdate = WHERE id = ? AND version = ? harus kena, plus foreign key nilai barunya.
+func (s *Store) checkTransactionUpdate(t *models.Transaction) error {
+	stored, ok := s.transactions.get(t.ID)
+	if !ok || stored.Version != t.Version {
+		return repository.ErrStaleVersion
+	}
+	if !s.wallets.exists(t.WalletID) || !s.categories.exists(t.CategoryID) {
+		return errInvalidReference
+	}
+	if t.PayeeID != nil && !s.payees.exists(*t.PayeeID) {
+		return errInvalidReference
+	}
+	if t.Lines != nil {
+		for _, line := range t.Lines {
+			if !s.categories.exists(line.CategoryID) {
+				return errInvalidReference
+			}
+		}
+	}
+	return nil
+}
+
+// insertTransaction: referensi udah dicek checkTransactionRefs.
+func (s *Store) insertTransaction(t *models.Transaction, now time.Time) {
+	stamp(&t.Base, now)
+	if t.Version == 0 {
+		t.Version = 1 // default kolom version
+	}
+	t.Amount = roundMoney(t.Amount)
+
+	row := *t
+	row.User = models.User{}
+	row.Wallet = models.Wallet{}
+	row.Category = models.Category{}
+	row.Payee = nil
+	row.Lines = nil
+	row.TransactionCount = 0
+	s.transactions.insert(row.ID, row)
+	s.insertTransactionLines(t, now)
+}
+
+// updateTransaction = updateTransactionVersioned di repository. Lines != nil = lines ikut diganti.
+func (s *Store) updateTransaction(t *models.Transaction, now time.Time) {
+	stored, _ := s.transactions.get(t.ID)
+	stored.Title = t.Title
+	stored.Amount = roundMoney(t.Amount)
+	stored.Description = t.Description
+	stored.Date = t.Date
+	stored.CategoryID = t.CategoryID
+	stored.PayeeID = t.PayeeID
+	stored.WalletID = t.WalletID
+	stored.Tags = t.Tags
+	stored.Version++
+	stored.UpdatedAt = now
+	t.Version++
+
+	if t.Lines != nil {
+		for _, line := range s.transactionLines.whereAll(func(l *models.TransactionLine) bool { return l.TransactionID == t.ID }) {
+			s.transactionLines.hardDelete(line.ID)
+		}
+		s.insertTransactionLines(t, now)
+	}
+}
+
+func (s *Store) insertTransactionLines(t *models.Transaction, now time.Time) {
+	for i := range t.Lines {
+		line := &t.Lines[i]
+		line.TransactionID = t.ID
+		line.Position = i
+		line.Amount = roundMoney(line.Amount)
+		stamp(&line.Base, now)
+
+		row := *line
+		row.Category = models.Category{}
+		s.transactionLines.insert(row.ID, row)
+	}
+}
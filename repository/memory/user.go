@@ -0,0 +1,250 @@
+package memory
+
+import (
+	"cashflow_gin/dto/request"
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type userRepository struct {
+	s *Store
+}
+
+func NewUserRepository(s *Store) repository.UserRepository {
+	return &userRepository{s: s}
+}
+
+func (r *userRepository) FindByEmailOrUsername(ctx context.Context, email, username string) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	user, ok := r.s.users.first(func(u *models.User) bool { return u.Email == email || u.Username == username })
+	if !ok {
+		return &models.User{}, errNotFound
+	}
+	return ptr(*user), nil
+}
+
+func (r *userRepository) IsEmailTaken(ctx context.Context, email string, exceptID uuid.UUID) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return len(r.s.users.whereAll(func(u *models.User) bool { return u.ID != exceptID && u.Email == email })) > 0, nil
+}
+
+func (r *userRepository) IsUsernameTaken(ctx context.Context, username string, exceptID uuid.UUID) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return len(r.s.users.whereAll(func(u *models.User) bool { return u.ID != exceptID && u.Username == username })) > 0, nil
+}
+
+func (r *userRepository) FindAllUser(ctx context.Context) ([]models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	users := values(r.s.users.where(nil))
+	for i := range users {
+		users[i].Wallets = r.s.userWallets(users[i].ID, false)
+	}
+	return users, nil
+}
+
+func (r *userRepository) FindMyProfile(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	user, ok := r.s.users.get(id)
+	if !ok {
+		return nil, errNotFound
+	}
+	profile := *user
+	profile.Wallets = r.s.userWallets(id, true)
+	return &profile, nil
+}
+
+func (r *userRepository) Login(ctx context.Context, input *request.LoginRequest) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.findUserByEmail(input.Email)
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.findUser(id)
+}
+
+func (r *userRepository) UpdateProfile(ctx context.Context, user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.users.get(user.ID)
+	if !ok {
+		return nil
+	}
+	if r.s.userTaken(user.ID, user.Username, user.Email) {
+		return errDuplicate
+	}
+	stored.Username = user.Username
+	stored.Email = user.Email
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, hashedPassword string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if user, ok := r.s.users.get(userID); ok {
+		user.Password = hashedPassword
+		user.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (r *userRepository) FindOwnedGroups(ctx context.Context, userID uuid.UUID) ([]models.Group, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	groups := values(r.s.groups.where(func(g *models.Group) bool { return g.OwnerID == userID }))
+	for i := range groups {
+		members := values(r.s.groupMembers.where(func(m *models.GroupMember) bool { return m.GroupID == groups[i].ID }))
+		sort.SliceStable(members, func(a, b int) bool { return members[a].CreatedAt.Before(members[b].CreatedAt) })
+		groups[i].Members = members
+	}
+	return groups, nil
+}
+
+func (r *userRepository) FindPersonalWallets(ctx context.Context, userID uuid.UUID) ([]models.Wallet, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return values(r.s.wallets.where(func(w *models.Wallet) bool { return isPersonalWallet(w, userID) })), nil
+}
+
+func (r *userRepository) FindTransactionsByUserID(ctx context.Context, userID uuid.UUID) ([]models.Transaction, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	transactions := values(r.s.transactions.where(func(t *models.Transaction) bool { return t.UserID == userID }))
+	for i := range transactions {
+		transactions[i].Category = r.s.category(transactions[i].CategoryID)
+	}
+	sort.SliceStable(transactions, func(a, b int) bool { return transactions[a].Date.Before(transactions[b].Date) })
+	return transactions, nil
+}
+
+func (r *userRepository) FindMemberships(ctx context.Context, userID uuid.UUID) ([]models.GroupMember, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	memberships := values(r.s.groupMembers.where(func(m *models.GroupMember) bool { return m.UserID == userID }))
+	for i := range memberships {
+		if group, ok := r.s.groups.get(memberships[i].GroupID); ok {
+			memberships[i].Group = *group
+		}
+	}
+	return memberships, nil
+}
+
+// DeleteAccount: urutannya sama dengan versi GORM, semua di bawah satu lock.
+func (r *userRepository) DeleteAccount(ctx context.Context, userID uuid.UUID, ownerTransfers map[uuid.UUID]uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+
+	// 1. Pindahin kepemilikan group + jadiin owner baru ADMIN
+	for groupID, newOwnerID := range ownerTransfers {
+		if group, ok := r.s.groups.get(groupID); ok && group.OwnerID == userID {
+			group.OwnerID = newOwnerID
+			group.UpdatedAt = now
+		}
+		for _, m := range r.s.groupMembers.where(func(m *models.GroupMember) bool { return m.GroupID == groupID && m.UserID == newOwnerID }) {
+			m.MembersRole = models.GroupAdmin
+			m.UpdatedAt = now
+		}
+	}
+
+	// 2. Keluarin user dari semua group
+	for _, m := range r.s.groupMembers.where(func(m *models.GroupMember) bool { return m.UserID == userID }) {
+		r.s.groupMembers.softDelete(m.ID)
+	}
+
+	// 3. Soft delete transaksi di wallet pribadi, lalu wallet-nya
+	for _, w := range r.s.wallets.where(func(w *models.Wallet) bool { return isPersonalWallet(w, userID) }) {
+		for _, t := range r.s.transactions.where(func(t *models.Transaction) bool { return t.WalletID == w.ID }) {
+			r.s.transactions.softDelete(t.ID)
+		}
+		r.s.wallets.softDelete(w.ID)
+	}
+
+	// 4. Cabut API key & webhook (termasuk webhook group yang dia bikin), buang recovery code 2FA & preference
+	for _, k := range r.s.apiKeys.where(func(k *models.APIKey) bool { return k.UserID == userID }) {
+		r.s.apiKeys.softDelete(k.ID)
+	}
+	for _, w := range r.s.webhooks.where(func(w *models.WebhookSubscription) bool { return w.UserID == userID }) {
+		r.s.webhooks.softDelete(w.ID)
+	}
+	for _, c := range r.s.recoveryCodes.whereAll(func(c *models.RecoveryCode) bool { return c.UserID == userID }) {
+		r.s.recoveryCodes.hardDelete(c.ID)
+	}
+	for _, p := range r.s.notificationPrefs.whereAll(func(p *models.NotificationPreference) bool { return p.UserID == userID }) {
+		r.s.notificationPrefs.hardDelete(p.ID)
+	}
+
+	// 5. Anonymize data pribadi biar email/username bisa dipake lagi, baru soft delete user
+	if user, ok := r.s.users.get(userID); ok {
+		anonymous := "deleted_" + userID.String()
+		user.Username = anonymous
+		user.Email = anonymous + "@deleted.local"
+		user.Password = ""
+		user.TwoFactorEnabled = false
+		user.TwoFactorSecret = ""
+		user.UpdatedAt = now
+		r.s.users.softDelete(userID)
+	}
+	return nil
+}
+
+func (s *Store) findUser(id uuid.UUID) (*models.User, error) {
+	user, ok := s.users.get(id)
+	if !ok {
+		return &models.User{}, errNotFound
+	}
+	return ptr(*user), nil
+}
+
+func (s *Store) findUserByEmail(email string) (*models.User, error) {
+	user, ok := s.users.first(func(u *models.User) bool { return u.Email == email })
+	if !ok {
+		return &models.User{}, errNotFound
+	}
+	return ptr(*user), nil
+}
+
+// userTaken = unique username / email, termasuk user yang udah dihapus (di DB constraint-nya juga gitu).
+func (s *Store) userTaken(id uuid.UUID, username, email string) bool {
+	return len(s.users.whereAll(func(u *models.User) bool {
+		return u.ID != id && (u.Username == username || u.Email == email)
+	})) > 0
+}
+
+// userWallets = preload User.Wallets. withCount = ikut isi TransactionCount (FindMyProfile).
+func (s *Store) userWallets(userID uuid.UUID, withCount bool) []models.Wallet {
+	wallets := values(s.wallets.where(func(w *models.Wallet) bool { return w.UserID != nil && *w.UserID == userID }))
+	if withCount {
+		for i := range wallets {
+			wallets[i].TransactionCount = int64(len(s.transactions.where(func(t *models.Transaction) bool {
+				return t.WalletID == wallets[i].ID
+			})))
+		}
+	}
+	return wallets
+}
+
+func isPersonalWallet(w *models.Wallet, userID uuid.UUID) bool {
+	return w.UserID != nil && *w.UserID == userID && w.GroupID == nil
+}
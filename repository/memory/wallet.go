@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type walletRepository struct {
+	s *Store
+}
+
+func NewWalletRepository(s *Store) repository.WalletRepository {
+	return &walletRepository{s: s}
+}
+
+func (r *walletRepository) FindByID(ctx context.Context, walletID uuid.UUID) (models.Wallet, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	stored, ok := r.s.wallets.get(walletID)
+	if !ok {
+		return models.Wallet{}, errNotFound
+	}
+	wallet := *stored
+	wallet.Transactions = values(r.s.transactions.where(func(t *models.Transaction) bool { return t.WalletID == walletID }))
+	for i := range wallet.Transactions {
+		wallet.Transactions[i].Category = r.s.category(wallet.Transactions[i].CategoryID)
+		if user, ok := r.s.users.get(wallet.Transactions[i].UserID); ok {
+			wallet.Transactions[i].User = *user
+		}
+	}
+	return wallet, nil
+}
+
+func (r *walletRepository) FindAll(ctx context.Context) (*[]models.Wallet, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	wallets := values(r.s.wallets.where(nil))
+	if len(wallets) > 10 {
+		wallets = wallets[:10]
+	}
+	return &wallets, nil
+}
+
+func (r *walletRepository) FindBalance(ctx context.Context, walletID uuid.UUID) (float64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	wallet, ok := r.s.wallets.get(walletID)
+	if !ok {
+		return 0, errNotFound
+	}
+	return wallet.Balance, nil
+}
+
+// FindByIDs tanpa preload transaksi, cukup buat ngecek kepemilikan wallet.
+func (r *walletRepository) FindByIDs(ctx context.Context, walletIDs []uuid.UUID) ([]models.Wallet, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	ids := idSet(walletIDs)
+	return values(r.s.wallets.where(func(w *models.Wallet) bool { return ids[w.ID] })), nil
+}
+
+// FindAccessibleByUserID = wallet pribadi user + wallet semua group yang dia ikuti.
+func (r *walletRepository) FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]models.Wallet, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	memberGroups := make(map[uuid.UUID]bool)
+	for _, m := range r.s.groupMembers.where(func(m *models.GroupMember) bool { return m.UserID == userID }) {
+		memberGroups[m.GroupID] = true
+	}
+	return values(r.s.wallets.where(func(w *models.Wallet) bool {
+		return isPersonalWallet(w, userID) || (w.GroupID != nil && memberGroups[*w.GroupID])
+	})), nil
+}
+
+// FindBalanceMismatches bandingin saldo tiap wallet dengan total amount transaksi yang belum dihapus.
+func (r *walletRepository) FindBalanceMismatches(ctx context.Context) ([]repository.BalanceMismatch, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	totals := make(map[uuid.UUID]float64)
+	for _, t := range r.s.transactions.where(nil) {
+		totals[t.WalletID] += t.Amount
+	}
+
+	var mismatches []repository.BalanceMismatch
+	for _, w := range r.s.wallets.where(nil) {
+		if total := roundMoney(totals[w.ID]); total != w.Balance {
+			mismatches = append(mismatches, repository.BalanceMismatch{WalletID: w.ID, Balance: w.Balance, TransactionTotal: total})
+		}
+	}
+	return mismatches, nil
+}
+
+func (s *Store) insertWallet(wallet *models.Wallet, now time.Time) error {
+	if wallet.UserID != nil && !s.users.exists(*wallet.UserID) {
+		return errInvalidReference
+	}
+	if wallet.GroupID != nil && !s.groups.exists(*wallet.GroupID) {
+		return errInvalidReference
+	}
+	stamp(&wallet.Base, now)
+	if s.wallets.exists(wallet.ID) {
+		return errDuplicate
+	}
+	if wallet.Currency == "" {
+		wallet.Currency = "IDR" // default kolom currency
+	}
+	wallet.Balance = roundMoney(wallet.Balance)
+
+	row := *wallet
+	row.Transactions = nil
+	row.Groups = nil
+	s.wallets.insert(row.ID, row)
+	return nil
+}
+
+// applyWalletDeltas = versi in-memory applyWalletDeltas di repository: geser saldo tiap wallet.
+// Wallet yang gak ada dilewatin, sama kayak UPDATE ... WHERE id = ? yang gak kena baris apa pun.
+func (s *Store) applyWalletDeltas(walletDeltas map[uuid.UUID]float64, now time.Time) {
+	for walletID, delta := range walletDeltas {
+		if delta == 0 {
+			continue
+		}
+		if wallet, ok := s.wallets.get(walletID); ok {
+			wallet.Balance = roundMoney(wallet.Balance + delta)
+			wallet.UpdatedAt = now
+		}
+	}
+}
+
+// roundMoney = pembulatan kolom decimal(16,2), biar saldo gak kena selisih floating point.
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func idSet(ids []uuid.UUID) map[uuid.UUID]bool {
+	set := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type webhookRepository struct {
+	s *Store
+}
+
+func NewWebhookRepository(s *Store) repository.WebhookRepository {
+	return &webhookRepository{s: s}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, subscription *models.WebhookSubscription) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stamp(&subscription.Base, time.Now())
+	r.s.webhooks.insert(subscription.ID, *subscription)
+	return nil
+}
+
+func (r *webhookRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.WebhookSubscription, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	subscriptions := values(r.s.webhooks.where(func(w *models.WebhookSubscription) bool { return w.UserID == userID }))
+	sort.SliceStable(subscriptions, func(i, j int) bool { return subscriptions[i].CreatedAt.After(subscriptions[j].CreatedAt) })
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) FindByIDAndUserID(ctx context.Context, id, userID uuid.UUID) (*models.WebhookSubscription, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	subscription, ok := r.s.webhooks.get(id)
+	if !ok || subscription.UserID != userID {
+		return &models.WebhookSubscription{}, errNotFound
+	}
+	return ptr(*subscription), nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	subscription, ok := r.s.webhooks.get(id)
+	if !ok || subscription.UserID != userID {
+		return false, nil
+	}
+	return r.s.webhooks.softDelete(id), nil
+}
+
+// FindActiveByOwner: wallet group -> subscription milik group itu, wallet pribadi -> subscription user (tanpa group).
+func (r *webhookRepository) FindActiveByOwner(ctx context.Context, walletUserID, groupID *uuid.UUID) ([]models.WebhookSubscription, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var match func(w *models.WebhookSubscription) bool
+	switch {
+	case groupID != nil:
+		match = func(w *models.WebhookSubscription) bool { return w.Active && sameID(w.GroupID, groupID) }
+	case walletUserID != nil:
+		match = func(w *models.WebhookSubscription) bool {
+			return w.Active && w.UserID == *walletUserID && w.GroupID == nil
+		}
+	default:
+		return []models.WebhookSubscription{}, nil
+	}
+	return values(r.s.webhooks.where(match)), nil
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stamp(&delivery.Base, time.Now())
+	r.s.webhookDeliveries.insert(delivery.ID, *delivery)
+	return nil
+}
+
+func (r *webhookRepository) FindDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	deliveries := values(r.s.webhookDeliveries.where(func(d *models.WebhookDelivery) bool { return d.SubscriptionID == subscriptionID }))
+	sort.SliceStable(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt) })
+	return take(deliveries, limit), nil
+}
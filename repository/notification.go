@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"cashflow_gin/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *models.Notification) error
+	FindByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]models.Notification, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int64, error)
+	MarkRead(ctx context.Context, userID, notificationID uuid.UUID) (bool, error)
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	FindPreferences(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error)
+	UpsertPreferences(ctx context.Context, preferences []models.NotificationPreference) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	return r.db.WithContext(ctx).Create(notification).Error
+}
+
+func (r *notificationRepository) FindByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	err := query.Order("created_at DESC").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Notification{}).Where("id = ? AND user_id = ?", notificationID, userID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return false, nil
+	}
+	err := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", notificationID, userID).
+		Update("read_at", time.Now()).Error
+	return true, err
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+func (r *notificationRepository) FindPreferences(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error) {
+	var preferences []models.NotificationPreference
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&preferences).Error
+	return preferences, err
+}
+
+// UpsertPreferences insert atau update berdasarkan unique (user_id, type, channel).
+func (r *notificationRepository) UpsertPreferences(ctx context.Context, preferences []models.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}, {Name: "channel"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(&preferences).Error
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"cashflow_gin/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PayeeScope: GroupID diisi = payee milik group itu, kosong = payee pribadi UserID.
+type PayeeScope struct {
+	UserID  uuid.UUID
+	GroupID *uuid.UUID
+}
+
+func (s PayeeScope) apply(db *gorm.DB) *gorm.DB {
+	if s.GroupID != nil {
+		return db.Where("payees.group_id = ?", *s.GroupID)
+	}
+	return db.Where("payees.user_id = ? AND payees.group_id IS NULL", s.UserID)
+}
+
+type PayeeRepository interface {
+	Create(ctx context.Context, payee *models.Payee) error
+	Update(ctx context.Context, payee *models.Payee) error
+	Delete(ctx context.Context, payeeID uuid.UUID) error
+	FindByID(ctx context.Context, payeeID uuid.UUID) (*models.Payee, error)
+	FindByIDs(ctx context.Context, payeeIDs []uuid.UUID) ([]models.Payee, error)
+	FindByNormalized(ctx context.Context, scope PayeeScope, normalized string) (*models.Payee, error)
+	Search(ctx context.Context, scope PayeeScope, normalizedQuery string, limit int) ([]models.Payee, error)
+	Merge(ctx context.Context, targetID uuid.UUID, sourceIDs []uuid.UUID, newAliases []models.PayeeAlias) error
+	// FindTransactions cuma balikin transaksi di wallet yang bisa diakses userID (pribadi + group yang diikuti).
+	FindTransactions(ctx context.Context, payeeID, userID uuid.UUID, from, to *time.Time) ([]models.Transaction, error)
+}
+
+type payeeRepository struct {
+	db *gorm.DB
+}
+
+func NewPayeeRepository(db *gorm.DB) PayeeRepository {
+	return &payeeRepository{db: db}
+}
+
+func (r *payeeRepository) Create(ctx context.Context, payee *models.Payee) error {
+	return r.db.WithContext(ctx).Omit("DefaultCategory").Create(payee).Error
+}
+
+// Update nulis field payee + ganti semua alias (alias lama di-hard delete).
+func (r *payeeRepository) Update(ctx context.Context, payee *models.Payee) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Payee{}).Where("id = ?", payee.ID).Updates(map[string]interface{}{
+			"name":                payee.Name,
+			"normalized_name":     payee.NormalizedName,
+			"default_category_id": payee.DefaultCategoryID,
+			"updated_at":          time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Where("payee_id = ?", payee.ID).Delete(&models.PayeeAlias{}).Error; err != nil {
+			return err
+		}
+		if len(payee.Aliases) == 0 {
+			return nil
+		}
+		for i := range payee.Aliases {
+			payee.Aliases[i].ID = uuid.Nil
+			payee.Aliases[i].PayeeID = payee.ID
+		}
+		return tx.Create(&payee.Aliases).Error
+	})
+}
+
+// Delete: transaksinya gak ikut kehapus, cuma dilepas dari payee.
+func (r *payeeRepository) Delete(ctx context.Context, payeeID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Transaction{}).Where("payee_id = ?", payeeID).Update("payee_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("payee_id = ?", payeeID).Delete(&models.PayeeAlias{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Payee{}, "id = ?", payeeID).Error
+	})
+}
+
+func (r *payeeRepository) FindByID(ctx context.Context, payeeID uuid.UUID) (*models.Payee, error) {
+	var payee models.Payee
+	err := r.db.WithContext(ctx).Preload("Aliases").Preload("DefaultCategory").First(&payee, "id = ?", payeeID).Error
+	return &payee, err
+}
+
+func (r *payeeRepository) FindByIDs(ctx context.Context, payeeIDs []uuid.UUID) ([]models.Payee, error) {
+	var payees []models.Payee
+	err := r.db.WithContext(ctx).Preload("Aliases").Where("id IN ?", payeeIDs).Find(&payees).Error
+	return payees, err
+}
+
+// FindByNormalized cari payee di scope yang nama ATAU salah satu alias-nya cocok. nil kalau gak ada.
+func (r *payeeRepository) FindByNormalized(ctx context.Context, scope PayeeScope, normalized string) (*models.Payee, error) {
+	var payee models.Payee
+	query := scope.apply(r.db.WithContext(ctx).Model(&models.Payee{})).
+		Where("payees.normalized_name = ? OR EXISTS (SELECT 1 FROM payee_aliases a WHERE a.payee_id = payees.id AND a.normalized_alias = ? AND a.deleted_at IS NULL)",
+			normalized, normalized)
+	err := query.Preload("DefaultCategory").Order("payees.created_at").First(&payee).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &payee, nil
+}
+
+// Search buat autocomplete: nama/alias diawali query (atau ada kata yang diawali query),
+// payee yang paling sering dipakai muncul duluan.
+func (r *payeeRepository) Search(ctx context.Context, scope PayeeScope, normalizedQuery string, limit int) ([]models.Payee, error) {
+	prefix := normalizedQuery + "%"
+	word := "% " + normalizedQuery + "%"
+
+	var payees []models.Payee
+	err := scope.apply(r.db.WithContext(ctx).Model(&models.Payee{})).
+		Select("payees.*, (SELECT COUNT(*) FROM transactions t WHERE t.payee_id = payees.id AND t.deleted_at IS NULL) AS transaction_count").
+		Where(`payees.normalized_name LIKE ? OR payees.normalized_name LIKE ? OR EXISTS (
+			SELECT 1 FROM payee_aliases a WHERE a.payee_id = payees.id AND a.deleted_at IS NULL
+			AND (a.normalized_alias LIKE ? OR a.normalized_alias LIKE ?))`, prefix, word, prefix, word).
+		Preload("Aliases").Preload("DefaultCategory").
+		Order("transaction_count DESC, payees.name").
+		Limit(limit).
+		Find(&payees).Error
+	return payees, err
+}
+
+// Merge pindahin transaksi sources ke target, alias sources diganti newAliases (udah di-dedupe Service:
+// nama + alias source yang belum ada di target), lalu sources dihapus.
+func (r *payeeRepository) Merge(ctx context.Context, targetID uuid.UUID, sourceIDs []uuid.UUID, newAliases []models.PayeeAlias) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Transaction{}).Where("payee_id IN ?", sourceIDs).Update("payee_id", targetID).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("payee_id IN ?", sourceIDs).Delete(&models.PayeeAlias{}).Error; err != nil {
+			return err
+		}
+		if len(newAliases) > 0 {
+			for i := range newAliases {
+				newAliases[i].PayeeID = targetID
+			}
+			if err := tx.Create(&newAliases).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&models.Payee{}, "id IN ?", sourceIDs).Error
+	})
+}
+
+// FindTransactions = riwayat transaksi payee, terbaru dulu. from/to opsional (to exclusive).
+func (r *payeeRepository) FindTransactions(ctx context.Context, payeeID, userID uuid.UUID, from, to *time.Time) ([]models.Transaction, error) {
+	// Sama kayak walletRepository.FindAccessibleByUserID
+	memberGroups := r.db.WithContext(ctx).Model(&models.GroupMember{}).Select("group_id").Where("user_id = ?", userID)
+	accessibleWallets := r.db.WithContext(ctx).Model(&models.Wallet{}).Select("id").
+		Where("(user_id = ? AND group_id IS NULL) OR group_id IN (?)", userID, memberGroups)
+
+	query := r.db.WithContext(ctx).Preload("Category").Preload("Lines", orderLines).Preload("Lines.Category").
+		Where("payee_id = ? AND wallet_id IN (?)", payeeID, accessibleWallets)
+	if from != nil {
+		query = query.Where("date >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("date < ?", *to)
+	}
+
+	var transactions []models.Transaction
+	err := query.Order("date DESC").Find(&transactions).Error
+	return transactions, err
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReportFilter: WalletID diisi = semua transaksi di wallet itu (termasuk punya member group lain),
+// kosong = semua transaksi milik UserID.
+type ReportFilter struct {
+	UserID   uuid.UUID
+	WalletID *uuid.UUID
+	From     time.Time
+	To       time.Time // exclusive
+}
+
+// CategoryTotal = total per kategori. Transaksi split dihitung per line, bukan per kategori utamanya.
+type CategoryTotal struct {
+	CategoryID       uuid.UUID
+	Name             string
+	Type             string
+	Total            float64
+	TransactionCount int64
+}
+
+type ReportRepository interface {
+	CategoryTotals(ctx context.Context, filter ReportFilter) ([]CategoryTotal, error)
+}
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+func (r *reportRepository) CategoryTotals(ctx context.Context, filter ReportFilter) ([]CategoryTotal, error) {
+	// Satu baris per line (kalau split) atau per transaksi (kalau gak)
+	entries := r.db.WithContext(ctx).Table("transactions t").
+		Select("t.id AS transaction_id, COALESCE(l.category_id, t.category_id) AS category_id, COALESCE(l.amount, t.amount) AS amount").
+		Joins("LEFT JOIN transaction_lines l ON l.transaction_id = t.id AND l.deleted_at IS NULL").
+		Where("t.deleted_at IS NULL AND t.date >= ? AND t.date < ?", filter.From, filter.To)
+	if filter.WalletID != nil {
+		entries = entries.Where("t.wallet_id = ?", *filter.WalletID)
+	} else {
+		entries = entries.Where("t.user_id = ?", filter.UserID)
+	}
+
+	var totals []CategoryTotal
+	err := r.db.WithContext(ctx).Table("(?) AS x", entries).
+		Select("c.id AS category_id, c.name, c.type, SUM(x.amount) AS total, COUNT(DISTINCT x.transaction_id) AS transaction_count").
+		Joins("JOIN categories c ON c.id = x.category_id").
+		Group("c.id, c.name, c.type").
+		Order("c.type, total").
+		Scan(&totals).Error
+	return totals, err
+}
@@ -0,0 +1,41 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Repositories = semua repository yang dipakai aplikasi, dikumpulin biar implementasinya
+// (GORM atau in-memory di package repository/memory) cukup dipilih sekali pas startup.
+type Repositories struct {
+	User            UserRepository
+	Auth            AuthRepository
+	Transaction     TransactionRepository
+	Category        CategoryRepository
+	Group           GroupRepository
+	Wallet          WalletRepository
+	APIKey          APIKeyRepository
+	Webhook         WebhookRepository
+	Notification    NotificationRepository
+	StatementImport StatementImportRepository
+	CategoryRule    CategoryRuleRepository
+	Idempotency     IdempotencyRepository
+	Report          ReportRepository
+	Payee           PayeeRepository
+}
+
+func NewRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		User:            NewUserRepository(db),
+		Auth:            NewAuthRepository(db),
+		Transaction:     NewTransactionRepository(db),
+		Category:        NewCategoryRepository(db),
+		Group:           NewGroupRepository(db),
+		Wallet:          NewWalletRepository(db),
+		APIKey:          NewAPIKeyRepository(db),
+		Webhook:         NewWebhookRepository(db),
+		Notification:    NewNotificationRepository(db),
+		StatementImport: NewStatementImportRepository(db),
+		CategoryRule:    NewCategoryRuleRepository(db),
+		Idempotency:     NewIdempotencyRepository(db),
+		Report:          NewReportRepository(db),
+		Payee:           NewPayeeRepository(db),
+	}
+}
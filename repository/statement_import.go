@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrImportNotPending: status import udah bukan pending (udah di-commit, atau barengan di-commit request lain).
+var ErrImportNotPending = apperror.Conflict("import_already_committed", "import sudah di-commit")
+
+// ImportCommitItem = satu baris import yang mau dijadiin transaksi.
+type ImportCommitItem struct {
+	Row         *models.StatementImportRow
+	Transaction models.Transaction
+}
+
+type StatementImportRepository interface {
+	Create(ctx context.Context, statementImport *models.StatementImport) error
+	FindByIDAndUserID(ctx context.Context, importID, userID uuid.UUID) (*models.StatementImport, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.StatementImport, error)
+	UpdateRows(ctx context.Context, rows []models.StatementImportRow) error
+	// Commit = satu DB transaction: status pending -> committed (gagal ErrImportNotPending kalau udah bukan
+	// pending), cek ulang duplikat fingerprint di wallet, insert transaksi + geser saldo, simpen hasil per baris.
+	// Baris yang ternyata duplikat ditandai Duplicate+Skip dan gak dibikin transaksinya.
+	// Balikin transaksi yang beneran dibikin.
+	Commit(ctx context.Context, statementImport *models.StatementImport, walletID uuid.UUID, items []ImportCommitItem) ([]models.Transaction, error)
+
+	// ExistingFingerprints balikin fingerprint yang udah ada di transaksi wallet tsb.
+	ExistingFingerprints(ctx context.Context, walletID uuid.UUID, fingerprints []string) (map[string]bool, error)
+}
+
+type statementImportRepository struct {
+	db *gorm.DB
+}
+
+func NewStatementImportRepository(db *gorm.DB) StatementImportRepository {
+	return &statementImportRepository{db: db}
+}
+
+func (r *statementImportRepository) Create(ctx context.Context, statementImport *models.StatementImport) error {
+	return r.db.WithContext(ctx).Create(statementImport).Error
+}
+
+func (r *statementImportRepository) FindByIDAndUserID(ctx context.Context, importID, userID uuid.UUID) (*models.StatementImport, error) {
+	var statementImport models.StatementImport
+	err := r.db.WithContext(ctx).
+		Preload("Rows", func(db *gorm.DB) *gorm.DB {
+			return db.Order("date ASC, created_at ASC")
+		}).
+		Preload("Rows.Category").
+		First(&statementImport, "id = ? AND user_id = ?", importID, userID).Error
+	return &statementImport, err
+}
+
+func (r *statementImportRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.StatementImport, error) {
+	var imports []models.StatementImport
+	err := r.db.WithContext(ctx).Preload("Rows").Where("user_id = ?", userID).Order("created_at DESC").Find(&imports).Error
+	return imports, err
+}
+
+func (r *statementImportRepository) UpdateRows(ctx context.Context, rows []models.StatementImportRow) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			if err := tx.Model(&models.StatementImportRow{}).
+				Where("id = ?", row.ID).
+				Updates(map[string]interface{}{
+					"category_id":    row.CategoryID,
+					"skip":           row.Skip,
+					"transaction_id": row.TransactionID,
+				}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *statementImportRepository) Commit(ctx context.Context, statementImport *models.StatementImport, walletID uuid.UUID, items []ImportCommitItem) ([]models.Transaction, error) {
+	var created []models.Transaction
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// UPDATE bersyarat duluan: dari dua commit yang barengan cuma satu yang kena baris ini,
+		// yang satu lagi langsung batal sebelum sempat insert apa-apa
+		res := tx.Model(&models.StatementImport{}).
+			Where("id = ? AND status = ?", statementImport.ID, models.StatementImportPending).
+			Updates(map[string]interface{}{
+				"status":     models.StatementImportCommitted,
+				"updated_at": time.Now(),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrImportNotPending
+		}
+
+		// Kunci wallet dulu baru cek duplikat, biar import lain ke wallet yang sama (file yang sama
+		// di-upload 2x) antri dan lihat transaksi hasil commit ini
+		if err := lockWallets(tx, walletID); err != nil {
+			return err
+		}
+		fingerprints := make([]string, 0, len(items))
+		for _, item := range items {
+			fingerprints = append(fingerprints, item.Transaction.Fingerprint)
+		}
+		existing, err := findFingerprints(tx, walletID, fingerprints)
+		if err != nil {
+			return err
+		}
+
+		var rows []*models.StatementImportRow
+		for _, item := range items {
+			// Row.Duplicate = user udah tau duplikat tapi sengaja gak di-skip, tetap dibikin
+			if existing[item.Transaction.Fingerprint] && !item.Row.Duplicate {
+				item.Row.Duplicate, item.Row.Skip = true, true
+				if err := tx.Model(&models.StatementImportRow{}).
+					Where("id = ?", item.Row.ID).
+					Updates(map[string]interface{}{"duplicate": true, "skip": true}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			rows = append(rows, item.Row)
+			created = append(created, item.Transaction)
+		}
+		if len(created) == 0 {
+			return nil
+		}
+
+		if err := tx.Omit("Lines", "Payee").Create(&created).Error; err != nil {
+			return err
+		}
+		deltas := make(map[uuid.UUID]float64)
+		for i := range created {
+			if err := saveTransactionLines(tx, &created[i]); err != nil {
+				return err
+			}
+			deltas[created[i].WalletID] += created[i].Amount
+		}
+		if err := applyWalletDeltas(tx, deltas); err != nil {
+			return err
+		}
+
+		for i, row := range rows {
+			id := created[i].ID
+			row.TransactionID = &id
+			if err := tx.Model(&models.StatementImportRow{}).
+				Where("id = ?", row.ID).
+				Update("transaction_id", row.TransactionID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	statementImport.Status = models.StatementImportCommitted
+	return created, nil
+}
+
+func (r *statementImportRepository) ExistingFingerprints(ctx context.Context, walletID uuid.UUID, fingerprints []string) (map[string]bool, error) {
+	return findFingerprints(r.db.WithContext(ctx), walletID, fingerprints)
+}
+
+func findFingerprints(db *gorm.DB, walletID uuid.UUID, fingerprints []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(fingerprints) == 0 {
+		return existing, nil
+	}
+
+	var found []string
+	err := db.Model(&models.Transaction{}).
+		Where("wallet_id = ? AND fingerprint IN ?", walletID, fingerprints).
+		Pluck("fingerprint", &found).Error
+	for _, fp := range found {
+		existing[fp] = true
+	}
+	return existing, err
+}
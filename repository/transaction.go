@@ -2,6 +2,7 @@ package repository
 
 import (
 	"cashflow_gin/models"
+	"context"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,13 +10,20 @@ import (
 )
 
 type TransactionRepository interface {
-	CreateWithWalletUpdate(transaction *models.Transaction) error
-	FindAll() ([]models.Transaction, error)
-	IsOwner(userID uuid.UUID, walletID string) bool
-	FindByID(transactionID uuid.UUID) (*models.Transaction, error)
-	UpdateTransaction(transaction *models.Transaction) error
-	UpdateTransactionWithWalletBallance(transaction *models.Transaction, delta float64) error
-	SoftDeleteTransaction(transactionID uuid.UUID, delta float64, walletID uuid.UUID) error
+	CreateWithWalletUpdate(ctx context.Context, transaction *models.Transaction) error
+	CreateManyWithWalletUpdate(ctx context.Context, transactions []models.Transaction) error
+	UpdateManyWithWalletUpdate(ctx context.Context, transactions []models.Transaction, walletDeltas map[uuid.UUID]float64) error
+	SoftDeleteManyWithWalletUpdate(ctx context.Context, transactions []models.Transaction, walletDeltas map[uuid.UUID]float64) error
+	FindByIDs(ctx context.Context, transactionIDs []uuid.UUID) ([]models.Transaction, error)
+	FindByIDsAndUserID(ctx context.Context, transactionIDs []uuid.UUID, userID uuid.UUID) ([]models.Transaction, error)
+	FindByUserIDAndCategoryID(ctx context.Context, userID, categoryID uuid.UUID) ([]models.Transaction, error)
+	FindAll(ctx context.Context) ([]models.Transaction, error)
+	IsOwner(ctx context.Context, userID uuid.UUID, walletID string) bool
+	FindByID(ctx context.Context, transactionID uuid.UUID) (*models.Transaction, error)
+	UpdateTransaction(ctx context.Context, transaction *models.Transaction) error
+	UpdateTransactionWithWalletBallance(ctx context.Context, transaction *models.Transaction, delta float64) error
+	MoveTransactionWithWalletUpdate(ctx context.Context, transaction *models.Transaction, fromWalletID uuid.UUID, oldAmount float64) error
+	SoftDeleteTransaction(ctx context.Context, transactionID uuid.UUID, version int, delta float64, walletID uuid.UUID) error
 }
 
 type transactionRepository struct {
@@ -27,17 +35,23 @@ func NewTransactionRepository(db *gorm.DB) TransactionRepository {
 }
 
 // INI LOGIC PENTING: Transaction Database (ACID)
-func (r *transactionRepository) CreateWithWalletUpdate(transaction *models.Transaction) error {
+func (r *transactionRepository) CreateWithWalletUpdate(ctx context.Context, transaction *models.Transaction) error {
 	// Mulai DB Transaction
-	return r.db.Transaction(func(tx *gorm.DB) error {
-		// 1. Create Transaction Record
-		if err := tx.Create(transaction).Error; err != nil {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Create Transaction Record (+ lines kalau transaksinya split)
+		if err := tx.Omit("Lines", "Payee").Create(transaction).Error; err != nil {
 			return err // Rollback otomatis kalau error
 		}
+		if err := saveTransactionLines(tx, transaction); err != nil {
+			return err
+		}
 
 		// 2. Update Wallet Balance
 		// Logic matematika (tambah/kurang) sudah ditentukan di Service lewat field Amount
-		// Kita pakai gorm.Expr biar aman dari race condition
+		// Row wallet dikunci (FOR UPDATE) dulu biar delta dari request lain antri
+		if err := lockWallets(tx, transaction.WalletID); err != nil {
+			return err
+		}
 		if err := tx.Model(&models.Wallet{}).
 			Where("id = ?", transaction.WalletID).
 			Updates(map[string]interface{}{
@@ -51,62 +65,185 @@ func (r *transactionRepository) CreateWithWalletUpdate(transaction *models.Trans
 	})
 }
 
-func (r *transactionRepository) FindAll() ([]models.Transaction, error) {
+// CreateManyWithWalletUpdate = CreateWithWalletUpdate versi banyak, semua transaksi + saldo
+// wallet-nya masuk dalam satu DB transaction (all or nothing).
+func (r *transactionRepository) CreateManyWithWalletUpdate(ctx context.Context, transactions []models.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Lines", "Payee").Create(&transactions).Error; err != nil {
+			return err
+		}
+		for i := range transactions {
+			if err := saveTransactionLines(tx, &transactions[i]); err != nil {
+				return err
+			}
+		}
+
+		deltas := make(map[uuid.UUID]float64)
+		for _, t := range transactions {
+			deltas[t.WalletID] += t.Amount
+		}
+		return applyWalletDeltas(tx, deltas)
+	})
+}
+
+// UpdateManyWithWalletUpdate simpen banyak transaksi sekaligus + geser saldo tiap wallet sesuai
+// walletDeltas, semuanya dalam satu DB transaction.
+func (r *transactionRepository) UpdateManyWithWalletUpdate(ctx context.Context, transactions []models.Transaction, walletDeltas map[uuid.UUID]float64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Satu aja yang versinya basi -> semuanya batal (ErrStaleVersion)
+		for i := range transactions {
+			if err := updateTransactionVersioned(tx, &transactions[i]); err != nil {
+				return err
+			}
+		}
+
+		return applyWalletDeltas(tx, walletDeltas)
+	})
+}
+
+// SoftDeleteManyWithWalletUpdate: soft delete banyak transaksi + kembalikan saldo per wallet, satu DB transaction.
+// Tiap transaksi dihapus pakai version yang di-load, sama kayak delete satuan.
+func (r *transactionRepository) SoftDeleteManyWithWalletUpdate(ctx context.Context, transactions []models.Transaction, walletDeltas map[uuid.UUID]float64) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, t := range transactions {
+			result := tx.Where("id = ? AND version = ?", t.ID, t.Version).Delete(&models.Transaction{})
+			if result.Error != nil {
+				return result.Error
+			}
+			// Udah kehapus / diubah duluan -> delta saldo-nya basi, batalin semua
+			if result.RowsAffected == 0 {
+				return ErrStaleVersion
+			}
+		}
+
+		return applyWalletDeltas(tx, walletDeltas)
+	})
+}
+
+func (r *transactionRepository) FindByIDs(ctx context.Context, transactionIDs []uuid.UUID) ([]models.Transaction, error) {
 	var transactions []models.Transaction
-	err := r.db.Preload("Category").Preload("Wallet").Find(&transactions).Error
+	err := r.db.WithContext(ctx).Preload("Category").Preload("Wallet").Preload("Lines", orderLines).Preload("Lines.Category").Preload("Payee").
+		Where("id IN ?", transactionIDs).
+		Find(&transactions).Error
 	return transactions, err
 }
 
-func (r *transactionRepository) IsOwner(userID uuid.UUID, walletID string) bool {
+func (r *transactionRepository) FindByIDsAndUserID(ctx context.Context, transactionIDs []uuid.UUID, userID uuid.UUID) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.db.WithContext(ctx).Preload("Category").Preload("Wallet").Preload("Lines", orderLines).Preload("Lines.Category").Preload("Payee").
+		Where("id IN ? AND user_id = ?", transactionIDs, userID).
+		Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *transactionRepository) FindByUserIDAndCategoryID(ctx context.Context, userID, categoryID uuid.UUID) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.db.WithContext(ctx).Preload("Category").Preload("Wallet").Preload("Lines", orderLines).Preload("Lines.Category").Preload("Payee").
+		Where("user_id = ? AND category_id = ?", userID, categoryID).
+		Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *transactionRepository) FindAll(ctx context.Context) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.db.WithContext(ctx).Preload("Category").Preload("Wallet").Preload("Lines", orderLines).Preload("Lines.Category").Preload("Payee").Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *transactionRepository) IsOwner(ctx context.Context, userID uuid.UUID, walletID string) bool {
 	var wallet models.Wallet
-	err := r.db.Where("id = ? AND user_id = ?", walletID, userID).First(&wallet).Error
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", walletID, userID).First(&wallet).Error
 	return err == nil
 }
 
-func (r *transactionRepository) FindByID(transactionID uuid.UUID) (*models.Transaction, error) {
+func (r *transactionRepository) FindByID(ctx context.Context, transactionID uuid.UUID) (*models.Transaction, error) {
 	var transaction models.Transaction
-	err := r.db.Preload("Category").Preload("Wallet").First(&transaction, "id = ?", transactionID).Error
+	err := r.db.WithContext(ctx).Preload("Category").Preload("Wallet").Preload("Lines", orderLines).Preload("Lines.Category").Preload("Payee").First(&transaction, "id = ?", transactionID).Error
 	return &transaction, err
 }
 
-func (r *transactionRepository) UpdateTransaction(transaction *models.Transaction) error {
-	return r.db.Save(transaction).Error
+// UpdateTransaction gak pakai db.Save lagi biar dua orang yang edit bareng gak saling timpa.
+func (r *transactionRepository) UpdateTransaction(ctx context.Context, transaction *models.Transaction) error {
+	// Dibungkus DB transaction karena lines (kalau ada) ikut diganti
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateTransactionVersioned(tx, transaction)
+	})
 }
 
-func (r *transactionRepository) UpdateTransactionWithWalletBallance(transaction *models.Transaction, delta float64) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
-		// 1. Update Transaction Record
-		if err := tx.Save(transaction).Error; err != nil {
+func (r *transactionRepository) UpdateTransactionWithWalletBallance(ctx context.Context, transaction *models.Transaction, delta float64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Update Transaction Record (cek version)
+		if err := updateTransactionVersioned(tx, transaction); err != nil {
 			return err // Rollback otomatis kalau error
 		}
 
 		// 2. Update Wallet Balance
 		// Logic matematika (tambah/kurang) sudah ditentukan di Service lewat field Amount
-		// Kita pakai gorm.Expr biar aman dari race condition
-		if err := tx.Model(&models.Wallet{}).
-			Where("id = ?", transaction.WalletID).
-			Update("balance", gorm.Expr("balance + ?", delta)).Error; err != nil {
-			return err // Rollback otomatis
+		return applyWalletDeltas(tx, map[uuid.UUID]float64{transaction.WalletID: delta})
+	})
+}
+
+// MoveTransactionWithWalletUpdate dipakai kalau transaksi pindah wallet: nominal lama dikeluarin dari
+// wallet asal, nominal baru dimasukin ke wallet tujuan, semua dalam satu DB transaction.
+func (r *transactionRepository) MoveTransactionWithWalletUpdate(ctx context.Context, transaction *models.Transaction, fromWalletID uuid.UUID, oldAmount float64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updateTransactionVersioned(tx, transaction); err != nil {
+			return err
 		}
 
-		return nil // Commit
+		// Balikin saldo wallet asal + tambahin ke wallet tujuan (dua-duanya dikunci dulu)
+		return applyWalletDeltas(tx, map[uuid.UUID]float64{
+			fromWalletID:         -oldAmount,
+			transaction.WalletID: transaction.Amount,
+		})
 	})
 }
 
-func (r *transactionRepository) SoftDeleteTransaction(transactionId uuid.UUID, delta float64, walletID uuid.UUID) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+// SoftDeleteTransaction cuma jalan kalau version masih sama dengan yang dibaca service.
+func (r *transactionRepository) SoftDeleteTransaction(ctx context.Context, transactionId uuid.UUID, version int, delta float64, walletID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 1. Soft Delete Transaction Record
-		if err := tx.Where("id = ?", transactionId).Delete(&models.Transaction{}).Error; err != nil {
-			return err // Rollback otomatis kalau error
+		result := tx.Where("id = ? AND version = ?", transactionId, version).Delete(&models.Transaction{})
+		if result.Error != nil {
+			return result.Error // Rollback otomatis kalau error
 		}
-
-		// 2. Update Wallet Balance (kembalikan ke kondisi sebelum transaksi)
-		if err := tx.Model(&models.Wallet{}).
-			Where("id = ?", walletID). // Asumsi kita punya walletID di transactionID, bisa juga lewat join
-			Update("balance", gorm.Expr("balance + ?", delta)).Error; err != nil {
-			return err // Rollback otomatis
+		if result.RowsAffected == 0 {
+			return ErrStaleVersion
 		}
 
-		return nil // Commit
+		// 2. Update Wallet Balance (kembalikan ke kondisi sebelum transaksi)
+		return applyWalletDeltas(tx, map[uuid.UUID]float64{walletID: delta})
 	})
 }
+
+// saveTransactionLines insert lines baru (Category gak ikut di-upsert). Lines kosong = gak ngapa-ngapain.
+func saveTransactionLines(tx *gorm.DB, transaction *models.Transaction) error {
+	if len(transaction.Lines) == 0 {
+		return nil
+	}
+	for i := range transaction.Lines {
+		transaction.Lines[i].TransactionID = transaction.ID
+		transaction.Lines[i].Position = i
+	}
+	return tx.Omit("Category").Create(&transaction.Lines).Error
+}
+
+// replaceTransactionLines hapus (hard delete) lines lama lalu insert yang baru.
+func replaceTransactionLines(tx *gorm.DB, transaction *models.Transaction) error {
+	if err := tx.Unscoped().Where("transaction_id = ?", transaction.ID).Delete(&models.TransactionLine{}).Error; err != nil {
+		return err
+	}
+	return saveTransactionLines(tx, transaction)
+}
+
+func orderLines(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
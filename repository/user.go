@@ -3,16 +3,30 @@ package repository
 import (
 	"cashflow_gin/dto/request"
 	"cashflow_gin/models"
+	"context"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
-	FindByEmailOrUsername(email, username string) (*models.User, error)
-	FindAllUser() ([]models.User, error)
-	FindMyProfile(id uuid.UUID) (*models.User, error)
-	Login(input *request.LoginRequest) (*models.User, error)
+	FindByEmailOrUsername(ctx context.Context, email, username string) (*models.User, error)
+	// IsEmailTaken / IsUsernameTaken: udah dipakai user lain selain exceptID (termasuk yang soft delete, constraint unique-nya juga gitu)
+	IsEmailTaken(ctx context.Context, email string, exceptID uuid.UUID) (bool, error)
+	IsUsernameTaken(ctx context.Context, username string, exceptID uuid.UUID) (bool, error)
+	FindAllUser(ctx context.Context) ([]models.User, error)
+	FindMyProfile(ctx context.Context, id uuid.UUID) (*models.User, error)
+	Login(ctx context.Context, input *request.LoginRequest) (*models.User, error)
+
+	FindByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	UpdateProfile(ctx context.Context, user *models.User) error
+	UpdatePassword(ctx context.Context, userID uuid.UUID, hashedPassword string) error
+	FindOwnedGroups(ctx context.Context, userID uuid.UUID) ([]models.Group, error)
+	FindPersonalWallets(ctx context.Context, userID uuid.UUID) ([]models.Wallet, error)
+	FindTransactionsByUserID(ctx context.Context, userID uuid.UUID) ([]models.Transaction, error)
+	FindMemberships(ctx context.Context, userID uuid.UUID) ([]models.GroupMember, error)
+	DeleteAccount(ctx context.Context, userID uuid.UUID, ownerTransfers map[uuid.UUID]uuid.UUID) error
 }
 
 type userRepository struct {
@@ -23,25 +37,44 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) FindByEmailOrUsername(email, username string) (*models.User, error) {
+func (r *userRepository) FindByEmailOrUsername(ctx context.Context, email, username string) (*models.User, error) {
 	var user models.User
-	err := r.db.Where("email = ? OR username = ?", email, username).First(&user).Error
+	err := r.db.WithContext(ctx).Where("email = ? OR username = ?", email, username).First(&user).Error
 	return &user, err
 }
 
-func (r *userRepository) FindAllUser() ([]models.User, error) {
+func (r *userRepository) IsEmailTaken(ctx context.Context, email string, exceptID uuid.UUID) (bool, error) {
+	return r.isTaken(ctx, "email", email, exceptID)
+}
+
+func (r *userRepository) IsUsernameTaken(ctx context.Context, username string, exceptID uuid.UUID) (bool, error) {
+	return r.isTaken(ctx, "username", username, exceptID)
+}
+
+func (r *userRepository) isTaken(ctx context.Context, column, value string, exceptID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.User{}).
+		Where(clause.Eq{Column: clause.Column{Name: column}, Value: value}).
+		Where("id <> ?", exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *userRepository) FindAllUser(ctx context.Context) ([]models.User, error) {
 	var users []models.User
-	subQuery := `(
-        SELECT COUNT(*) 
-        FROM transactions t
-        JOIN wallets w ON t.wallet_id = w.id
-        WHERE w.user_id = users.id
-    )`
-	err := r.db.Select("users.*, " + subQuery + " as transaction_count").Preload("Wallets").Find(&users).Error
+	// Subquery dibikin lewat builder GORM (bukan string SQL) biar quoting tabel ikut dialect, jalan di postgres & SQLite
+	transactionCount := r.db.Model(&models.Transaction{}).
+		Select("COUNT(*)").
+		Joins("JOIN wallets ON wallets.id = transactions.wallet_id").
+		Where("wallets.user_id = ?", clause.Column{Table: "users", Name: "id"})
+	err := r.db.WithContext(ctx).
+		Select("?.*, (?) AS transaction_count", clause.Table{Name: "users"}, transactionCount).
+		Preload("Wallets").
+		Find(&users).Error
 	return users, err
 }
 
-func (r *userRepository) FindMyProfile(id uuid.UUID) (*models.User, error) {
+func (r *userRepository) FindMyProfile(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	var user *models.User
 	walletSelectQuery := `
         wallets.*, 
@@ -51,7 +84,7 @@ func (r *userRepository) FindMyProfile(id uuid.UUID) (*models.User, error) {
             WHERE transactions.wallet_id = wallets.id
         ) as transaction_count
     `
-	err := r.db.
+	err := r.db.WithContext(ctx).
 		// 1. Preload dengan Custom Query
 		Preload("Wallets", func(db *gorm.DB) *gorm.DB {
 			return db.Select(walletSelectQuery)
@@ -61,9 +94,113 @@ func (r *userRepository) FindMyProfile(id uuid.UUID) (*models.User, error) {
 	return user, err
 }
 
-func (r *userRepository) Login(input *request.LoginRequest) (*models.User, error) {
+func (r *userRepository) Login(ctx context.Context, input *request.LoginRequest) (*models.User, error) {
 	var user models.User
-	err := r.db.First(&user, "email = ?", input.Email).Error
+	err := r.db.WithContext(ctx).First(&user, "email = ?", input.Email).Error
 
 	return &user, err
 }
+
+func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error
+	return &user, err
+}
+
+func (r *userRepository) UpdateProfile(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Model(user).Updates(map[string]interface{}{
+		"username": user.Username,
+		"email":    user.Email,
+	}).Error
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, hashedPassword string) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Update("password", hashedPassword).Error
+}
+
+func (r *userRepository) FindOwnedGroups(ctx context.Context, userID uuid.UUID) ([]models.Group, error) {
+	var groups []models.Group
+	err := r.db.WithContext(ctx).Preload("Members", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Where("owner_id = ?", userID).Find(&groups).Error
+	return groups, err
+}
+
+func (r *userRepository) FindPersonalWallets(ctx context.Context, userID uuid.UUID) ([]models.Wallet, error) {
+	var wallets []models.Wallet
+	err := r.db.WithContext(ctx).Where("user_id = ? AND group_id IS NULL", userID).Find(&wallets).Error
+	return wallets, err
+}
+
+func (r *userRepository) FindTransactionsByUserID(ctx context.Context, userID uuid.UUID) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.db.WithContext(ctx).Preload("Category").Where("user_id = ?", userID).Order("date ASC").Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *userRepository) FindMemberships(ctx context.Context, userID uuid.UUID) ([]models.GroupMember, error) {
+	var memberships []models.GroupMember
+	err := r.db.WithContext(ctx).Preload("Group").Where("user_id = ?", userID).Find(&memberships).Error
+	return memberships, err
+}
+
+// DeleteAccount soft-delete user + wallet pribadi dalam satu DB transaction, sekalian matiin semua akses
+// yang nempel ke akun (API key, webhook, 2FA, preference notifikasi). ownerTransfers isinya groupID -> userID owner baru, wajib udah di-resolve di Service.
+func (r *userRepository) DeleteAccount(ctx context.Context, userID uuid.UUID, ownerTransfers map[uuid.UUID]uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Pindahin kepemilikan group + jadiin owner baru ADMIN
+		for groupID, newOwnerID := range ownerTransfers {
+			if err := tx.Model(&models.Group{}).
+				Where("id = ? AND owner_id = ?", groupID, userID).
+				Update("owner_id", newOwnerID).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.GroupMember{}).
+				Where("group_id = ? AND user_id = ?", groupID, newOwnerID).
+				Update("members_role", models.GroupAdmin).Error; err != nil {
+				return err
+			}
+		}
+
+		// 2. Keluarin user dari semua group
+		if err := tx.Where("user_id = ?", userID).Delete(&models.GroupMember{}).Error; err != nil {
+			return err
+		}
+
+		// 3. Soft delete transaksi di wallet pribadi, lalu wallet-nya
+		personalWallets := tx.Model(&models.Wallet{}).Select("id").Where("user_id = ? AND group_id IS NULL", userID)
+		if err := tx.Where("wallet_id IN (?)", personalWallets).Delete(&models.Transaction{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND group_id IS NULL", userID).Delete(&models.Wallet{}).Error; err != nil {
+			return err
+		}
+
+		// 4. Cabut API key & webhook (termasuk webhook group yang dia bikin), buang recovery code 2FA & preference
+		if err := tx.Where("user_id = ?", userID).Delete(&models.APIKey{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.WebhookSubscription{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.NotificationPreference{}).Error; err != nil {
+			return err
+		}
+
+		// 5. Anonymize data pribadi biar email/username bisa dipake lagi, baru soft delete user
+		anonymous := "deleted_" + userID.String()
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"username":           anonymous,
+			"email":              anonymous + "@deleted.local",
+			"password":           "",
+			"two_factor_enabled": false,
+			"two_factor_secret":  "",
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.User{}, "id = ?", userID).Error
+	})
+}
@@ -2,14 +2,26 @@ package repository
 
 import (
 	"cashflow_gin/models"
+	"context"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// BalanceMismatch = wallet yang kolom balance-nya beda dengan total amount transaksinya.
+type BalanceMismatch struct {
+	WalletID         uuid.UUID
+	Balance          float64
+	TransactionTotal float64
+}
+
 type WalletRepository interface {
-	FindAll() (*[]models.Wallet, error)
-	FindByID(walletID uuid.UUID) (models.Wallet, error)
+	FindAll(ctx context.Context) (*[]models.Wallet, error)
+	FindByID(ctx context.Context, walletID uuid.UUID) (models.Wallet, error)
+	FindBalance(ctx context.Context, walletID uuid.UUID) (float64, error)
+	FindByIDs(ctx context.Context, walletIDs []uuid.UUID) ([]models.Wallet, error)
+	FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]models.Wallet, error)
+	FindBalanceMismatches(ctx context.Context) ([]BalanceMismatch, error)
 }
 
 type walletRepository struct {
@@ -20,16 +32,54 @@ func NewWalletRepository(db *gorm.DB) WalletRepository {
 	return &walletRepository{db: db}
 }
 
-func (r *walletRepository) FindByID(walletID uuid.UUID) (models.Wallet, error) {
+func (r *walletRepository) FindByID(ctx context.Context, walletID uuid.UUID) (models.Wallet, error) {
 	var wallet models.Wallet
-	err := r.db.Where("id = ?", walletID).Preload("Transactions").Preload("Transactions.Category").Preload("Transactions.User").First(&wallet).Error
+	err := r.db.WithContext(ctx).Where("id = ?", walletID).Preload("Transactions").Preload("Transactions.Category").Preload("Transactions.User").First(&wallet).Error
 	return wallet, err
 }
 
-func (r *walletRepository) FindAll() (*[]models.Wallet, error) {
+func (r *walletRepository) FindAll(ctx context.Context) (*[]models.Wallet, error) {
 	var wallets []models.Wallet
-	err := r.db.Find(&wallets, func(db *gorm.DB) *gorm.DB {
+	err := r.db.WithContext(ctx).Find(&wallets, func(db *gorm.DB) *gorm.DB {
 		return db.Limit(10)
 	}).Error
 	return &wallets, err
 }
+
+func (r *walletRepository) FindBalance(ctx context.Context, walletID uuid.UUID) (float64, error) {
+	var wallet models.Wallet
+	err := r.db.WithContext(ctx).Select("balance").First(&wallet, "id = ?", walletID).Error
+	return wallet.Balance, err
+}
+
+// FindByIDs tanpa preload transaksi, cukup buat ngecek kepemilikan wallet.
+func (r *walletRepository) FindByIDs(ctx context.Context, walletIDs []uuid.UUID) ([]models.Wallet, error) {
+	var wallets []models.Wallet
+	err := r.db.WithContext(ctx).Where("id IN ?", walletIDs).Find(&wallets).Error
+	return wallets, err
+}
+
+// FindAccessibleByUserID = wallet pribadi user + wallet semua group yang dia ikuti.
+func (r *walletRepository) FindAccessibleByUserID(ctx context.Context, userID uuid.UUID) ([]models.Wallet, error) {
+	var wallets []models.Wallet
+	memberGroups := r.db.WithContext(ctx).Model(&models.GroupMember{}).Select("group_id").Where("user_id = ?", userID)
+	err := r.db.WithContext(ctx).
+		Where("(user_id = ? AND group_id IS NULL) OR group_id IN (?)", userID, memberGroups).
+		Find(&wallets).Error
+	return wallets, err
+}
+
+// FindBalanceMismatches bandingin saldo tiap wallet dengan SUM(amount) transaksi yang belum dihapus.
+// Saldo awal wallet selalu 0 & cuma digeser bareng transaksi, jadi harusnya selalu sama.
+func (r *walletRepository) FindBalanceMismatches(ctx context.Context) ([]BalanceMismatch, error) {
+	var mismatches []BalanceMismatch
+	err := r.db.WithContext(ctx).
+		Table("wallets w").
+		Select("w.id AS wallet_id, w.balance AS balance, COALESCE(SUM(t.amount), 0) AS transaction_total").
+		Joins("LEFT JOIN transactions t ON t.wallet_id = w.id AND t.deleted_at IS NULL").
+		Where("w.deleted_at IS NULL").
+		Group("w.id, w.balance").
+		Having("w.balance <> COALESCE(SUM(t.amount), 0)").
+		Scan(&mismatches).Error
+	return mismatches, err
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"cashflow_gin/models"
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, subscription *models.WebhookSubscription) error
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.WebhookSubscription, error)
+	FindByIDAndUserID(ctx context.Context, id, userID uuid.UUID) (*models.WebhookSubscription, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) (bool, error)
+	FindActiveByOwner(ctx context.Context, walletUserID, groupID *uuid.UUID) ([]models.WebhookSubscription, error)
+
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	FindDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]models.WebhookDelivery, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, subscription *models.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Create(subscription).Error
+}
+
+func (r *webhookRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *webhookRepository) FindByIDAndUserID(ctx context.Context, id, userID uuid.UUID) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&subscription).Error
+	return &subscription, err
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&models.WebhookSubscription{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindActiveByOwner: wallet group -> subscription milik group itu, wallet pribadi -> subscription user (tanpa group).
+// Filter per tipe event dilakukan di Service (kolom events bentuknya list dipisah koma).
+func (r *webhookRepository) FindActiveByOwner(ctx context.Context, walletUserID, groupID *uuid.UUID) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	query := r.db.WithContext(ctx).Where("active = ?", true)
+	switch {
+	case groupID != nil:
+		query = query.Where("group_id = ?", *groupID)
+	case walletUserID != nil:
+		query = query.Where("user_id = ? AND group_id IS NULL", *walletUserID)
+	default:
+		return subscriptions, nil
+	}
+	err := query.Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Create(delivery).Error
+}
+
+func (r *webhookRepository) FindDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.WithContext(ctx).Where("subscription_id = ?", subscriptionID).Order("created_at DESC").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
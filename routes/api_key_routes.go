@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"cashflow_gin/controllers"
+	"cashflow_gin/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func APIKeyRoutes(r *gin.RouterGroup, controller *controllers.APIKeyController, auth gin.HandlerFunc) {
+	apiKeys := r.Group("/users/me/api-keys")
+	apiKeys.Use(auth, middlewares.RequireJWT()) // API key gak boleh bikin/hapus API key
+	{
+		apiKeys.GET("/", controller.GetMyAPIKeys)
+		apiKeys.POST("/", controller.CreateAPIKey)
+		apiKeys.DELETE("/:id", controller.RevokeAPIKey)
+	}
+}
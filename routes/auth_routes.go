@@ -2,14 +2,38 @@ package routes
 
 import (
 	"cashflow_gin/controllers"
+	"cashflow_gin/middlewares"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthRoutes(r *gin.RouterGroup, controller *controllers.AuthController) {
+func AuthRoutes(r *gin.RouterGroup, controller *controllers.AuthController, limiter middlewares.RateLimitStore, authMiddleware gin.HandlerFunc) {
+	// Limit per route: bcrypt mahal, jadi login & register wajib di-throttle
+	registerLimit := middlewares.RateLimit(limiter,
+		middlewares.RateLimitRule{Name: "register-ip", Limit: 5, Window: time.Hour, KeyFunc: middlewares.KeyByIP},
+	)
+	loginLimit := middlewares.RateLimit(limiter,
+		middlewares.RateLimitRule{Name: "login-ip", Limit: 20, Window: time.Minute, KeyFunc: middlewares.KeyByIP},
+		middlewares.RateLimitRule{Name: "login-email", Limit: 5, Window: time.Minute, KeyFunc: middlewares.KeyByEmail},
+	)
+
+	twoFactorLimit := middlewares.RateLimit(limiter,
+		middlewares.RateLimitRule{Name: "login-2fa-ip", Limit: 10, Window: time.Minute, KeyFunc: middlewares.KeyByIP},
+	)
+
 	auth := r.Group("/auth")
 	{
-		auth.POST("/register", controller.Register)
-		auth.POST("/login", controller.Login)
+		auth.POST("/register", registerLimit, controller.Register)
+		auth.POST("/login", loginLimit, controller.Login)
+		auth.POST("/login/2fa", twoFactorLimit, controller.LoginTwoFactor)
+
+		twoFactor := auth.Group("/2fa")
+		twoFactor.Use(authMiddleware, middlewares.RequireJWT())
+		{
+			twoFactor.POST("/enroll", controller.EnrollTwoFactor)
+			twoFactor.POST("/enable", controller.EnableTwoFactor)
+			twoFactor.POST("/disable", controller.DisableTwoFactor)
+		}
 	}
 }
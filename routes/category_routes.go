@@ -3,20 +3,23 @@ package routes
 import (
 	"cashflow_gin/controllers"
 	"cashflow_gin/middlewares"
+	"cashflow_gin/models"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CategoryRoutes(r *gin.RouterGroup, controller *controllers.CategoryController) {
+func CategoryRoutes(r *gin.RouterGroup, controller *controllers.CategoryController, auth gin.HandlerFunc) {
+	jwtOnly := middlewares.RequireJWT()
+
 	categories := r.Group("/categories")
-	categories.Use(middlewares.AuthMiddleware())
+	categories.Use(auth)
 	{
-		categories.POST("/default-cat-admin-only-wlee", controller.CreateDefaultCategories)
-		categories.GET("/", controller.GetAllCategories)
+		categories.POST("/default-cat-admin-only-wlee", jwtOnly, controller.CreateDefaultCategories)
+		categories.GET("/", jwtOnly, controller.GetAllCategories)
 
-		categories.POST("/mine", controller.CreateMy)
-		categories.GET("/mine", controller.GetMine)
-		categories.PATCH("/:id/update", controller.UpdateById)
-		categories.PATCH("/:id/delete", controller.DeleteById)
+		categories.POST("/mine", jwtOnly, controller.CreateMy)
+		categories.GET("/mine", middlewares.RequireScope(models.ScopeCategoriesRead), controller.GetMine)
+		categories.PATCH("/:id/update", jwtOnly, middlewares.RequireIfMatch(), controller.UpdateById)
+		categories.PATCH("/:id/delete", jwtOnly, middlewares.RequireIfMatch(), controller.DeleteById)
 	}
 }
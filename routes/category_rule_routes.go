@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"cashflow_gin/controllers"
+	"cashflow_gin/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CategoryRuleRoutes(r *gin.RouterGroup, controller *controllers.CategoryRuleController, auth gin.HandlerFunc) {
+	rules := r.Group("/category-rules")
+	rules.Use(auth, middlewares.RequireJWT())
+	{
+		rules.GET("/", controller.GetMyRules)
+		rules.POST("/", controller.CreateRule)
+		rules.PUT("/order", controller.ReorderRules)
+		rules.POST("/test", controller.TestRules)
+		rules.POST("/apply", controller.ApplyRules)
+		rules.PUT("/:id", controller.UpdateRule)
+		rules.DELETE("/:id", controller.DeleteRule)
+	}
+}
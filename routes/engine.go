@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"cashflow_gin/config"
+	"cashflow_gin/middlewares"
+	"cashflow_gin/repository"
+	"cashflow_gin/validation"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
+)
+
+// NewEngine = handler HTTP lengkap (middleware + semua route), belum dinyalain.
+// Dipakai main.go, dan bisa juga langsung dibungkus httptest.NewServer bareng
+// memory.NewRepositories() (db nil) buat jalanin seluruh API tanpa database.
+func NewEngine(db *gorm.DB, repos repository.Repositories, cfg *config.Config, logger *slog.Logger) (*gin.Engine, *Background, error) {
+	// Validator custom (currency, category_type, money) & nama field JSON di error validasi
+	if err := validation.Register(); err != nil {
+		return nil, nil, err
+	}
+
+	// gin.New (bukan gin.Default) biar logger & recovery gak dobel
+	r := gin.New()
+	// X-Forwarded-For cuma dipercaya dari proxy yang terdaftar, kalau gak client bisa ganti-ganti IP
+	// sendiri buat lolos rate limit per IP
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, nil, err
+	}
+	r.Use(middlewares.RequestID(logger))
+	r.Use(middlewares.Tracing())
+	r.Use(middlewares.RequestLogger())
+	r.Use(middlewares.Recovery())
+	r.Use(middlewares.Metrics())
+	r.Use(middlewares.CORS(cfg.CORS.AllowedOrigins, cfg.CORS.AllowCredentials, cfg.CORS.MaxAge.Std()))
+	r.Use(middlewares.ErrorHandler())
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	background := SetupRoutes(db, repos, r, cfg)
+	return r, background, nil
+}
@@ -0,0 +1,733 @@
+package routes_test
+
+import (
+	"bytes"
+	"cashflow_gin/config"
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"cashflow_gin/repository/memory"
+	"cashflow_gin/routes"
+	"cashflow_gin/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestServer = seluruh API (NewEngine) di atas repository in-memory, tanpa database.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return newTestServerWithRepos(t, memory.NewRepositories())
+}
+
+// newTestServerWithRepos = newTestServer, tapi repository-nya boleh dibungkus dulu (mis. buat nyuntik error).
+func newTestServerWithRepos(t *testing.T, repos repository.Repositories) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	t.Setenv("APP_ENV", "development")
+	t.Setenv("DB_DRIVER", "memory")
+	t.Setenv("JWT_SECRET", "e2e-test-secret-yang-cukup-panjang-buat-hs256")
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	engine, background, err := routes.NewEngine(nil, repos, cfg, logger)
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+
+	srv := httptest.NewServer(engine)
+	t.Cleanup(func() {
+		srv.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		background.Stop(ctx)
+	})
+	return srv
+}
+
+type apiClient struct {
+	t     *testing.T
+	srv   *httptest.Server
+	token string
+}
+
+// do kirim request JSON & balikin status + field data dari BaseResponse.
+func (c *apiClient) do(method, path string, body interface{}) (int, json.RawMessage) {
+	c.t.Helper()
+	status, _, data := c.doWithHeaders(method, path, body, nil)
+	return status, data
+}
+
+// doWithHeaders = do plus header tambahan (If-Match, Idempotency-Key, ...), balikin header response juga.
+func (c *apiClient) doWithHeaders(method, path string, body interface{}, headers map[string]string) (int, http.Header, json.RawMessage) {
+	c.t.Helper()
+	status, header, envelope := c.send(method, path, body, headers)
+	return status, header, envelope.Data
+}
+
+// apiEnvelope = BaseResponse dari sisi client.
+type apiEnvelope struct {
+	Data   json.RawMessage `json:"data"`
+	Errors struct {
+		Code    string          `json:"code"`
+		Details json.RawMessage `json:"details"`
+	} `json:"errors"`
+}
+
+// mustFail = do + cek status error, balikin code & details dari field errors.
+func (c *apiClient) mustFail(method, path string, body interface{}, headers map[string]string, wantStatus int) (string, json.RawMessage) {
+	c.t.Helper()
+	status, _, envelope := c.send(method, path, body, headers)
+	if status != wantStatus {
+		c.t.Fatalf("%s %s: status %d, want %d (errors: %s %s)", method, path, status, wantStatus, envelope.Errors.Code, envelope.Errors.Details)
+	}
+	return envelope.Errors.Code, envelope.Errors.Details
+}
+
+func (c *apiClient) send(method, path string, body interface{}, headers map[string]string) (int, http.Header, apiEnvelope) {
+	c.t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(method, c.srv.URL+path, reader)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := c.srv.Client().Do(req)
+	if err != nil {
+		c.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer res.Body.Close()
+
+	var envelope apiEnvelope
+	raw, _ := io.ReadAll(res.Body)
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &envelope); err != nil {
+			c.t.Fatalf("%s %s: invalid JSON response %q", method, path, raw)
+		}
+	}
+	return res.StatusCode, res.Header, envelope
+}
+
+// mustDo = do + cek status, data di-decode ke out (boleh nil).
+func (c *apiClient) mustDo(method, path string, body interface{}, wantStatus int, out interface{}) {
+	c.t.Helper()
+	status, data := c.do(method, path, body)
+	if status != wantStatus {
+		c.t.Fatalf("%s %s: status %d, want %d (data: %s)", method, path, status, wantStatus, data)
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			c.t.Fatalf("%s %s: decode data: %v", method, path, err)
+		}
+	}
+}
+
+type walletData struct {
+	ID      string  `json:"id"`
+	Balance float64 `json:"balance"`
+}
+
+// signUp register + login, balikin client yang udah bawa token.
+func signUp(t *testing.T, srv *httptest.Server, username string) *apiClient {
+	t.Helper()
+	c := &apiClient{t: t, srv: srv}
+	email := username + "@example.com"
+	c.mustDo(http.MethodPost, "/api/auth/register", map[string]string{
+		"username": username,
+		"email":    email,
+		"password": "password-rahasia-123",
+	}, http.StatusCreated, nil)
+
+	var login struct {
+		Token string `json:"token"`
+	}
+	c.mustDo(http.MethodPost, "/api/auth/login", map[string]string{
+		"email":    email,
+		"password": "password-rahasia-123",
+	}, http.StatusOK, &login)
+	if login.Token == "" {
+		t.Fatal("login gak balikin token")
+	}
+	c.token = login.Token
+	return c
+}
+
+func (c *apiClient) personalWallet() walletData {
+	c.t.Helper()
+	var profile struct {
+		Wallets []walletData `json:"wallets"`
+	}
+	c.mustDo(http.MethodGet, "/api/users/me", nil, http.StatusOK, &profile)
+	if len(profile.Wallets) != 1 {
+		c.t.Fatalf("wallets = %+v, want 1 wallet pribadi", profile.Wallets)
+	}
+	return profile.Wallets[0]
+}
+
+func (c *apiClient) createCategory(name, categoryType string) string {
+	c.t.Helper()
+	var category struct {
+		ID string `json:"id"`
+	}
+	c.mustDo(http.MethodPost, "/api/categories/mine", map[string]string{"name": name, "type": categoryType}, http.StatusCreated, &category)
+	return category.ID
+}
+
+// createTransaction balikin ID transaksi yang baru dibikin.
+func (c *apiClient) createTransaction(walletID, category, title string, amount float64) string {
+	c.t.Helper()
+	var transaction struct {
+		ID string `json:"id"`
+	}
+	c.mustDo(http.MethodPost, "/api/transactions/", map[string]interface{}{
+		"wallet_id":     walletID,
+		"category_name": category,
+		"title":         title,
+		"amount":        amount,
+		"date":          time.Now().UTC().Format(time.RFC3339),
+	}, http.StatusOK, &transaction)
+	return transaction.ID
+}
+
+func TestTransactionFlow(t *testing.T) {
+	srv := newTestServer(t)
+	c := signUp(t, srv, "budi")
+
+	wallet := c.personalWallet()
+	if wallet.Balance != 0 {
+		t.Fatalf("saldo awal = %v, want 0", wallet.Balance)
+	}
+
+	// Wallet group dibikin bareng group-nya
+	var group struct {
+		ID     string     `json:"id"`
+		Wallet walletData `json:"wallet"`
+	}
+	c.mustDo(http.MethodPost, "/api/groups/", map[string]string{"name": "Keluarga"}, http.StatusOK, &group)
+	if group.Wallet.ID == "" {
+		t.Fatal("group dibikin tanpa wallet")
+	}
+
+	c.createCategory("Gaji", "INCOME")
+	c.createCategory("Makan", "EXPENSE")
+	c.createTransaction(wallet.ID, "Gaji", "Gaji Januari", 150000)
+	c.createTransaction(wallet.ID, "Makan", "Nasi padang", 25000.5)
+	c.createTransaction(group.Wallet.ID, "Makan", "Belanja bulanan", 10000)
+
+	if got := c.personalWallet().Balance; got != 124999.5 {
+		t.Errorf("saldo wallet pribadi = %v, want 124999.5", got)
+	}
+	c.mustDo(http.MethodGet, "/api/groups/"+group.ID, nil, http.StatusOK, &group)
+	if group.Wallet.Balance != -10000 {
+		t.Errorf("saldo wallet group = %v, want -10000", group.Wallet.Balance)
+	}
+
+	// Wallet orang lain gak boleh dipakai
+	other := signUp(t, srv, "siti")
+	other.createCategory("Ngemil", "EXPENSE")
+	status, _ := other.do(http.MethodPost, "/api/transactions/", map[string]interface{}{
+		"wallet_id":     wallet.ID,
+		"category_name": "Ngemil",
+		"title":         "Nyelonong",
+		"amount":        1,
+		"date":          time.Now().UTC().Format(time.RFC3339),
+	})
+	if status != http.StatusForbidden {
+		t.Errorf("transaksi ke wallet orang lain: status %d, want 403", status)
+	}
+}
+
+// Create barengan ke wallet yang sama: saldo akhir harus pas, gak ada update yang ketimpa.
+func TestConcurrentTransactionCreate(t *testing.T) {
+	srv := newTestServer(t)
+	c := signUp(t, srv, "andi")
+	c.createCategory("Jajan", "EXPENSE")
+	wallet := c.personalWallet()
+
+	const (
+		workers = 50
+		amount  = 1234.56
+	)
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			status, data := c.do(http.MethodPost, "/api/transactions/", map[string]interface{}{
+				"wallet_id":     wallet.ID,
+				"category_name": "Jajan",
+				"title":         fmt.Sprintf("Jajan #%d", i),
+				"amount":        amount,
+				"date":          time.Now().UTC().Format(time.RFC3339),
+			})
+			if status != http.StatusOK {
+				errs <- fmt.Errorf("transaksi #%d: status %d (data: %s)", i, status, data)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	want := -amount * workers
+	if got := c.personalWallet().Balance; fmt.Sprintf("%.2f", got) != fmt.Sprintf("%.2f", want) {
+		t.Errorf("saldo akhir = %.2f, want %.2f", got, want)
+	}
+}
+
+// withAPIKey bikin API key dengan scope tsb, balikin client yang autentikasi pakai key itu.
+func (c *apiClient) withAPIKey(scopes ...string) *apiClient {
+	c.t.Helper()
+	var created struct {
+		Key string `json:"key"`
+	}
+	c.mustDo(http.MethodPost, "/api/users/me/api-keys/", map[string]interface{}{
+		"name":   "script",
+		"scopes": scopes,
+	}, http.StatusCreated, &created)
+	return &apiClient{t: c.t, srv: c.srv, token: created.Key}
+}
+
+// Akun dihapus -> API key-nya ikut dicabut.
+func TestDeleteAccountRevokesAPIKeys(t *testing.T) {
+	srv := newTestServer(t)
+	c := signUp(t, srv, "dodi")
+	script := c.withAPIKey("transactions:read")
+	script.mustDo(http.MethodGet, "/api/transactions/", nil, http.StatusOK, nil)
+
+	c.mustDo(http.MethodDelete, "/api/users/me", map[string]interface{}{"password": "password-rahasia-123"}, http.StatusOK, nil)
+
+	if status, _ := script.do(http.MethodGet, "/api/transactions/", nil); status != http.StatusUnauthorized {
+		t.Errorf("API key setelah akun dihapus: status %d, want 401", status)
+	}
+}
+
+// Ganti kategori transaksi ke kategori pribadi user lain -> dianggap gak ada.
+func TestUpdateTransactionRejectsForeignCategory(t *testing.T) {
+	srv := newTestServer(t)
+	c := signUp(t, srv, "eko")
+	c.createCategory("Bensin", "EXPENSE")
+	id := c.createTransaction(c.personalWallet().ID, "Bensin", "Pertamax", 50000)
+
+	other := signUp(t, srv, "fina")
+	foreign := other.createCategory("Skincare", "EXPENSE")
+
+	status, _, _ := c.doWithHeaders(http.MethodPatch, "/api/transactions/"+id+"/update",
+		map[string]string{"category_id": foreign}, map[string]string{"If-Match": `"1"`})
+	if status != http.StatusNotFound {
+		t.Errorf("update ke kategori user lain: status %d, want 404", status)
+	}
+}
+
+// Pindah ke wallet group: transaksi yang payee-nya payee pribadi ditolak (per item di bulk).
+func TestMoveRejectsPayeeOutOfScope(t *testing.T) {
+	srv := newTestServer(t)
+	c := signUp(t, srv, "gilang")
+	c.createCategory("Kopi", "EXPENSE")
+	wallet := c.personalWallet()
+
+	var group struct {
+		Wallet walletData `json:"wallet"`
+	}
+	c.mustDo(http.MethodPost, "/api/groups/", map[string]string{"name": "Kantor"}, http.StatusOK, &group)
+
+	var withPayee struct {
+		ID string `json:"id"`
+	}
+	c.mustDo(http.MethodPost, "/api/transactions/", map[string]interface{}{
+		"wallet_id":     wallet.ID,
+		"category_name": "Kopi",
+		"payee_name":    "Kedai Kopi",
+		"title":         "Kopi susu",
+		"amount":        20000,
+		"date":          time.Now().UTC().Format(time.RFC3339),
+	}, http.StatusOK, &withPayee)
+	plain := c.createTransaction(wallet.ID, "Kopi", "Kopi sachet", 2000)
+
+	var result struct {
+		Succeeded int `json:"succeeded"`
+		Items     []struct {
+			Success bool   `json:"success"`
+			Error   string `json:"error"`
+		} `json:"items"`
+	}
+	c.mustDo(http.MethodPost, "/api/transactions/bulk/move", map[string]interface{}{
+		"wallet_id":       group.Wallet.ID,
+		"transaction_ids": []string{withPayee.ID, plain},
+	}, http.StatusOK, &result)
+	if result.Succeeded != 1 || result.Items[0].Success || !result.Items[1].Success {
+		t.Fatalf("bulk move = %+v, want item 0 gagal (payee), item 1 sukses", result)
+	}
+	if got := c.personalWallet().Balance; got != -20000 {
+		t.Errorf("saldo wallet pribadi = %v, want -20000", got)
+	}
+
+	status, _, _ := c.doWithHeaders(http.MethodPatch, "/api/transactions/"+withPayee.ID+"/update",
+		map[string]string{"wallet_id": group.Wallet.ID}, map[string]string{"If-Match": `"1"`})
+	if status != http.StatusUnprocessableEntity {
+		t.Errorf("update pindah wallet dengan payee pribadi: status %d, want 422", status)
+	}
+}
+
+// failingTransactions bikin create transaksi gagal (500) sekali, sisanya diterusin ke repo asli.
+type failingTransactions struct {
+	repository.TransactionRepository
+	failNext atomic.Bool
+}
+
+func (r *failingTransactions) CreateWithWalletUpdate(ctx context.Context, transaction *models.Transaction) error {
+	if r.failNext.CompareAndSwap(true, false) {
+		return errors.New("database lagi down")
+	}
+	return r.TransactionRepository.CreateWithWalletUpdate(ctx, transaction)
+}
+
+// Idempotency-Key: sukses & 4xx di-replay apa adanya, 5xx key-nya dilepas biar retry diproses ulang.
+func TestIdempotencyKeyReplay(t *testing.T) {
+	repos := memory.NewRepositories()
+	transactions := &failingTransactions{TransactionRepository: repos.Transaction}
+	repos.Transaction = transactions
+	srv := newTestServerWithRepos(t, repos)
+	c := signUp(t, srv, "hana")
+	c.createCategory("Parkir", "EXPENSE")
+	wallet := c.personalWallet()
+
+	create := func(key, category string) (int, http.Header, json.RawMessage) {
+		return c.doWithHeaders(http.MethodPost, "/api/transactions/", map[string]interface{}{
+			"wallet_id":     wallet.ID,
+			"category_name": category,
+			"title":         "Parkir mall",
+			"amount":        5000,
+			"date":          "2026-01-15T10:00:00Z",
+		}, map[string]string{"Idempotency-Key": key})
+	}
+
+	t.Run("replay setelah sukses", func(t *testing.T) {
+		status, _, first := create("sukses-1", "Parkir")
+		if status != http.StatusOK {
+			t.Fatalf("request pertama: status %d (data: %s)", status, first)
+		}
+		status, header, replayed := create("sukses-1", "Parkir")
+		if status != http.StatusOK || header.Get("Idempotent-Replayed") != "true" || string(replayed) != string(first) {
+			t.Fatalf("replay: status %d, replayed %q, data %s", status, header.Get("Idempotent-Replayed"), replayed)
+		}
+		if got := c.personalWallet().Balance; got != -5000 {
+			t.Errorf("saldo = %v, want -5000 (transaksi gak boleh dobel)", got)
+		}
+	})
+
+	t.Run("replay setelah 4xx", func(t *testing.T) {
+		status, _, _ := create("gagal-4xx", "Kategori Gak Ada")
+		if status != http.StatusNotFound {
+			t.Fatalf("request pertama: status %d, want 404", status)
+		}
+		// Kategori dibikin belakangan, tapi key yang sama tetap dapet hasil pertama
+		c.createCategory("Kategori Gak Ada", "EXPENSE")
+		status, header, _ := create("gagal-4xx", "Kategori Gak Ada")
+		if status != http.StatusNotFound || header.Get("Idempotent-Replayed") != "true" {
+			t.Fatalf("replay: status %d, replayed %q, want 404 replayed", status, header.Get("Idempotent-Replayed"))
+		}
+	})
+
+	t.Run("retry setelah 5xx", func(t *testing.T) {
+		before := c.personalWallet().Balance
+		transactions.failNext.Store(true)
+		if status, _, _ := create("gagal-5xx", "Parkir"); status != http.StatusInternalServerError {
+			t.Fatalf("request pertama: status %d, want 500", status)
+		}
+		status, header, data := create("gagal-5xx", "Parkir")
+		if status != http.StatusOK || header.Get("Idempotent-Replayed") != "" {
+			t.Fatalf("retry: status %d, replayed %q (data: %s), want 200 diproses ulang", status, header.Get("Idempotent-Replayed"), data)
+		}
+		if got := c.personalWallet().Balance; got != before-5000 {
+			t.Errorf("saldo = %v, want %v", got, before-5000)
+		}
+	})
+}
+
+// Password salah berkali-kali -> akun dikunci (429 + Retry-After), bukan cuma 401 terus.
+func TestLoginLockout(t *testing.T) {
+	srv := newTestServer(t)
+	c := &apiClient{t: t, srv: srv}
+	c.mustDo(http.MethodPost, "/api/auth/register", map[string]string{
+		"username": "indra",
+		"email":    "indra@example.com",
+		"password": "password-rahasia-123",
+	}, http.StatusCreated, nil)
+
+	wrong := map[string]string{"email": "indra@example.com", "password": "salah-terus"}
+	for i := 1; i < 5; i++ {
+		if code, _ := c.mustFail(http.MethodPost, "/api/auth/login", wrong, nil, http.StatusUnauthorized); code != "invalid_credentials" {
+			t.Fatalf("percobaan #%d: code %q, want invalid_credentials", i, code)
+		}
+	}
+	status, header, envelope := c.send(http.MethodPost, "/api/auth/login", wrong, nil)
+	if status != http.StatusTooManyRequests || envelope.Errors.Code != "account_locked" || header.Get("Retry-After") == "" {
+		t.Fatalf("percobaan ke-5: status %d, code %q, Retry-After %q, want 429 account_locked", status, envelope.Errors.Code, header.Get("Retry-After"))
+	}
+}
+
+// 2FA: login butuh kode TOTP, recovery code cuma bisa dipakai sekali.
+func TestTwoFactorLogin(t *testing.T) {
+	srv := newTestServer(t)
+	c := signUp(t, srv, "joko")
+
+	var enroll struct {
+		Secret string `json:"secret"`
+	}
+	c.mustDo(http.MethodPost, "/api/auth/2fa/enroll", nil, http.StatusOK, &enroll)
+	code, err := utils.GenerateTOTP(enroll.Secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var enabled struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	c.mustDo(http.MethodPost, "/api/auth/2fa/enable", map[string]string{"code": code}, http.StatusOK, &enabled)
+	if len(enabled.RecoveryCodes) == 0 {
+		t.Fatal("enable 2FA gak balikin recovery code")
+	}
+
+	anon := &apiClient{t: t, srv: srv}
+	challenge := func() string {
+		t.Helper()
+		var login struct {
+			Token             string `json:"token"`
+			TwoFactorRequired bool   `json:"two_factor_required"`
+			ChallengeToken    string `json:"challenge_token"`
+		}
+		anon.mustDo(http.MethodPost, "/api/auth/login", map[string]string{
+			"email":    "joko@example.com",
+			"password": "password-rahasia-123",
+		}, http.StatusOK, &login)
+		if login.Token != "" || !login.TwoFactorRequired || login.ChallengeToken == "" {
+			t.Fatalf("login dengan 2FA aktif = %+v, want challenge tanpa token", login)
+		}
+		return login.ChallengeToken
+	}
+
+	// Challenge token bukan access token
+	challengeToken := challenge()
+	(&apiClient{t: t, srv: srv, token: challengeToken}).mustFail(http.MethodGet, "/api/users/me", nil, nil, http.StatusUnauthorized)
+
+	anon.mustFail(http.MethodPost, "/api/auth/login/2fa", map[string]string{"challenge_token": challengeToken, "code": "000000"}, nil, http.StatusUnauthorized)
+	var login struct {
+		Token string `json:"token"`
+	}
+	anon.mustDo(http.MethodPost, "/api/auth/login/2fa", map[string]string{"challenge_token": challengeToken, "code": code}, http.StatusOK, &login)
+	(&apiClient{t: t, srv: srv, token: login.Token}).mustDo(http.MethodGet, "/api/users/me", nil, http.StatusOK, nil)
+
+	recovery := map[string]string{"challenge_token": challenge(), "code": enabled.RecoveryCodes[0]}
+	anon.mustDo(http.MethodPost, "/api/auth/login/2fa", recovery, http.StatusOK, nil)
+	recovery["challenge_token"] = challenge()
+	anon.mustFail(http.MethodPost, "/api/auth/login/2fa", recovery, nil, http.StatusUnauthorized)
+}
+
+// API key cuma bisa akses route sesuai scope-nya, route khusus sesi user (JWT) selalu ditolak.
+func TestAPIKeyScopes(t *testing.T) {
+	srv := newTestServer(t)
+	c := signUp(t, srv, "kiki")
+	c.createCategory("Listrik", "EXPENSE")
+	wallet := c.personalWallet()
+
+	reader := c.withAPIKey("transactions:read")
+	reader.mustDo(http.MethodGet, "/api/transactions/", nil, http.StatusOK, nil)
+	reader.mustFail(http.MethodPost, "/api/transactions/", map[string]interface{}{
+		"wallet_id":     wallet.ID,
+		"category_name": "Listrik",
+		"title":         "Token listrik",
+		"amount":        100000,
+		"date":          time.Now().UTC().Format(time.RFC3339),
+	}, nil, http.StatusForbidden)
+	if code, _ := reader.mustFail(http.MethodGet, "/api/users/me", nil, nil, http.StatusForbidden); code != "user_session_required" {
+		t.Errorf("GET /users/me pakai API key: code %q, want user_session_required", code)
+	}
+	reader.mustFail(http.MethodPost, "/api/users/me/api-keys/", map[string]interface{}{
+		"name":   "eskalasi",
+		"scopes": []string{"transactions:write"},
+	}, nil, http.StatusForbidden)
+
+	writer := c.withAPIKey("transactions:read", "transactions:write")
+	writer.createTransaction(wallet.ID, "Listrik", "Token listrik", 100000)
+}
+
+// Update tanpa If-Match -> 428, If-Match basi -> 412, If-Match pas -> sukses + ETag baru.
+func TestUpdateRequiresIfMatch(t *testing.T) {
+	srv := newTestServer(t)
+	c := signUp(t, srv, "lina")
+	c.createCategory("Pulsa", "EXPENSE")
+	id := c.createTransaction(c.personalWallet().ID, "Pulsa", "Pulsa 50rb", 50000)
+	path := "/api/transactions/" + id + "/update"
+	update := map[string]string{"title": "Pulsa + kuota"}
+
+	if code, _ := c.mustFail(http.MethodPatch, path, update, nil, http.StatusPreconditionRequired); code != "if_match_required" {
+		t.Errorf("tanpa If-Match: code %q, want if_match_required", code)
+	}
+	status, header, _ := c.doWithHeaders(http.MethodPatch, path, update, map[string]string{"If-Match": `"1"`})
+	if status != http.StatusOK || header.Get("ETag") != `"2"` {
+		t.Fatalf("If-Match pas: status %d, ETag %q, want 200 \"2\"", status, header.Get("ETag"))
+	}
+	if code, _ := c.mustFail(http.MethodPatch, path, update, map[string]string{"If-Match": `"1"`}, http.StatusPreconditionFailed); code != "version_mismatch" {
+		t.Errorf("If-Match basi: code %q, want version_mismatch", code)
+	}
+}
+
+// Bulk create: item yang gagal dilaporin per item, item lain tetap masuk.
+func TestBulkCreatePartialFailure(t *testing.T) {
+	srv := newTestServer(t)
+	c := signUp(t, srv, "mira")
+	c.createCategory("Laundry", "EXPENSE")
+	wallet := c.personalWallet()
+
+	item := func(category string, amount float64) map[string]interface{} {
+		return map[string]interface{}{
+			"wallet_id":     wallet.ID,
+			"category_name": category,
+			"title":         "Laundry kiloan",
+			"amount":        amount,
+			"date":          time.Now().UTC().Format(time.RFC3339),
+		}
+	}
+	var result struct {
+		Succeeded int `json:"succeeded"`
+		Failed    int `json:"failed"`
+		Items     []struct {
+			Success bool   `json:"success"`
+			Error   string `json:"error"`
+		} `json:"items"`
+	}
+	c.mustDo(http.MethodPost, "/api/transactions/bulk/create", map[string]interface{}{
+		"items": []interface{}{item("Laundry", 15000), item("Gak Ada", 1000), item("Laundry", 20000)},
+	}, http.StatusOK, &result)
+	if result.Succeeded != 2 || result.Failed != 1 || result.Items[1].Success || result.Items[1].Error == "" {
+		t.Fatalf("bulk create = %+v, want item 1 gagal, sisanya sukses", result)
+	}
+	if got := c.personalWallet().Balance; got != -35000 {
+		t.Errorf("saldo = %v, want -35000", got)
+	}
+}
+
+// Merge payee: transaksi source pindah ke target, nama source jadi alias target.
+func TestMergePayees(t *testing.T) {
+	srv := newTestServer(t)
+	c := signUp(t, srv, "nanda")
+	c.createCategory("Belanja", "EXPENSE")
+	wallet := c.personalWallet()
+
+	createWithPayee := func(payee string, amount float64) string {
+		t.Helper()
+		var transaction struct {
+			Payee struct {
+				ID string `json:"id"`
+			} `json:"payee"`
+		}
+		c.mustDo(http.MethodPost, "/api/transactions/", map[string]interface{}{
+			"wallet_id":     wallet.ID,
+			"category_name": "Belanja",
+			"payee_name":    payee,
+			"title":         "Belanja " + payee,
+			"amount":        amount,
+			"date":          time.Now().UTC().Format(time.RFC3339),
+		}, http.StatusOK, &transaction)
+		return transaction.Payee.ID
+	}
+	target := createWithPayee("Indomaret", 10000)
+	source := createWithPayee("Idm Kebayoran", 5000)
+	if target == "" || source == "" || target == source {
+		t.Fatalf("payee target %q, source %q, want dua payee beda", target, source)
+	}
+
+	c.mustDo(http.MethodPost, "/api/payees/merge", map[string]interface{}{
+		"target_id":  target,
+		"source_ids": []string{source},
+	}, http.StatusOK, nil)
+
+	// Nama source sekarang alias target
+	if got := createWithPayee("Idm Kebayoran", 2500); got != target {
+		t.Errorf("payee_name source setelah merge = %s, want target %s", got, target)
+	}
+	var history struct {
+		TransactionCount int     `json:"transaction_count"`
+		TotalAmount      float64 `json:"total_amount"`
+	}
+	c.mustDo(http.MethodGet, "/api/payees/"+target+"/history", nil, http.StatusOK, &history)
+	if history.TransactionCount != 3 || history.TotalAmount != -17500 {
+		t.Errorf("riwayat target = %+v, want 3 transaksi, total -17500", history)
+	}
+	c.mustFail(http.MethodGet, "/api/payees/"+source+"/history", nil, nil, http.StatusNotFound)
+}
+
+// Error validasi dikirim per field (termasuk item di dalam list), pesannya ngikut Accept-Language.
+func TestValidationFieldErrors(t *testing.T) {
+	srv := newTestServer(t)
+	c := signUp(t, srv, "oki")
+	wallet := c.personalWallet()
+
+	body := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"wallet_id": wallet.ID, "title": "Ok", "amount": 1000, "date": time.Now().UTC().Format(time.RFC3339)},
+			map[string]interface{}{"wallet_id": "bukan-uuid", "amount": -5, "date": time.Now().UTC().Format(time.RFC3339)},
+		},
+	}
+	code, details := c.mustFail(http.MethodPost, "/api/transactions/bulk/create", body, map[string]string{"Accept-Language": "en"}, http.StatusUnprocessableEntity)
+	if code != "validation_failed" {
+		t.Fatalf("code %q, want validation_failed", code)
+	}
+	var fields []struct {
+		Field   string `json:"field"`
+		Rule    string `json:"rule"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(details, &fields); err != nil {
+		t.Fatalf("details bukan daftar field: %s", details)
+	}
+	got := make(map[string]string)
+	for _, f := range fields {
+		got[f.Field] = f.Rule
+		if f.Field == "items[1].title" && !strings.Contains(f.Message, "is required") {
+			t.Errorf("pesan %s = %q, want bahasa Inggris", f.Field, f.Message)
+		}
+	}
+	want := map[string]string{"items[1].wallet_id": "uuid", "items[1].title": "required", "items[1].amount": "gt"}
+	for field, rule := range want {
+		if got[field] != rule {
+			t.Errorf("field %s: rule %q, want %q (details: %s)", field, got[field], rule, details)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("details = %s, want cuma field item 1 yang salah", details)
+	}
+}
@@ -7,14 +7,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func GroupRoutes(r *gin.RouterGroup, controller *controllers.GroupController) {
+func GroupRoutes(r *gin.RouterGroup, controller *controllers.GroupController, auth gin.HandlerFunc) {
 	groups := r.Group("/groups")
-	groups.Use(middlewares.AuthMiddleware()) // Middleware dipasang di sini
+	groups.Use(auth, middlewares.RequireJWT()) // Middleware dipasang di sini
 	{
 		groups.GET("/", controller.GetAllGroups)
 		groups.POST("/", controller.CreateGroup)
 		groups.GET("/:id", controller.GetGroupByID)
-		// groups.PATCH("/:id/update", controller.UpdateGroup)
-		groups.PATCH("/:id/remove-user", controller.RemoveUserFromGroup)
+		groups.PATCH("/:id/update", middlewares.RequireIfMatch(), controller.UpdateGroup)
+		groups.PATCH("/:id/remove-user", middlewares.RequireIfMatch(), controller.RemoveUserFromGroup)
 	}
 }
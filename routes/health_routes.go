@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"cashflow_gin/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthRoutes dipasang di root (bukan /api) & tanpa auth, dipakai probe orchestrator / load balancer.
+func HealthRoutes(r gin.IRouter, controller *controllers.HealthController) {
+	r.GET("/healthz", controller.Liveness)
+	r.GET("/readyz", controller.Readiness)
+}
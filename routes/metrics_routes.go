@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"cashflow_gin/metrics"
+	"cashflow_gin/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsRoutes pasang /metrics (format Prometheus). Bisa di server utama atau di listener terpisah (METRICS_ADDR).
+func MetricsRoutes(r gin.IRouter, token string) {
+	r.GET("/metrics", middlewares.MetricsAuth(token), gin.WrapH(metrics.Default.Handler()))
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"cashflow_gin/controllers"
+	"cashflow_gin/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NotificationRoutes(r *gin.RouterGroup, controller *controllers.NotificationController, auth gin.HandlerFunc) {
+	notifications := r.Group("/notifications")
+	notifications.Use(auth, middlewares.RequireJWT())
+	{
+		notifications.GET("/", controller.GetMyNotifications)
+		notifications.POST("/read-all", controller.MarkAllRead)
+		notifications.PATCH("/:id/read", controller.MarkRead)
+		notifications.GET("/preferences", controller.GetPreferences)
+		notifications.PUT("/preferences", controller.UpdatePreferences)
+	}
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"cashflow_gin/controllers"
+	"cashflow_gin/middlewares"
+	"cashflow_gin/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func PayeeRoutes(r *gin.RouterGroup, controller *controllers.PayeeController, auth gin.HandlerFunc) {
+	read := middlewares.RequireScope(models.ScopeTransactionsRead)
+	write := middlewares.RequireScope(models.ScopeTransactionsWrite)
+
+	payees := r.Group("/payees")
+	payees.Use(auth)
+	{
+		payees.GET("/", read, controller.Autocomplete)
+		payees.POST("/", write, controller.CreatePayee)
+		payees.POST("/merge", write, controller.MergePayees)
+		payees.PUT("/:id", write, controller.UpdatePayee)
+		payees.DELETE("/:id", write, controller.DeletePayee)
+		payees.GET("/:id/history", read, controller.GetPayeeHistory)
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"cashflow_gin/controllers"
+	"cashflow_gin/middlewares"
+	"cashflow_gin/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RealtimeRoutes(r *gin.RouterGroup, controller *controllers.RealtimeController, auth gin.HandlerFunc) {
+	realtime := r.Group("/events")
+	// EventSource di browser gak bisa set header, jadi token boleh dari query ?access_token=
+	realtime.Use(middlewares.TokenFromQuery("access_token"), auth)
+	{
+		realtime.GET("/stream", middlewares.RequireScope(models.ScopeTransactionsRead), controller.Stream)
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"cashflow_gin/controllers"
+	"cashflow_gin/middlewares"
+	"cashflow_gin/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ReportRoutes(r *gin.RouterGroup, controller *controllers.ReportController, auth gin.HandlerFunc) {
+	reports := r.Group("/reports")
+	reports.Use(auth, middlewares.RequireScope(models.ScopeReportsRead))
+	{
+		reports.GET("/categories", controller.CategoryReport)
+	}
+}
@@ -1,34 +1,97 @@
 package routes
 
 import (
+	"cashflow_gin/config"
 	"cashflow_gin/controllers"
+	"cashflow_gin/events"
+	"cashflow_gin/middlewares"
 	"cashflow_gin/repository"
 	"cashflow_gin/services"
+	"context"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(db *gorm.DB, r *gin.Engine) {
+// Background = komponen yang masih jalan di belakang request, perlu dimatiin berurutan pas shutdown.
+type Background struct {
+	health        services.HealthService
+	realtime      *services.RealtimeHub
+	webhooks      *services.WebhookDispatcher
+	notifications services.NotificationService
+	stopJanitor   func()
+	stopTasks     []func()
+}
+
+// BeginShutdown dipanggil begitu SIGTERM masuk, sebelum nunggu request selesai:
+// /readyz jadi 503 & stream SSE ditutup (kalau gak, Shutdown nungguin SSE sampai timeout).
+func (b *Background) BeginShutdown() {
+	b.health.MarkShuttingDown()
+	b.realtime.Close()
+}
+
+// Stop dipanggil setelah request selesai & sebelum DB ditutup: nunggu notifikasi yang masih dikirim di
+// background, lalu ngosongin antrian & retry webhook (sampai ctx habis).
+func (b *Background) Stop(ctx context.Context) {
+	b.stopJanitor()
+	for _, stop := range b.stopTasks {
+		stop()
+	}
+	b.notifications.Close()
+	b.webhooks.Stop(ctx)
+}
+
+// SetupRoutes masang semua route di r. repos = implementasi repository yang dipilih pas startup
+// (GORM atau in-memory); db cuma dipakai health check & boleh nil kalau mode in-memory.
+func SetupRoutes(db *gorm.DB, repos repository.Repositories, r *gin.Engine, cfg *config.Config) *Background {
 	// 1. INIT REPOSITORIES (Layer Paling Bawah)
-	// Cukup sekali init per repo!
-	userRepo := repository.NewUserRepository(db)
-	transRepo := repository.NewTransactionRepository(db)
-	catRepo := repository.NewCategoryRepository(db) // <--- Dipake bareng-bareng
-	authRepo := repository.NewAuthRepository(db)
-	groupRepo := repository.NewGroupRepository(db)   // <--- Repo baru untuk Group
-	walletRepo := repository.NewWalletRepository(db) // <--- Repo baru untuk Wallet
+	// Udah di-init sekali di luar (repository.NewRepositories / memory.NewRepositories), tinggal dipakai
+	userRepo := repos.User
+	transRepo := repos.Transaction
+	catRepo := repos.Category // <--- Dipake bareng-bareng
+	authRepo := repos.Auth
+	groupRepo := repos.Group
+	walletRepo := repos.Wallet
+	apiKeyRepo := repos.APIKey
+	webhookRepo := repos.Webhook
+	notificationRepo := repos.Notification
+	statementImportRepo := repos.StatementImport
+	categoryRuleRepo := repos.CategoryRule
+	idempotencyRepo := repos.Idempotency
+	reportRepo := repos.Report
+	payeeRepo := repos.Payee
+
+	// Event bus in-process: TransactionService publish, webhook (dan subscriber lain) dengerin
+	bus := events.NewBus()
+	webhookDispatcher := services.NewWebhookDispatcher(webhookRepo, groupRepo, 4)
+	bus.Subscribe(webhookDispatcher.HandleEvent)
+	realtimeHub := services.NewRealtimeHub(walletRepo, groupRepo)
+	bus.Subscribe(realtimeHub.HandleEvent)
 
 	// 2. INIT SERVICES (Layer Tengah)
-	userService := services.NewUserService(userRepo)
-	authService := services.NewAuthService(authRepo)
+	// Notifikasi: channel bisa ditambah di sini (email masih pakai LogMailer sampai ada SMTP)
+	notificationService := services.NewNotificationService(notificationRepo, userRepo,
+		services.NewInAppChannel(notificationRepo),
+		services.NewEmailChannel(services.LogMailer{}),
+	)
+	userService := services.NewUserService(userRepo, catRepo)
+	authService := services.NewAuthService(authRepo, cfg.Auth)
 	catService := services.NewCategoryService(catRepo)
-	groupService := services.NewGroupService(groupRepo)
+	groupService := services.NewGroupService(groupRepo, notificationService)
+	ruleEngine := services.NewCategoryRuleEngine(categoryRuleRepo) // Auto-kategori, dipakai create transaksi & import
+	payeeService := services.NewPayeeService(payeeRepo, catRepo, groupRepo)
 
 	// Perhatikan ini: TransactionService butuh catRepo & userRepo juga
 	// Karena kita udah init di atas, tinggal masukin variabelnya.
-	transService := services.NewTransactionService(transRepo, catRepo, userRepo, groupRepo, walletRepo)
+	transService := services.NewTransactionService(transRepo, catRepo, userRepo, groupRepo, walletRepo, bus, notificationService, ruleEngine, payeeService)
 	walletService := services.NewWalletService(walletRepo, groupRepo) // Service untuk Wallet, kalau nanti butuh logic khusus selain repo langsung bisa ditambahin di sini
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo)
+	webhookService := services.NewWebhookService(webhookRepo, groupRepo, webhookDispatcher)
+	categoryRuleService := services.NewCategoryRuleService(categoryRuleRepo, catRepo, transRepo, transService, ruleEngine)
+	reportService := services.NewReportService(reportRepo, walletRepo, groupRepo)
+	healthService := services.NewHealthService(db)
+	statementImportService := services.NewStatementImportService(statementImportRepo, walletRepo, groupRepo, catRepo, transService, ruleEngine, payeeService)
 
 	// 3. INIT CONTROLLERS (Layer Atas)
 	userController := controllers.NewUserController(userService)
@@ -37,16 +100,62 @@ func SetupRoutes(db *gorm.DB, r *gin.Engine) {
 	transController := controllers.NewTransactionController(transService)
 	groupController := controllers.NewGroupController(groupService)
 	walletController := controllers.NewWalletController(walletService) // Controller untuk Wallet
+	apiKeyController := controllers.NewAPIKeyController(apiKeyService)
+	webhookController := controllers.NewWebhookController(webhookService)
+	realtimeController := controllers.NewRealtimeController(realtimeHub)
+	notificationController := controllers.NewNotificationController(notificationService)
+	statementImportController := controllers.NewStatementImportController(statementImportService)
+	categoryRuleController := controllers.NewCategoryRuleController(categoryRuleService)
+	reportController := controllers.NewReportController(reportService)
+	payeeController := controllers.NewPayeeController(payeeService)
+	healthController := controllers.NewHealthController(healthService)
+
+	// Rate limiter (Redis kalau REDIS_ADDR diisi, kalau gak in-memory)
+	limiter := middlewares.NewRateLimitStore(cfg.Redis.Addr, cfg.Redis.Password)
+
+	// Auth middleware (JWT atau API key), dipakai bareng semua route yang butuh login
+	authMiddleware := middlewares.AuthMiddleware(apiKeyService, cfg.Auth.JWTSecret)
+
+	// Idempotency-Key buat POST yang mindahin duit (create transaksi, bulk, import). TTL & lease dari IDEMPOTENCY_TTL / IDEMPOTENCY_LEASE
+	idempotent := middlewares.Idempotency(idempotencyRepo, cfg.Idempotency.TTL.Std(), cfg.Idempotency.Lease.Std())
+	stopJanitor := middlewares.StartIdempotencyJanitor(idempotencyRepo, time.Hour)
+
+	// Cek saldo wallet vs total transaksi, selisihnya masuk metric cashflow_balance_reconciliation_*
+	var stopTasks []func()
+	if cfg.Metrics.ReconcileInterval > 0 {
+		stopTasks = append(stopTasks, services.StartBalanceReconciler(walletRepo, cfg.Metrics.ReconcileInterval.Std()))
+	}
 
 	// 4. ROUTING GROUP (Panggil file-file routes yang udah dipisah)
+	HealthRoutes(r, healthController) // /healthz & /readyz di luar /api, tanpa auth
+	if cfg.Metrics.Enabled && cfg.Metrics.Addr == "" {
+		MetricsRoutes(r, cfg.Metrics.Token) // kalau METRICS_ADDR diisi, /metrics dipasang di listener sendiri (main.go)
+	}
 	api := r.Group("/api")
 	{
 		// Lempar Controller yang udah jadi ke masing-masing file route
-		AuthRoutes(api, authController)
-		UserRoutes(api, userController)
-		CategoryRoutes(api, catController)
-		TransactionRoutes(api, transController)
-		GroupRoutes(api, groupController)
-		WalletRoutes(api, walletController)
+		AuthRoutes(api, authController, limiter, authMiddleware)
+		UserRoutes(api, userController, authMiddleware)
+		CategoryRoutes(api, catController, authMiddleware)
+		TransactionRoutes(api, transController, authMiddleware, idempotent)
+		GroupRoutes(api, groupController, authMiddleware)
+		WalletRoutes(api, walletController, authMiddleware)
+		APIKeyRoutes(api, apiKeyController, authMiddleware)
+		WebhookRoutes(api, webhookController, authMiddleware)
+		RealtimeRoutes(api, realtimeController, authMiddleware)
+		NotificationRoutes(api, notificationController, authMiddleware)
+		StatementImportRoutes(api, statementImportController, authMiddleware, idempotent)
+		CategoryRuleRoutes(api, categoryRuleController, authMiddleware)
+		ReportRoutes(api, reportController, authMiddleware)
+		PayeeRoutes(api, payeeController, authMiddleware)
+	}
+
+	return &Background{
+		health:        healthService,
+		realtime:      realtimeHub,
+		webhooks:      webhookDispatcher,
+		notifications: notificationService,
+		stopJanitor:   stopJanitor,
+		stopTasks:     stopTasks,
 	}
 }
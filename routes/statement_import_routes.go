@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"cashflow_gin/controllers"
+	"cashflow_gin/middlewares"
+	"cashflow_gin/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func StatementImportRoutes(r *gin.RouterGroup, controller *controllers.StatementImportController, auth, idempotent gin.HandlerFunc) {
+	read := middlewares.RequireScope(models.ScopeTransactionsRead)
+	write := middlewares.RequireScope(models.ScopeTransactionsWrite)
+
+	imports := r.Group("/imports")
+	imports.Use(auth)
+	{
+		imports.POST("/", write, idempotent, controller.UploadStatement)
+		imports.GET("/", read, controller.GetMyImports)
+		imports.GET("/:id", read, controller.GetImport)
+		imports.PATCH("/:id/rows", write, controller.ReviewRows)
+		imports.POST("/:id/commit", write, idempotent, controller.CommitImport)
+	}
+}
@@ -3,18 +3,31 @@ package routes
 import (
 	"cashflow_gin/controllers"
 	"cashflow_gin/middlewares"
+	"cashflow_gin/models"
 
 	"github.com/gin-gonic/gin"
 )
 
-func TransactionRoutes(r *gin.RouterGroup, controller *controllers.TransactionController) {
+func TransactionRoutes(r *gin.RouterGroup, controller *controllers.TransactionController, auth, idempotent gin.HandlerFunc) {
+	read := middlewares.RequireScope(models.ScopeTransactionsRead)
+	write := middlewares.RequireScope(models.ScopeTransactionsWrite)
+
 	transactions := r.Group("/transactions")
-	transactions.Use(middlewares.AuthMiddleware()) // Middleware dipasang di sini
+	transactions.Use(auth) // Middleware dipasang di sini
 	{
-		transactions.POST("/", controller.Create)
-		transactions.GET("/", controller.FindAll)
-		transactions.GET("/:id/detail", controller.GetTransactionByID)
-		transactions.PATCH("/:id/update", controller.UpdateTransaction)
-		transactions.PATCH("/:id/wallet/:walletid/soft-delete", controller.SoftDeleteTransaction)
+		// idempotent: retry dengan Idempotency-Key yang sama gak bikin transaksi dobel
+		transactions.POST("/", write, idempotent, controller.Create)
+		transactions.GET("/", read, controller.FindAll)
+		transactions.GET("/:id/detail", read, controller.GetTransactionByID)
+		transactions.PATCH("/:id/update", write, middlewares.RequireIfMatch(), controller.UpdateTransaction)
+		transactions.PATCH("/:id/wallet/:walletid/soft-delete", write, middlewares.RequireIfMatch(), controller.SoftDeleteTransaction)
+
+		bulk := transactions.Group("/bulk", write)
+		{
+			bulk.POST("/create", idempotent, controller.BulkCreate)
+			bulk.POST("/recategorize", controller.BulkRecategorize)
+			bulk.POST("/move", idempotent, controller.BulkMove)
+			bulk.POST("/delete", controller.BulkDelete)
+		}
 	}
 }
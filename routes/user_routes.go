@@ -7,11 +7,15 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func UserRoutes(r *gin.RouterGroup, controller *controllers.UserController) {
+func UserRoutes(r *gin.RouterGroup, controller *controllers.UserController, auth gin.HandlerFunc) {
 	users := r.Group("/users")
-	users.Use(middlewares.AuthMiddleware())
+	users.Use(auth, middlewares.RequireJWT())
 	{
 		users.GET("/", controller.FindAllUser)
 		users.GET("/me", controller.GetMyProfile)
+		users.PATCH("/me", controller.UpdateMyProfile)
+		users.DELETE("/me", controller.DeleteMyAccount)
+		users.POST("/me/password", controller.ChangePassword)
+		users.GET("/me/export", controller.ExportMyData)
 	}
 }
@@ -3,15 +3,18 @@ package routes
 import (
 	"cashflow_gin/controllers"
 	"cashflow_gin/middlewares"
+	"cashflow_gin/models"
 
 	"github.com/gin-gonic/gin"
 )
 
-func WalletRoutes(r *gin.RouterGroup, controller *controllers.WalletController) {
+func WalletRoutes(r *gin.RouterGroup, controller *controllers.WalletController, auth gin.HandlerFunc) {
+	read := middlewares.RequireScope(models.ScopeWalletsRead)
+
 	wallets := r.Group("/wallets")
-	wallets.Use(middlewares.AuthMiddleware()) // Middleware dipasang di sini
+	wallets.Use(auth) // Middleware dipasang di sini
 	{
-		wallets.GET("/", controller.GetAllWallets)
-		wallets.GET("/:id/detail", controller.GetWalletByID)
+		wallets.GET("/", read, controller.GetAllWallets)
+		wallets.GET("/:id/detail", read, controller.GetWalletByID)
 	}
 }
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"cashflow_gin/controllers"
+	"cashflow_gin/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func WebhookRoutes(r *gin.RouterGroup, controller *controllers.WebhookController, auth gin.HandlerFunc) {
+	webhooks := r.Group("/webhooks")
+	webhooks.Use(auth, middlewares.RequireJWT())
+	{
+		webhooks.GET("/", controller.GetMyWebhooks)
+		webhooks.POST("/", controller.CreateWebhook)
+		webhooks.DELETE("/:id", controller.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", controller.GetDeliveries)
+		webhooks.POST("/:id/test", controller.TestWebhook)
+	}
+}
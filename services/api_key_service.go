@@ -0,0 +1,163 @@
+package services
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"cashflow_gin/tracing"
+	"cashflow_gin/utils"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Format key: cfk_<prefix 8 char>_<secret>. Prefix gak rahasia, dipakai buat identifikasi di list key.
+const (
+	// last_used_at gak di-update tiap request biar gak nulis ke DB terus-terusan
+	apiKeyTouchInterval = time.Minute
+)
+
+type APIKeyService interface {
+	Create(ctx context.Context, userID uuid.UUID, input request.CreateAPIKeyRequest) (*response.CreatedAPIKeyResponse, error)
+	GetMine(ctx context.Context, userID uuid.UUID) ([]response.APIKeyResponse, error)
+	Revoke(ctx context.Context, userID, keyID uuid.UUID) error
+
+	// Dipanggil AuthMiddleware
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (*models.APIKey, error)
+}
+
+type apiKeyService struct {
+	repo repository.APIKeyRepository
+}
+
+func NewAPIKeyService(r repository.APIKeyRepository) APIKeyService {
+	return &apiKeyService{repo: r}
+}
+
+func (s *apiKeyService) Create(ctx context.Context, userID uuid.UUID, input request.CreateAPIKeyRequest) (*response.CreatedAPIKeyResponse, error) {
+	ctx, span := tracing.Start(ctx, "ApiKeyService.Create")
+	defer span.End()
+
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, apperror.Validation("expires_at_in_past", "expires_at harus di masa depan")
+	}
+
+	// Buang scope dobel tapi tetap jaga urutan
+	seen := make(map[string]bool)
+	var scopes []string
+	for _, scope := range input.Scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	prefix, err := utils.RandomToken(5)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := utils.RandomToken(20)
+	if err != nil {
+		return nil, err
+	}
+	rawKey := models.APIKeyPrefix + prefix + "_" + secret
+
+	key := models.APIKey{
+		UserID:    userID,
+		Name:      input.Name,
+		Prefix:    models.APIKeyPrefix + prefix,
+		KeyHash:   utils.HashToken(rawKey),
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: input.ExpiresAt,
+	}
+	if err := s.repo.Create(ctx, &key); err != nil {
+		return nil, err
+	}
+
+	return &response.CreatedAPIKeyResponse{
+		APIKeyResponse: toAPIKeyResponse(key),
+		Key:            rawKey,
+	}, nil
+}
+
+func (s *apiKeyService) GetMine(ctx context.Context, userID uuid.UUID) ([]response.APIKeyResponse, error) {
+	ctx, span := tracing.Start(ctx, "ApiKeyService.GetMine")
+	defer span.End()
+
+	keys, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []response.APIKeyResponse{}
+	for _, k := range keys {
+		res = append(res, toAPIKeyResponse(k))
+	}
+	return res, nil
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, userID, keyID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "ApiKeyService.Revoke")
+	defer span.End()
+
+	deleted, err := s.repo.Delete(ctx, userID, keyID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperror.NotFound("api_key_not_found", "api key not found")
+	}
+	return nil
+}
+
+var ErrInvalidAPIKey = apperror.Unauthorized("invalid_api_key", "invalid api key")
+
+func (s *apiKeyService) AuthenticateAPIKey(ctx context.Context, rawKey string) (*models.APIKey, error) {
+	ctx, span := tracing.Start(ctx, "ApiKeyService.AuthenticateAPIKey")
+	defer span.End()
+
+	if !strings.HasPrefix(rawKey, models.APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.repo.FindByHash(ctx, utils.HashToken(rawKey))
+	if errors.Is(err, apperror.ErrNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if key.User.ID == uuid.Nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if key.ExpiresAt != nil && now.After(*key.ExpiresAt) {
+		return nil, apperror.Unauthorized("api_key_expired", "api key expired")
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		// Gagal update last_used_at bukan alasan buat nolak request
+		_ = s.repo.TouchLastUsed(ctx, key.ID, now)
+		key.LastUsedAt = &now
+	}
+
+	return key, nil
+}
+
+func toAPIKeyResponse(k models.APIKey) response.APIKeyResponse {
+	return response.APIKeyResponse{
+		ID:         k.ID.String(),
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.ScopeList(),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
@@ -1,68 +1,236 @@
 package services
 
 import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/config"
 	"cashflow_gin/dto/request"
 	"cashflow_gin/dto/response"
+	"cashflow_gin/metrics"
 	"cashflow_gin/models"
 	"cashflow_gin/repository"
+	"cashflow_gin/tracing"
+	"cashflow_gin/utils"
+	"context"
 	"errors"
 	"fmt"
-	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthService interface {
-	Login(input *request.LoginRequest) (string, error)
-	Register(input request.CreateUserRequest) (*response.UserResponse, error)
+	Login(ctx context.Context, input *request.LoginRequest) (*response.LoginResponse, error)
+	LoginTwoFactor(ctx context.Context, input request.LoginTwoFactorRequest) (*response.LoginResponse, error)
+	Register(ctx context.Context, input request.CreateUserRequest) (*response.UserResponse, error)
+
+	EnrollTwoFactor(ctx context.Context, userID uuid.UUID) (*response.TwoFactorEnrollResponse, error)
+	EnableTwoFactor(ctx context.Context, userID uuid.UUID, input request.TwoFactorCodeRequest) (*response.RecoveryCodesResponse, error)
+	DisableTwoFactor(ctx context.Context, userID uuid.UUID, input request.DisableTwoFactorRequest) error
 }
 
 type authService struct {
-	repo repository.AuthRepository
+	repo           repository.AuthRepository
+	jwtSecret      []byte
+	accessTokenTTL time.Duration
+}
+
+const (
+	maxFailedLoginAttempts = 5
+	accountLockDuration    = 15 * time.Minute
+
+	totpIssuer          = "Cashflow"
+	recoveryCodeCount   = 10
+	challengeTokenTTL   = 5 * time.Minute
+	challengeTokenScope = "2fa_challenge"
+)
+
+var (
+	ErrInvalidCredentials    = apperror.Unauthorized("invalid_credentials", "email atau password salah")
+	ErrInvalidChallenge      = apperror.Unauthorized("invalid_challenge_token", "challenge token tidak valid atau sudah kadaluarsa")
+	ErrInvalidTwoFactorCode  = apperror.Unauthorized("invalid_two_factor_code", "kode 2FA salah")
+	ErrUserAlreadyRegistered = apperror.Conflict("user_already_registered", "email atau username sudah terdaftar")
+	ErrWrongPassword         = apperror.Validation("wrong_password", "password salah")
+)
+
+// accountLockedError = 429 + header Retry-After (diisi middleware ErrorHandler dari RetryAfter).
+func accountLockedError(retryAfter time.Duration) error {
+	return apperror.TooManyRequests("account_locked",
+		fmt.Sprintf("akun dikunci sementara karena terlalu banyak percobaan login, coba lagi dalam %d detik", int(retryAfter.Seconds()))).
+		WithRetryAfter(retryAfter)
 }
 
-func NewAuthService(r repository.AuthRepository) AuthService {
-	return &authService{repo: r}
+func NewAuthService(r repository.AuthRepository, cfg config.AuthConfig) AuthService {
+	return &authService{repo: r, jwtSecret: []byte(cfg.JWTSecret), accessTokenTTL: cfg.AccessTokenTTL.Std()}
 }
 
-func (s *authService) Login(input *request.LoginRequest) (string, error) {
+func (s *authService) Login(ctx context.Context, input *request.LoginRequest) (*response.LoginResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer span.End()
+
 	// 1. Cari user berdasarkan email (panggil Repo)
-	user, err := s.repo.Login(input)
+	user, err := s.repo.Login(ctx, input)
 	if err != nil {
-		return "", errors.New("email atau password salah") // Jangan kasih tau email gak ada (security)
+		metrics.FailedLoginsTotal.Inc("password")
+		return nil, ErrInvalidCredentials // Jangan kasih tau email gak ada (security)
+	}
+
+	// 2. Tolak kalau akun lagi dikunci (sebelum bcrypt, biar gak buang CPU)
+	if err := checkAccountLock(user); err != nil {
+		metrics.FailedLoginsTotal.Inc("locked")
+		return nil, err
 	}
 
-	// 2. Bandingkan Password
+	// 3. Bandingkan Password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password))
 	if err != nil {
-		return "", errors.New("email atau password salah")
+		metrics.FailedLoginsTotal.Inc("password")
+		if lockErr := s.registerFailedLogin(ctx, user); lockErr != nil {
+			return nil, lockErr
+		}
+		return nil, ErrInvalidCredentials
 	}
 
-	// 3. Generate JWT Token
+	// 4. Kalau 2FA aktif, jangan kasih access token dulu. Kasih challenge token yang umurnya pendek
+	if user.TwoFactorEnabled {
+		challenge, err := s.generateChallengeToken(user)
+		if err != nil {
+			return nil, err
+		}
+		return &response.LoginResponse{TwoFactorRequired: true, ChallengeToken: challenge}, nil
+	}
+
+	return s.completeLogin(ctx, user)
+}
+
+func (s *authService) LoginTwoFactor(ctx context.Context, input request.LoginTwoFactorRequest) (*response.LoginResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.LoginTwoFactor")
+	defer span.End()
+
+	userID, err := s.parseChallengeToken(input.ChallengeToken)
+	if err != nil {
+		return nil, ErrInvalidChallenge
+	}
+
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil || !user.TwoFactorEnabled {
+		return nil, ErrInvalidChallenge
+	}
+
+	if err := checkAccountLock(user); err != nil {
+		metrics.FailedLoginsTotal.Inc("locked")
+		return nil, err
+	}
+
+	code := strings.TrimSpace(input.Code)
+	valid := utils.ValidateTOTP(user.TwoFactorSecret, code, time.Now())
+	if !valid {
+		// Bukan kode TOTP? Coba sebagai recovery code
+		valid, err = s.repo.UseRecoveryCode(ctx, user.ID, utils.HashToken(normalizeRecoveryCode(code)))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !valid {
+		// Gagal 2FA dihitung sama kayak gagal password biar 6 digit gak bisa di-bruteforce
+		metrics.FailedLoginsTotal.Inc("two_factor")
+		if lockErr := s.registerFailedLogin(ctx, user); lockErr != nil {
+			return nil, lockErr
+		}
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	return s.completeLogin(ctx, user)
+}
+
+// completeLogin reset counter gagal login & generate access token.
+func (s *authService) completeLogin(ctx context.Context, user *models.User) (*response.LoginResponse, error) {
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := s.repo.ResetFailedLogin(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	token, err := s.generateAccessToken(user)
+	if err != nil {
+		return nil, err
+	}
+	return &response.LoginResponse{Token: token}, nil
+}
+
+func checkAccountLock(user *models.User) error {
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return accountLockedError(time.Until(*user.LockedUntil))
+	}
+	return nil
+}
+
+// registerFailedLogin return error account_locked kalau percobaan gagal ini bikin akun kekunci.
+func (s *authService) registerFailedLogin(ctx context.Context, user *models.User) error {
+	attempts, err := s.repo.IncrementFailedLogin(ctx, user.ID)
+	if err != nil || attempts < maxFailedLoginAttempts {
+		return nil
+	}
+	if err := s.repo.LockAccount(ctx, user.ID, time.Now().Add(accountLockDuration)); err != nil {
+		return nil
+	}
+	return accountLockedError(accountLockDuration)
+}
+
+func (s *authService) generateAccessToken(user *models.User) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id":   user.ID.String(),
 		"user_role": user.UserRole,
-		"exp":       time.Now().Add(time.Hour * 24).Unix(), // Token berlaku 24 jam
+		"exp":       time.Now().Add(s.accessTokenTTL).Unix(), // Default 24 jam, atur via JWT_ACCESS_TOKEN_TTL
 	})
 
-	tokenString, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
-	return tokenString, err
+	return token.SignedString(s.jwtSecret)
 }
 
-func (s *authService) Register(input request.CreateUserRequest) (*response.UserResponse, error) {
+// generateChallengeToken = JWT pendek dengan claim "scope" supaya AuthMiddleware nolak token ini
+// kalau dipakai buat akses endpoint biasa.
+func (s *authService) generateChallengeToken(user *models.User) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": user.ID.String(),
+		"scope":   challengeTokenScope,
+		"exp":     time.Now().Add(challengeTokenTTL).Unix(),
+	})
+
+	return token.SignedString(s.jwtSecret)
+}
+
+func (s *authService) parseChallengeToken(tokenString string) (uuid.UUID, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return s.jwtSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["scope"] != challengeTokenScope {
+		return uuid.Nil, errors.New("invalid challenge token")
+	}
+	return uuid.Parse(fmt.Sprintf("%v", claims["user_id"]))
+}
+
+func (s *authService) Register(ctx context.Context, input request.CreateUserRequest) (*response.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Register")
+	defer span.End()
+
 	// cek apakah email atau username udah ada di db?
-	_, err := s.repo.FindByEmail(input.Email)
+	_, err := s.repo.FindByEmail(ctx, input.Email)
 	// kalo udah ada kan err = nil, kembalikan error
 	if err == nil {
-		return nil, errors.New("email atau username sudah terdaftar")
+		return nil, ErrUserAlreadyRegistered
 	}
 
 	// kalo err != nil / belum ada
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, errors.New("error hashing password")
+		return nil, fmt.Errorf("error hashing password: %w", err)
 	}
 
 	defaultRole := models.RoleUser
@@ -82,7 +250,7 @@ func (s *authService) Register(input request.CreateUserRequest) (*response.UserR
 		Currency: "IDR",
 	}
 
-	err = s.repo.CreateUserWithWallet(&user, wallet)
+	err = s.repo.CreateUserWithWallet(ctx, &user, wallet)
 	if err != nil {
 		return nil, err
 	}
@@ -103,3 +271,91 @@ func (s *authService) Register(input request.CreateUserRequest) (*response.UserR
 
 	return res, nil
 }
+
+func (s *authService) EnrollTwoFactor(ctx context.Context, userID uuid.UUID) (*response.TwoFactorEnrollResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.EnrollTwoFactor")
+	defer span.End()
+
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.Replace(err, ErrUserNotFound)
+	}
+	if user.TwoFactorEnabled {
+		return nil, apperror.Conflict("two_factor_already_enabled", "2FA sudah aktif, nonaktifkan dulu untuk enroll ulang")
+	}
+
+	// Secret disimpan dulu, 2FA belum aktif sampai user verifikasi kode pertama
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveTwoFactorSecret(ctx, user.ID, secret); err != nil {
+		return nil, err
+	}
+
+	return &response.TwoFactorEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+func (s *authService) EnableTwoFactor(ctx context.Context, userID uuid.UUID, input request.TwoFactorCodeRequest) (*response.RecoveryCodesResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.EnableTwoFactor")
+	defer span.End()
+
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.Replace(err, ErrUserNotFound)
+	}
+	if user.TwoFactorEnabled {
+		return nil, apperror.Conflict("two_factor_already_enabled", "2FA sudah aktif")
+	}
+	if user.TwoFactorSecret == "" {
+		return nil, apperror.Validation("two_factor_not_enrolled", "belum enroll 2FA, panggil endpoint enroll dulu")
+	}
+	if !utils.ValidateTOTP(user.TwoFactorSecret, input.Code, time.Now()) {
+		return nil, apperror.Validation("invalid_two_factor_code", "kode 2FA salah")
+	}
+
+	// Generate recovery code: plaintext cuma dikirim sekali, yang disimpan hash-nya
+	plainCodes := make([]string, 0, recoveryCodeCount)
+	codes := make([]models.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := utils.RandomToken(5)
+		if err != nil {
+			return nil, err
+		}
+		code := raw[:4] + "-" + raw[4:]
+		plainCodes = append(plainCodes, code)
+		codes = append(codes, models.RecoveryCode{CodeHash: utils.HashToken(normalizeRecoveryCode(code))})
+	}
+
+	if err := s.repo.EnableTwoFactor(ctx, user.ID, codes); err != nil {
+		return nil, err
+	}
+
+	return &response.RecoveryCodesResponse{RecoveryCodes: plainCodes}, nil
+}
+
+func (s *authService) DisableTwoFactor(ctx context.Context, userID uuid.UUID, input request.DisableTwoFactorRequest) error {
+	ctx, span := tracing.Start(ctx, "AuthService.DisableTwoFactor")
+	defer span.End()
+
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return apperror.Replace(err, ErrUserNotFound)
+	}
+	if !user.TwoFactorEnabled {
+		return apperror.Validation("two_factor_not_enabled", "2FA belum aktif")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
+		return ErrWrongPassword
+	}
+
+	return s.repo.DisableTwoFactor(ctx, user.ID)
+}
+
+// normalizeRecoveryCode biar user boleh ketik pakai/tanpa "-" dan huruf besar/kecil.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
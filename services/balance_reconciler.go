@@ -0,0 +1,52 @@
+package services
+
+import (
+	"cashflow_gin/logging"
+	"cashflow_gin/metrics"
+	"cashflow_gin/repository"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ReconcileBalances cocokin saldo semua wallet dengan total transaksinya. Selisih cuma dilaporin
+// (log + metric), gak dibenerin otomatis: saldo yang beda berarti ada bug yang perlu dicek manual.
+func ReconcileBalances(ctx context.Context, walletRepo repository.WalletRepository) (int, error) {
+	mismatches, err := walletRepo.FindBalanceMismatches(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	logger := logging.FromContext(ctx)
+	for _, m := range mismatches {
+		logger.WarnContext(ctx, "saldo wallet gak cocok dengan total transaksi",
+			"wallet_id", m.WalletID,
+			"balance", m.Balance,
+			"transaction_total", m.TransactionTotal,
+		)
+	}
+	metrics.BalanceReconciliationMismatchesTotal.Add(float64(len(mismatches)))
+	metrics.BalanceReconciliationMismatchedWallets.Set(float64(len(mismatches)))
+	return len(mismatches), nil
+}
+
+// StartBalanceReconciler jalanin ReconcileBalances tiap interval. Return func buat stop.
+func StartBalanceReconciler(walletRepo repository.WalletRepository, interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	ctx := logging.WithLogger(context.Background(), slog.Default().With("component", "balance_reconciler"))
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := ReconcileBalances(ctx, walletRepo); err != nil {
+					logging.FromContext(ctx).Error("gagal reconcile saldo wallet", "error", err)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
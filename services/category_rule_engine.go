@@ -0,0 +1,165 @@
+package services
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"cashflow_gin/tracing"
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// RuleInput = data transaksi yang dicocokin ke rule. Amount boleh bertanda, dibandingin absolut.
+type RuleInput struct {
+	Title       string
+	Description string
+	Amount      float64
+}
+
+// CategoryRuleEngine dipakai bareng TransactionService (create), import mutasi, dan endpoint rule.
+type CategoryRuleEngine struct {
+	repo repository.CategoryRuleRepository
+}
+
+func NewCategoryRuleEngine(r repository.CategoryRuleRepository) *CategoryRuleEngine {
+	return &CategoryRuleEngine{repo: r}
+}
+
+// ActiveRules diambil sekali lalu dipakai berkali-kali (mis. tiap baris import).
+func (e *CategoryRuleEngine) ActiveRules(ctx context.Context, userID uuid.UUID) ([]models.CategoryRule, error) {
+	ctx, span := tracing.Start(ctx, "CategoryRuleEngine.ActiveRules")
+	defer span.End()
+
+	return e.repo.FindByUserID(ctx, userID, true)
+}
+
+// Match balikin rule pertama (urut priority) yang cocok, nil kalau gak ada.
+func (e *CategoryRuleEngine) Match(ctx context.Context, userID uuid.UUID, input RuleInput) (*models.CategoryRule, error) {
+	ctx, span := tracing.Start(ctx, "CategoryRuleEngine.Match")
+	defer span.End()
+
+	rules, err := e.ActiveRules(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return MatchRules(rules, input), nil
+}
+
+func MatchRules(rules []models.CategoryRule, input RuleInput) *models.CategoryRule {
+	for i := range rules {
+		if RuleMatches(rules[i], input) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// RuleMatches: semua kondisi harus cocok (AND). Rule tanpa kondisi gak pernah cocok.
+func RuleMatches(rule models.CategoryRule, input RuleInput) bool {
+	conditions := rule.ConditionList()
+	if len(conditions) == 0 {
+		return false
+	}
+	for _, c := range conditions {
+		if !conditionMatches(c, input) {
+			return false
+		}
+	}
+	return true
+}
+
+func conditionMatches(c models.RuleCondition, input RuleInput) bool {
+	if c.Field == models.RuleFieldAmount {
+		limit, err := strconv.ParseFloat(c.Value, 64)
+		if err != nil {
+			return false
+		}
+		amount := math.Abs(input.Amount)
+		switch c.Operator {
+		case models.RuleOpLT:
+			return amount < limit
+		case models.RuleOpLTE:
+			return amount <= limit
+		case models.RuleOpGT:
+			return amount > limit
+		case models.RuleOpGTE:
+			return amount >= limit
+		case models.RuleOpEquals:
+			return amount == limit
+		}
+		return false
+	}
+
+	var text string
+	switch c.Field {
+	case models.RuleFieldTitle:
+		text = input.Title
+	case models.RuleFieldDescription:
+		text = input.Description
+	default:
+		return false
+	}
+
+	// Pencocokan teks gak peduli huruf besar/kecil
+	switch c.Operator {
+	case models.RuleOpContains:
+		return strings.Contains(strings.ToLower(text), strings.ToLower(c.Value))
+	case models.RuleOpEquals:
+		return strings.EqualFold(strings.TrimSpace(text), strings.TrimSpace(c.Value))
+	case models.RuleOpStartsWith:
+		return strings.HasPrefix(strings.ToLower(text), strings.ToLower(c.Value))
+	case models.RuleOpRegex:
+		re, err := regexp.Compile("(?i)" + c.Value)
+		return err == nil && re.MatchString(text)
+	}
+	return false
+}
+
+// validateRuleCondition dipanggil pas rule dibuat/diubah biar rule rusak gak sampai ke DB.
+func validateRuleCondition(c models.RuleCondition) error {
+	switch c.Field {
+	case models.RuleFieldAmount:
+		switch c.Operator {
+		case models.RuleOpLT, models.RuleOpLTE, models.RuleOpGT, models.RuleOpGTE, models.RuleOpEquals:
+		default:
+			return invalidCondition("operator %s tidak bisa dipakai untuk amount (pilihan: lt, lte, gt, gte, equals)", c.Operator)
+		}
+		if _, err := strconv.ParseFloat(c.Value, 64); err != nil {
+			return invalidCondition("value amount harus angka: %s", c.Value)
+		}
+	case models.RuleFieldTitle, models.RuleFieldDescription:
+		switch c.Operator {
+		case models.RuleOpContains, models.RuleOpEquals, models.RuleOpStartsWith:
+		case models.RuleOpRegex:
+			if _, err := regexp.Compile(c.Value); err != nil {
+				return invalidCondition("regex tidak valid: %v", err)
+			}
+		default:
+			return invalidCondition("operator %s tidak bisa dipakai untuk %s (pilihan: contains, equals, starts_with, regex)", c.Operator, c.Field)
+		}
+		if strings.TrimSpace(c.Value) == "" {
+			return invalidCondition("value kondisi tidak boleh kosong")
+		}
+	default:
+		return invalidCondition("field %s tidak dikenal (pilihan: title, description, amount)", c.Field)
+	}
+	return nil
+}
+
+func invalidCondition(format string, args ...interface{}) error {
+	return apperror.Validation("invalid_rule_condition", fmt.Sprintf(format, args...))
+}
+
+// categoryFitsAmount: kategori EXPENSE cuma buat uang keluar, INCOME buat uang masuk.
+func categoryFitsAmount(category models.Category, amount float64) bool {
+	if category.Type == "EXPENSE" {
+		return amount <= 0
+	}
+	return amount >= 0
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"cashflow_gin/tracing"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var ErrRuleNotFound = apperror.NotFound("rule_not_found", "rule not found")
+
+type CategoryRuleService interface {
+	Create(ctx context.Context, userID uuid.UUID, input request.CategoryRuleRequest) (*response.CategoryRuleResponse, error)
+	GetMine(ctx context.Context, userID uuid.UUID) ([]response.CategoryRuleResponse, error)
+	Update(ctx context.Context, userID, ruleID uuid.UUID, input request.CategoryRuleRequest) (*response.CategoryRuleResponse, error)
+	Delete(ctx context.Context, userID, ruleID uuid.UUID) error
+	Reorder(ctx context.Context, userID uuid.UUID, input request.ReorderCategoryRulesRequest) ([]response.CategoryRuleResponse, error)
+	Test(ctx context.Context, userID uuid.UUID, input request.TestCategoryRuleRequest) (*response.TestCategoryRuleResponse, error)
+	Apply(ctx context.Context, userID uuid.UUID, input request.ApplyCategoryRulesRequest) (*response.ApplyCategoryRulesResponse, error)
+}
+
+type categoryRuleService struct {
+	repo            repository.CategoryRuleRepository
+	categoryRepo    repository.CategoryRepository
+	transactionRepo repository.TransactionRepository
+	transactions    TransactionService
+	engine          *CategoryRuleEngine
+}
+
+func NewCategoryRuleService(
+	r repository.CategoryRuleRepository,
+	cRepo repository.CategoryRepository,
+	tRepo repository.TransactionRepository,
+	transactions TransactionService,
+	engine *CategoryRuleEngine,
+) CategoryRuleService {
+	return &categoryRuleService{
+		repo:            r,
+		categoryRepo:    cRepo,
+		transactionRepo: tRepo,
+		transactions:    transactions,
+		engine:          engine,
+	}
+}
+
+func (s *categoryRuleService) Create(ctx context.Context, userID uuid.UUID, input request.CategoryRuleRequest) (*response.CategoryRuleResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryRuleService.Create")
+	defer span.End()
+
+	rule := models.CategoryRule{UserID: userID, Active: true}
+	if err := s.fillRule(ctx, &rule, input); err != nil {
+		return nil, err
+	}
+
+	// Tanpa priority -> taruh paling bawah
+	if input.Priority == nil {
+		next, err := s.repo.NextPriority(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		rule.Priority = next
+	}
+
+	if err := s.repo.Create(ctx, &rule); err != nil {
+		return nil, err
+	}
+
+	res := toCategoryRuleResponse(rule)
+	return &res, nil
+}
+
+func (s *categoryRuleService) GetMine(ctx context.Context, userID uuid.UUID) ([]response.CategoryRuleResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryRuleService.GetMine")
+	defer span.End()
+
+	rules, err := s.repo.FindByUserID(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []response.CategoryRuleResponse{}
+	for _, rule := range rules {
+		res = append(res, toCategoryRuleResponse(rule))
+	}
+	return res, nil
+}
+
+func (s *categoryRuleService) Update(ctx context.Context, userID, ruleID uuid.UUID, input request.CategoryRuleRequest) (*response.CategoryRuleResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryRuleService.Update")
+	defer span.End()
+
+	rule, err := s.repo.FindByIDAndUserID(ctx, ruleID, userID)
+	if err != nil {
+		return nil, apperror.Replace(err, ErrRuleNotFound)
+	}
+	if err := s.fillRule(ctx, rule, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	res := toCategoryRuleResponse(*rule)
+	return &res, nil
+}
+
+func (s *categoryRuleService) Delete(ctx context.Context, userID, ruleID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "CategoryRuleService.Delete")
+	defer span.End()
+
+	found, err := s.repo.Delete(ctx, userID, ruleID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+// Reorder: urutan rule_ids = urutan evaluasi. Rule yang gak disebut ditaruh di belakang.
+func (s *categoryRuleService) Reorder(ctx context.Context, userID uuid.UUID, input request.ReorderCategoryRulesRequest) ([]response.CategoryRuleResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryRuleService.Reorder")
+	defer span.End()
+
+	rules, err := s.repo.FindByUserID(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make(map[uuid.UUID]bool)
+	for _, rule := range rules {
+		owned[rule.ID] = true
+	}
+
+	var ordered []uuid.UUID
+	listed := make(map[uuid.UUID]bool)
+	for _, idStr := range input.RuleIDs {
+		id, _ := uuid.Parse(idStr)
+		if !owned[id] {
+			return nil, ErrRuleNotFound.WithMessage(fmt.Sprintf("rule %s not found", idStr))
+		}
+		if !listed[id] {
+			listed[id] = true
+			ordered = append(ordered, id)
+		}
+	}
+	for _, rule := range rules {
+		if !listed[rule.ID] {
+			ordered = append(ordered, rule.ID)
+		}
+	}
+
+	if err := s.repo.UpdatePriorities(ctx, userID, ordered); err != nil {
+		return nil, err
+	}
+	return s.GetMine(ctx, userID)
+}
+
+func (s *categoryRuleService) Test(ctx context.Context, userID uuid.UUID, input request.TestCategoryRuleRequest) (*response.TestCategoryRuleResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryRuleService.Test")
+	defer span.End()
+
+	rules, err := s.engine.ActiveRules(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleInput := RuleInput{Title: input.Title, Description: input.Description, Amount: input.Amount}
+	res := response.TestCategoryRuleResponse{MatchingRuleIDs: []string{}}
+	for _, rule := range rules {
+		if !RuleMatches(rule, ruleInput) {
+			continue
+		}
+		res.MatchingRuleIDs = append(res.MatchingRuleIDs, rule.ID.String())
+		if res.Rule == nil {
+			winner := toCategoryRuleResponse(rule)
+			res.Rule = &winner
+			res.Matched = true
+		}
+	}
+	return &res, nil
+}
+
+// Apply jalanin ulang rule ke transaksi yang dipilih (atau semua yang "Uncategorized").
+// Kalau tipe kategori berubah, tanda nominal ikut dibalik dan saldo wallet disesuaikan.
+func (s *categoryRuleService) Apply(ctx context.Context, userID uuid.UUID, input request.ApplyCategoryRulesRequest) (*response.ApplyCategoryRulesResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryRuleService.Apply")
+	defer span.End()
+
+	var (
+		transactions []models.Transaction
+		err          error
+	)
+	if len(input.TransactionIDs) > 0 {
+		var ids []uuid.UUID
+		for _, idStr := range input.TransactionIDs {
+			id, _ := uuid.Parse(idStr)
+			ids = append(ids, id)
+		}
+		transactions, err = s.transactionRepo.FindByIDsAndUserID(ctx, ids, userID)
+	} else {
+		var uncategorized *models.Category
+		uncategorized, err = s.categoryRepo.FindOrCreate(ctx, models.UncategorizedCategoryName, models.UncategorizedCategoryType)
+		if err == nil {
+			transactions, err = s.transactionRepo.FindByUserIDAndCategoryID(ctx, userID, uncategorized.ID)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.engine.ActiveRules(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []models.Transaction
+	deltas := make(map[uuid.UUID]float64)
+	for _, t := range transactions {
+		if len(t.Lines) > 0 {
+			continue // transaksi split kategorinya per line, rule gak nyentuh
+		}
+		rule := MatchRules(rules, RuleInput{Title: t.Title, Description: t.Description, Amount: t.Amount})
+		if rule == nil {
+			continue
+		}
+
+		tags := models.JoinTags(models.SplitTags(t.Tags), rule.TagList())
+		if rule.CategoryID == t.CategoryID && tags == t.Tags {
+			continue
+		}
+
+		newAmount := signedAmount(rule.Category, t.Amount)
+		deltas[t.WalletID] += newAmount - t.Amount
+
+		t.CategoryID = rule.CategoryID
+		t.Category = rule.Category
+		t.Amount = newAmount
+		t.Tags = tags
+		changed = append(changed, t)
+	}
+
+	if err := s.transactions.UpdateBatch(ctx, userID, changed, deltas); err != nil {
+		return nil, err
+	}
+
+	res := response.ApplyCategoryRulesResponse{
+		Checked:      len(transactions),
+		Updated:      len(changed),
+		Transactions: []response.TransactionResponse{},
+	}
+	for _, t := range changed {
+		res.Transactions = append(res.Transactions, toTransactionResponse(t))
+	}
+	return &res, nil
+}
+
+func (s *categoryRuleService) fillRule(ctx context.Context, rule *models.CategoryRule, input request.CategoryRuleRequest) error {
+	var conditions []models.RuleCondition
+	for _, c := range input.Conditions {
+		condition := models.RuleCondition{Field: c.Field, Operator: c.Operator, Value: c.Value}
+		if err := validateRuleCondition(condition); err != nil {
+			return err
+		}
+		conditions = append(conditions, condition)
+	}
+	raw, err := json.Marshal(conditions)
+	if err != nil {
+		return err
+	}
+
+	category, err := s.categoryRepo.FindByName(ctx, input.CategoryName)
+	if err != nil {
+		return apperror.Replace(err, ErrCategoryNotFound)
+	}
+
+	rule.Name = input.Name
+	rule.Conditions = string(raw)
+	rule.CategoryID = category.ID
+	rule.Category = *category
+	rule.Tags = models.JoinTags(input.Tags)
+	if input.Priority != nil {
+		rule.Priority = *input.Priority
+	}
+	if input.Active != nil {
+		rule.Active = *input.Active
+	}
+	return nil
+}
+
+func toCategoryRuleResponse(rule models.CategoryRule) response.CategoryRuleResponse {
+	res := response.CategoryRuleResponse{
+		ID:         rule.ID.String(),
+		Name:       rule.Name,
+		Priority:   rule.Priority,
+		Active:     rule.Active,
+		Conditions: []response.RuleConditionResponse{},
+		Category: response.CategoryResponse{
+			ID:   rule.Category.ID.String(),
+			Name: rule.Category.Name,
+			Type: rule.Category.Type,
+		},
+		Tags:      rule.TagList(),
+		CreatedAt: rule.CreatedAt,
+	}
+	for _, c := range rule.ConditionList() {
+		res.Conditions = append(res.Conditions, response.RuleConditionResponse{
+			Field:    c.Field,
+			Operator: c.Operator,
+			Value:    c.Value,
+		})
+	}
+	return res
+}
@@ -1,24 +1,27 @@
 package services
 
 import (
+	"cashflow_gin/apperror"
 	"cashflow_gin/dto/request"
 	"cashflow_gin/dto/response"
 	"cashflow_gin/models"
 	"cashflow_gin/repository"
-	"errors"
+	"cashflow_gin/tracing"
+	"context"
 
 	"github.com/google/uuid"
 )
 
 type CategoryService interface {
-	CreateDefaultCategories() (*[]models.Category, error)
-	GetAllCategories(userRole float64) (*[]models.Category, error)
+	CreateDefaultCategories(ctx context.Context) (*[]models.Category, error)
+	GetAllCategories(ctx context.Context, userRole float64) (*[]models.Category, error)
 
-	CreateMy(userID uuid.UUID, input request.CreateCategoryRequest) (*response.CategoryResponse, error)
-	GetMine(userID uuid.UUID) (*[]response.CategoryResponse, error)
+	CreateMy(ctx context.Context, userID uuid.UUID, input request.CreateCategoryRequest) (*response.CategoryResponse, error)
+	GetMine(ctx context.Context, userID uuid.UUID) (*[]response.CategoryResponse, error)
 
-	UpdateById(userID, categoryID uuid.UUID, input request.CreateCategoryRequest) (*response.CategoryResponse, error)
-	DeleteById(userID, categoryID uuid.UUID) error
+	// expectedVersion = version dari header If-Match
+	UpdateById(ctx context.Context, userID, categoryID uuid.UUID, expectedVersion int, input request.CreateCategoryRequest) (*response.CategoryResponse, error)
+	DeleteById(ctx context.Context, userID, categoryID uuid.UUID, expectedVersion int) error
 }
 
 type categoryService struct {
@@ -29,20 +32,29 @@ func NewCategoryService(r repository.CategoryRepository) CategoryService {
 	return &categoryService{repo: r}
 }
 
-func (s *categoryService) CreateDefaultCategories() (*[]models.Category, error) {
-	newCategory, err := s.repo.CreateDefaultCategories()
+func (s *categoryService) CreateDefaultCategories(ctx context.Context) (*[]models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.CreateDefaultCategories")
+	defer span.End()
+
+	newCategory, err := s.repo.CreateDefaultCategories(ctx)
 	return newCategory, err
 }
 
-func (s *categoryService) GetAllCategories(userRole float64) (*[]models.Category, error) {
+func (s *categoryService) GetAllCategories(ctx context.Context, userRole float64) (*[]models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.GetAllCategories")
+	defer span.End()
+
 	if userRole > 2 {
-		return nil, errors.New("forbidden: access is denied")
+		return nil, apperror.ErrForbidden.WithMessage("forbidden: access is denied")
 	}
 
-	return s.repo.FindAll()
+	return s.repo.FindAll(ctx)
 }
 
-func (s *categoryService) CreateMy(userID uuid.UUID, input request.CreateCategoryRequest) (*response.CategoryResponse, error) {
+func (s *categoryService) CreateMy(ctx context.Context, userID uuid.UUID, input request.CreateCategoryRequest) (*response.CategoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.CreateMy")
+	defer span.End()
+
 	category := models.Category{
 		UserID: userID,
 		Name:   input.Name,
@@ -52,21 +64,22 @@ func (s *categoryService) CreateMy(userID uuid.UUID, input request.CreateCategor
 	if input.GroupID != "" {
 		id, err := uuid.Parse(input.GroupID)
 		if err != nil {
-			return nil, errors.New("invalid group id")
+			return nil, ErrInvalidGroupID
 		}
 		category.GroupID = &id
 	}
 
-	createdCategory, err := s.repo.Create(&category)
+	createdCategory, err := s.repo.Create(ctx, &category)
 	if err != nil {
 		return nil, err
 	}
 
 	res := response.CategoryResponse{
-		ID:     createdCategory.ID.String(),
-		UserID: createdCategory.UserID.String(),
-		Name:   createdCategory.Name,
-		Type:   createdCategory.Type,
+		ID:      createdCategory.ID.String(),
+		UserID:  createdCategory.UserID.String(),
+		Name:    createdCategory.Name,
+		Type:    createdCategory.Type,
+		Version: createdCategory.Version,
 	}
 	if createdCategory.GroupID != nil {
 		res.GroupID = createdCategory.GroupID.String()
@@ -75,8 +88,11 @@ func (s *categoryService) CreateMy(userID uuid.UUID, input request.CreateCategor
 	return &res, nil
 }
 
-func (s *categoryService) GetMine(userID uuid.UUID) (*[]response.CategoryResponse, error) {
-	categories, err := s.repo.FindByUserID(userID)
+func (s *categoryService) GetMine(ctx context.Context, userID uuid.UUID) (*[]response.CategoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.GetMine")
+	defer span.End()
+
+	categories, err := s.repo.FindByUserID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -84,10 +100,11 @@ func (s *categoryService) GetMine(userID uuid.UUID) (*[]response.CategoryRespons
 	var res []response.CategoryResponse
 	for _, category := range *categories {
 		r := response.CategoryResponse{
-			ID:     category.ID.String(),
-			UserID: category.UserID.String(),
-			Name:   category.Name,
-			Type:   category.Type,
+			ID:      category.ID.String(),
+			UserID:  category.UserID.String(),
+			Name:    category.Name,
+			Type:    category.Type,
+			Version: category.Version,
 		}
 		if category.GroupID != nil {
 			r.GroupID = category.GroupID.String()
@@ -98,10 +115,16 @@ func (s *categoryService) GetMine(userID uuid.UUID) (*[]response.CategoryRespons
 	return &res, nil
 }
 
-func (s *categoryService) UpdateById(userID, categoryID uuid.UUID, input request.CreateCategoryRequest) (*response.CategoryResponse, error) {
-	category, err := s.repo.FindByIDAndUserID(categoryID, userID)
+func (s *categoryService) UpdateById(ctx context.Context, userID, categoryID uuid.UUID, expectedVersion int, input request.CreateCategoryRequest) (*response.CategoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.UpdateById")
+	defer span.End()
+
+	category, err := s.repo.FindByIDAndUserID(ctx, categoryID, userID)
 	if err != nil {
-		return nil, errors.New("category not found or unauthorized")
+		return nil, apperror.Replace(err, ErrCategoryNotFound)
+	}
+	if err := checkVersion(category.Version, expectedVersion); err != nil {
+		return nil, err
 	}
 
 	category.Name = input.Name
@@ -110,23 +133,24 @@ func (s *categoryService) UpdateById(userID, categoryID uuid.UUID, input request
 	if input.GroupID != "" {
 		groupID, err := uuid.Parse(input.GroupID)
 		if err != nil {
-			return nil, errors.New("invalid group id")
+			return nil, ErrInvalidGroupID
 		}
 		category.GroupID = &groupID
 	} else {
 		category.GroupID = nil
 	}
 
-	updatedCategory, err := s.repo.Update(category)
+	updatedCategory, err := s.repo.Update(ctx, category)
 	if err != nil {
 		return nil, err
 	}
 
 	res := response.CategoryResponse{
-		ID:     updatedCategory.ID.String(),
-		UserID: updatedCategory.UserID.String(),
-		Name:   updatedCategory.Name,
-		Type:   updatedCategory.Type,
+		ID:      updatedCategory.ID.String(),
+		UserID:  updatedCategory.UserID.String(),
+		Name:    updatedCategory.Name,
+		Type:    updatedCategory.Type,
+		Version: updatedCategory.Version,
 	}
 	if updatedCategory.GroupID != nil {
 		res.GroupID = updatedCategory.GroupID.String()
@@ -135,11 +159,17 @@ func (s *categoryService) UpdateById(userID, categoryID uuid.UUID, input request
 	return &res, nil
 }
 
-func (s *categoryService) DeleteById(userID, categoryID uuid.UUID) error {
-	category, err := s.repo.FindByIDAndUserID(categoryID, userID)
+func (s *categoryService) DeleteById(ctx context.Context, userID, categoryID uuid.UUID, expectedVersion int) error {
+	ctx, span := tracing.Start(ctx, "CategoryService.DeleteById")
+	defer span.End()
+
+	category, err := s.repo.FindByIDAndUserID(ctx, categoryID, userID)
 	if err != nil {
-		return errors.New("category not found or unauthorized")
+		return apperror.Replace(err, ErrCategoryNotFound)
+	}
+	if err := checkVersion(category.Version, expectedVersion); err != nil {
+		return err
 	}
 
-	return s.repo.Delete(category)
+	return s.repo.Delete(ctx, category)
 }
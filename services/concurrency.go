@@ -0,0 +1,19 @@
+package services
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/repository"
+)
+
+// ErrVersionMismatch: If-Match dari client beda dengan version di DB (client pegang data basi).
+var ErrVersionMismatch = apperror.New(apperror.KindPrecondition, "version_mismatch", "versi data tidak cocok dengan If-Match, ambil ulang data terbaru")
+
+// ErrConcurrentUpdate: version masih cocok pas dicek, tapi keburu diubah request lain sebelum UPDATE jalan.
+var ErrConcurrentUpdate = repository.ErrStaleVersion
+
+func checkVersion(current, expected int) error {
+	if current != expected {
+		return ErrVersionMismatch
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package services
+
+import "cashflow_gin/apperror"
+
+// Error domain yang dipakai lintas service. Code-nya jangan diganti sembarangan, client bisa aja
+// udah switch pakai code ini. Error yang cuma dipakai satu service ditaruh di file service-nya.
+var (
+	ErrUserNotFound         = apperror.NotFound("user_not_found", "user not found")
+	ErrWalletNotFound       = apperror.NotFound("wallet_not_found", "wallet not found")
+	ErrWalletForbidden      = apperror.Forbidden("wallet_forbidden", "unauthorized: wallet does not belong to user")
+	ErrGroupNotFound        = apperror.NotFound("group_not_found", "group not found")
+	ErrNotGroupMember       = apperror.Forbidden("not_group_member", "unauthorized: user is not a member of the group")
+	ErrTransactionNotFound  = apperror.NotFound("transaction_not_found", "transaction not found")
+	ErrTransactionForbidden = apperror.Forbidden("transaction_forbidden", "unauthorized: transaction does not belong to user")
+	ErrCategoryNotFound     = apperror.NotFound("category_not_found", "category not found")
+
+	ErrInvalidWalletID   = apperror.BadRequest("invalid_wallet_id", "invalid wallet id")
+	ErrInvalidGroupID    = apperror.BadRequest("invalid_group_id", "invalid group id")
+	ErrInvalidCategoryID = apperror.BadRequest("invalid_category_id", "invalid category id")
+	ErrInvalidPayeeID    = apperror.BadRequest("invalid_payee_id", "invalid payee id")
+	ErrPayeeWalletScope  = apperror.Validation("payee_wallet_mismatch", "payee does not belong to the wallet owner or group")
+	ErrInvalidFromDate   = apperror.BadRequest("invalid_from_date", "invalid from date")
+	ErrInvalidToDate     = apperror.BadRequest("invalid_to_date", "invalid to date")
+)
@@ -1,35 +1,44 @@
 package services
 
 import (
+	"cashflow_gin/apperror"
 	"cashflow_gin/dto/request"
 	"cashflow_gin/dto/response"
 	"cashflow_gin/models"
 	"cashflow_gin/repository"
+	"cashflow_gin/tracing"
+	"context"
 
 	"github.com/google/uuid"
 )
 
 type GroupService interface {
-	CreateGroup(ownerID uuid.UUID, input request.CreateGroupRequest) (*response.GroupResponse, error)
-	GetAllGroups() (*[]response.GroupResponse, error)
+	CreateGroup(ctx context.Context, ownerID uuid.UUID, input request.CreateGroupRequest) (*response.GroupResponse, error)
+	GetAllGroups(ctx context.Context) (*[]response.GroupResponse, error)
 
-	GetGroupByID(groupID uuid.UUID) (*response.GroupResponse, error)
-	UpdateGroup(groupID uuid.UUID, name string) (*response.GroupResponse, error)
-	DeleteGroup(groupID uuid.UUID) error
+	GetGroupByID(ctx context.Context, groupID uuid.UUID) (*response.GroupResponse, error)
+	// expectedVersion = version dari header If-Match
+	UpdateGroup(ctx context.Context, userID, groupID uuid.UUID, expectedVersion int, input request.UpdateGroupRequest) (*response.GroupResponse, error)
+	DeleteGroup(ctx context.Context, groupID uuid.UUID) error
 
-	AddUserToGroup(groupID uuid.UUID, userIDs []uuid.UUID) error
-	RemoveUserFromGroup(groupID, userID uuid.UUID) error
+	AddUserToGroup(ctx context.Context, groupID uuid.UUID, userIDs []uuid.UUID) error
+	// actorID = yang manggil: owner / admin group, atau member yang keluar sendiri
+	RemoveUserFromGroup(ctx context.Context, actorID, groupID, userID uuid.UUID, expectedVersion int) error
 }
 
 type groupService struct {
-	repo repository.GroupRepository
+	repo     repository.GroupRepository
+	notifier NotificationPublisher
 }
 
-func NewGroupService(r repository.GroupRepository) GroupService {
-	return &groupService{repo: r}
+func NewGroupService(r repository.GroupRepository, notifier NotificationPublisher) GroupService {
+	return &groupService{repo: r, notifier: notifier}
 }
 
-func (s *groupService) CreateGroup(ownerID uuid.UUID, input request.CreateGroupRequest) (*response.GroupResponse, error) {
+func (s *groupService) CreateGroup(ctx context.Context, ownerID uuid.UUID, input request.CreateGroupRequest) (*response.GroupResponse, error) {
+	ctx, span := tracing.Start(ctx, "GroupService.CreateGroup")
+	defer span.End()
+
 	uniqMemberID := make(map[uuid.UUID]bool)
 	uniqMemberID[ownerID] = true
 
@@ -89,11 +98,20 @@ func (s *groupService) CreateGroup(ownerID uuid.UUID, input request.CreateGroupR
 
 	// 4. SAVE KE DB (Panggil Repo yang Transactional)
 	// Kita kirim pointer biar ID-nya ke-generate dan balik ke variable ini
-	err := s.repo.CreateGroupWithWalletAndMembers(&newGroup, &newWallet, &members)
+	err := s.repo.CreateGroupWithWalletAndMembers(ctx, &newGroup, &newWallet, &members)
 	if err != nil {
 		return &response.GroupResponse{}, err
 	}
 
+	// Kabarin member yang ditambahin (owner gak perlu)
+	var invited []uuid.UUID
+	for userID := range uniqMemberID {
+		if userID != ownerID {
+			invited = append(invited, userID)
+		}
+	}
+	s.notifyMembersAdded(ctx, newGroup, invited)
+
 	// 5. MAPPING KE RESPONSE (Manual Mapping biar Rapi)
 	// Ambil data member yang baru disimpan buat ditampilkan
 	var memberResponses []response.GroupMemberResponse
@@ -123,9 +141,12 @@ func (s *groupService) CreateGroup(ownerID uuid.UUID, input request.CreateGroupR
 	return &res, nil
 }
 
-func (s *groupService) GetAllGroups() (*[]response.GroupResponse, error) {
+func (s *groupService) GetAllGroups(ctx context.Context) (*[]response.GroupResponse, error) {
+	ctx, span := tracing.Start(ctx, "GroupService.GetAllGroups")
+	defer span.End()
+
 	// Implementasi logika untuk mendapatkan semua grup
-	groups, err := s.repo.GetAllGroups()
+	groups, err := s.repo.GetAllGroups(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -149,16 +170,20 @@ func (s *groupService) GetAllGroups() (*[]response.GroupResponse, error) {
 			Description:  group.Description,
 			Wallet:       walletRes,
 			TotalMembers: group.MemberCount,
+			Version:      group.Version,
 		})
 	}
 
 	return &groupResponses, nil
 }
 
-func (s *groupService) GetGroupByID(groupID uuid.UUID) (*response.GroupResponse, error) {
+func (s *groupService) GetGroupByID(ctx context.Context, groupID uuid.UUID) (*response.GroupResponse, error) {
+	ctx, span := tracing.Start(ctx, "GroupService.GetGroupByID")
+	defer span.End()
+
 	// Implementasi logika untuk mendapatkan grup berdasarkan ID
 
-	group, err := s.repo.GetGroupByID(groupID)
+	group, err := s.repo.GetGroupByID(ctx, groupID)
 	if err != nil {
 		return nil, err
 	}
@@ -192,22 +217,51 @@ func (s *groupService) GetGroupByID(groupID uuid.UUID) (*response.GroupResponse,
 		Wallet:       walletRes,
 		Members:      memberResponses,
 		TotalMembers: group.MemberCount,
+		Version:      group.Version,
 	}
 
 	return &res, nil
 }
 
-func (s *groupService) UpdateGroup(groupID uuid.UUID, name string) (*response.GroupResponse, error) {
-	// Implementasi logika untuk memperbarui nama grup
-	return &response.GroupResponse{}, nil
+func (s *groupService) UpdateGroup(ctx context.Context, userID, groupID uuid.UUID, expectedVersion int, input request.UpdateGroupRequest) (*response.GroupResponse, error) {
+	ctx, span := tracing.Start(ctx, "GroupService.UpdateGroup")
+	defer span.End()
+
+	group, err := s.repo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return nil, apperror.Replace(err, ErrGroupNotFound)
+	}
+
+	// Cuma owner / admin group yang boleh ganti nama & deskripsi
+	if !isGroupAdmin(*group, userID) {
+		return nil, apperror.Forbidden("not_group_admin", "forbidden: only group admin can update group")
+	}
+
+	if err := checkVersion(group.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	group.Name = input.Name
+	group.Description = input.Description
+	if err := s.repo.UpdateGroup(ctx, group); err != nil {
+		return nil, err
+	}
+
+	return s.GetGroupByID(ctx, groupID)
 }
 
-func (s *groupService) DeleteGroup(groupID uuid.UUID) error {
-	return s.repo.DeleteGroup(groupID)
+func (s *groupService) DeleteGroup(ctx context.Context, groupID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "GroupService.DeleteGroup")
+	defer span.End()
+
+	return s.repo.DeleteGroup(ctx, groupID)
 }
 
 // services/group_service.go
-func (s *groupService) AddUserToGroup(groupID uuid.UUID, userIDs []uuid.UUID) error {
+func (s *groupService) AddUserToGroup(ctx context.Context, groupID uuid.UUID, userIDs []uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "GroupService.AddUserToGroup")
+	defer span.End()
+
 	// 1. (Opsional) Cek dulu Group-nya ada gak?
 	// _, err := s.repo.GetGroupByID(groupID)
 	// if err != nil { return errors.New("group not found") }
@@ -224,9 +278,60 @@ func (s *groupService) AddUserToGroup(groupID uuid.UUID, userIDs []uuid.UUID) er
 	}
 
 	// 3. Panggil Repo buat nyimpen
-	return s.repo.CreateMembers(members)
+	if err := s.repo.CreateMembers(ctx, members); err != nil {
+		return err
+	}
+
+	if group, err := s.repo.GetGroupByID(ctx, groupID); err == nil {
+		s.notifyMembersAdded(ctx, *group, userIDs)
+	}
+	return nil
+}
+
+func (s *groupService) RemoveUserFromGroup(ctx context.Context, actorID, groupID, userID uuid.UUID, expectedVersion int) error {
+	ctx, span := tracing.Start(ctx, "GroupService.RemoveUserFromGroup")
+	defer span.End()
+
+	group, err := s.repo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return apperror.Replace(err, ErrGroupNotFound)
+	}
+
+	// Owner gak bisa dikeluarin (termasuk sama admin), member lain cuma bisa dikeluarin owner / admin
+	// atau keluar sendiri
+	if userID == group.OwnerID {
+		return apperror.Forbidden("cannot_remove_group_owner", "forbidden: group owner cannot be removed")
+	}
+	if actorID != userID && !isGroupAdmin(*group, actorID) {
+		return apperror.Forbidden("not_group_admin", "forbidden: only group admin can remove other members")
+	}
+	if err := checkVersion(group.Version, expectedVersion); err != nil {
+		return err
+	}
+	return s.repo.RemoveUserFromGroup(ctx, groupID, userID, group.Version)
+}
+
+// isGroupAdmin: owner group atau member dengan role admin.
+func isGroupAdmin(group models.Group, userID uuid.UUID) bool {
+	if group.OwnerID == userID {
+		return true
+	}
+	for _, m := range group.Members {
+		if m.UserID == userID && m.MembersRole == models.GroupAdmin {
+			return true
+		}
+	}
+	return false
 }
 
-func (s *groupService) RemoveUserFromGroup(groupID, userID uuid.UUID) error {
-	return s.repo.RemoveUserFromGroup(groupID, userID)
+func (s *groupService) notifyMembersAdded(ctx context.Context, group models.Group, userIDs []uuid.UUID) {
+	if s.notifier == nil || len(userIDs) == 0 {
+		return
+	}
+	s.notifier.Notify(ctx, userIDs, NotificationMessage{
+		Type:  models.NotificationGroupMemberAdded,
+		Title: "Kamu ditambahkan ke group " + group.Name,
+		Body:  "Sekarang kamu bisa lihat dan nambah transaksi di wallet group " + group.Name + ".",
+		Data:  map[string]string{"group_id": group.ID.String(), "group_name": group.Name},
+	})
 }
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"cashflow_gin/logging"
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+)
+
+// Nama channel, dipakai juga sebagai key di NotificationPreference.
+const (
+	NotificationChannelInApp = "in_app"
+	NotificationChannelEmail = "email"
+)
+
+// NotificationChannel = cara nganterin notifikasi ke user. Tambah channel baru (push, telegram, dll)
+// cukup implement interface ini terus daftarin di NewNotificationService.
+type NotificationChannel interface {
+	Name() string
+	// DefaultEnabled dipakai kalau user belum pernah set preference buat channel ini.
+	DefaultEnabled() bool
+	Send(ctx context.Context, user *models.User, notification *models.Notification) error
+}
+
+// --- In-App: disimpan ke DB, dibaca lewat GET /notifications ---
+
+type inAppChannel struct {
+	repo repository.NotificationRepository
+}
+
+func NewInAppChannel(r repository.NotificationRepository) NotificationChannel {
+	return &inAppChannel{repo: r}
+}
+
+func (c *inAppChannel) Name() string         { return NotificationChannelInApp }
+func (c *inAppChannel) DefaultEnabled() bool { return true }
+
+func (c *inAppChannel) Send(ctx context.Context, user *models.User, notification *models.Notification) error {
+	return c.repo.Create(ctx, notification)
+}
+
+// --- Email: lewat Mailer, defaultnya off ---
+
+// Mailer = abstraksi pengirim email (SMTP, SES, dll).
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// LogMailer cuma nulis ke log, buat development / kalau belum ada SMTP.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, to, subject, body string) error {
+	logging.FromContext(ctx).InfoContext(ctx, "mailer: email not sent (LogMailer)", "to", to, "subject", subject, "body", body)
+	return nil
+}
+
+type emailChannel struct {
+	mailer Mailer
+}
+
+func NewEmailChannel(m Mailer) NotificationChannel {
+	return &emailChannel{mailer: m}
+}
+
+func (c *emailChannel) Name() string         { return NotificationChannelEmail }
+func (c *emailChannel) DefaultEnabled() bool { return false }
+
+func (c *emailChannel) Send(ctx context.Context, user *models.User, notification *models.Notification) error {
+	return c.mailer.Send(ctx, user.Email, notification.Title, notification.Body)
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/logging"
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"cashflow_gin/tracing"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+const notificationListLimit = 50
+
+// NotificationMessage = isi notifikasi dari service lain. Data di-marshal jadi JSON.
+type NotificationMessage struct {
+	Type  string
+	Title string
+	Body  string
+	Data  interface{}
+}
+
+// NotificationPublisher = API internal yang dipanggil service lain (group, transaction, budget, ...).
+// Pengiriman jalan di background, jadi gak nge-block request dan error-nya cuma di-log.
+type NotificationPublisher interface {
+	Notify(ctx context.Context, userIDs []uuid.UUID, message NotificationMessage)
+}
+
+type NotificationService interface {
+	NotificationPublisher
+	GetMine(ctx context.Context, userID uuid.UUID, unreadOnly bool) (*response.NotificationListResponse, error)
+	MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error)
+	GetPreferences(ctx context.Context, userID uuid.UUID) ([]response.NotificationPreferenceResponse, error)
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, input request.UpdateNotificationPreferencesRequest) ([]response.NotificationPreferenceResponse, error)
+	// Close nunggu pengiriman yang masih jalan di background. Dipanggil pas shutdown sebelum DB ditutup;
+	// Notify setelahnya di-drop.
+	Close()
+}
+
+type notificationService struct {
+	repo     repository.NotificationRepository
+	userRepo repository.UserRepository
+	channels []NotificationChannel
+
+	mu      sync.Mutex
+	closed  bool
+	pending sync.WaitGroup
+}
+
+func NewNotificationService(r repository.NotificationRepository, uRepo repository.UserRepository, channels ...NotificationChannel) NotificationService {
+	return &notificationService{repo: r, userRepo: uRepo, channels: channels}
+}
+
+func (s *notificationService) Notify(ctx context.Context, userIDs []uuid.UUID, message NotificationMessage) {
+	ctx, span := tracing.Start(ctx, "NotificationService.Notify")
+	defer span.End()
+
+	if len(userIDs) == 0 {
+		return
+	}
+
+	data := ""
+	if message.Data != nil {
+		raw, err := json.Marshal(message.Data)
+		if err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "notification: failed to marshal data", "type", message.Type, "error", err)
+		} else {
+			data = string(raw)
+		}
+	}
+
+	// Dicatat di WaitGroup di bawah lock biar gak balapan sama Close (Add gak boleh barengan Wait)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		logging.FromContext(ctx).WarnContext(ctx, "notification: service closed, dropping notification", "type", message.Type)
+		return
+	}
+
+	// Dikirim di background, context request keburu selesai -> pakai versi yang gak ikut ke-cancel (logger tetap kebawa)
+	ctx = context.WithoutCancel(ctx)
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		for _, userID := range userIDs {
+			s.deliver(ctx, userID, message, data)
+		}
+	}()
+}
+
+func (s *notificationService) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.pending.Wait()
+}
+
+func (s *notificationService) deliver(ctx context.Context, userID uuid.UUID, message NotificationMessage, data string) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "notification: user not found", "user_id", userID, "error", err)
+		return
+	}
+
+	enabled, err := s.enabledChannels(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "notification: failed to load preferences", "user_id", userID, "error", err)
+		return
+	}
+
+	for _, channel := range s.channels {
+		if !enabled[preferenceKey(message.Type, channel.Name())] {
+			continue
+		}
+		// Tiap channel dapet copy sendiri biar ID dari in-app gak kebawa kemana-mana
+		notification := models.Notification{
+			UserID: userID,
+			Type:   message.Type,
+			Title:  message.Title,
+			Body:   message.Body,
+			Data:   data,
+		}
+		if err := channel.Send(ctx, user, &notification); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "notification: channel failed",
+				"channel", channel.Name(), "user_id", userID, "error", err)
+		}
+	}
+}
+
+// enabledChannels gabungin default channel sama preference yang disimpen user.
+func (s *notificationService) enabledChannels(ctx context.Context, userID uuid.UUID) (map[string]bool, error) {
+	preferences, err := s.repo.FindPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make(map[string]bool)
+	for _, t := range models.NotificationTypes {
+		for _, channel := range s.channels {
+			enabled[preferenceKey(t, channel.Name())] = channel.DefaultEnabled()
+		}
+	}
+	for _, p := range preferences {
+		key := preferenceKey(p.Type, p.Channel)
+		if _, ok := enabled[key]; ok {
+			enabled[key] = p.Enabled
+		}
+	}
+	return enabled, nil
+}
+
+func (s *notificationService) GetMine(ctx context.Context, userID uuid.UUID, unreadOnly bool) (*response.NotificationListResponse, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.GetMine")
+	defer span.End()
+
+	notifications, err := s.repo.FindByUserID(ctx, userID, unreadOnly, notificationListLimit)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := response.NotificationListResponse{
+		UnreadCount:   unread,
+		Notifications: []response.NotificationResponse{},
+	}
+	for _, n := range notifications {
+		item := response.NotificationResponse{
+			ID:        n.ID.String(),
+			Type:      n.Type,
+			Title:     n.Title,
+			Body:      n.Body,
+			ReadAt:    n.ReadAt,
+			CreatedAt: n.CreatedAt,
+		}
+		if n.Data != "" {
+			item.Data = json.RawMessage(n.Data)
+		}
+		res.Notifications = append(res.Notifications, item)
+	}
+	return &res, nil
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "NotificationService.MarkRead")
+	defer span.End()
+
+	found, err := s.repo.MarkRead(ctx, userID, notificationID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return apperror.NotFound("notification_not_found", "notification not found")
+	}
+	return nil
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.MarkAllRead")
+	defer span.End()
+
+	return s.repo.MarkAllRead(ctx, userID)
+}
+
+func (s *notificationService) GetPreferences(ctx context.Context, userID uuid.UUID) ([]response.NotificationPreferenceResponse, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.GetPreferences")
+	defer span.End()
+
+	enabled, err := s.enabledChannels(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []response.NotificationPreferenceResponse
+	for _, t := range models.NotificationTypes {
+		for _, channel := range s.channels {
+			res = append(res, response.NotificationPreferenceResponse{
+				Type:    t,
+				Channel: channel.Name(),
+				Enabled: enabled[preferenceKey(t, channel.Name())],
+			})
+		}
+	}
+	return res, nil
+}
+
+func (s *notificationService) UpdatePreferences(ctx context.Context, userID uuid.UUID, input request.UpdateNotificationPreferencesRequest) ([]response.NotificationPreferenceResponse, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.UpdatePreferences")
+	defer span.End()
+
+	current, err := s.enabledChannels(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var preferences []models.NotificationPreference
+	for _, p := range input.Preferences {
+		if _, ok := current[preferenceKey(p.Type, p.Channel)]; !ok {
+			return nil, apperror.Validation("unknown_notification_preference", fmt.Sprintf("kombinasi type %s dan channel %s tidak dikenal, type: %s, channel: %s",
+				p.Type, p.Channel, strings.Join(models.NotificationTypes, ", "), strings.Join(s.channelNames(), ", ")))
+		}
+		preferences = append(preferences, models.NotificationPreference{
+			UserID:  userID,
+			Type:    p.Type,
+			Channel: p.Channel,
+			Enabled: *p.Enabled,
+		})
+	}
+
+	if err := s.repo.UpsertPreferences(ctx, preferences); err != nil {
+		return nil, err
+	}
+	return s.GetPreferences(ctx, userID)
+}
+
+func (s *notificationService) channelNames() []string {
+	var names []string
+	for _, c := range s.channels {
+		names = append(names, c.Name())
+	}
+	return names
+}
+
+func preferenceKey(notificationType, channel string) string {
+	return notificationType + "|" + channel
+}
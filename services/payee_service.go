@@ -0,0 +1,435 @@
+package services
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"cashflow_gin/statement"
+	"cashflow_gin/tracing"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPayeeSearchLimit = 10
+	maxPayeeHistoryItems    = 100
+)
+
+var ErrPayeeNotFound = apperror.NotFound("payee_not_found", "payee not found")
+
+type PayeeService interface {
+	Autocomplete(ctx context.Context, userID uuid.UUID, query request.PayeeSearchQuery) ([]response.PayeeResponse, error)
+	Create(ctx context.Context, userID uuid.UUID, input request.PayeeRequest) (*response.PayeeResponse, error)
+	Update(ctx context.Context, userID, payeeID uuid.UUID, input request.PayeeRequest) (*response.PayeeResponse, error)
+	Delete(ctx context.Context, userID, payeeID uuid.UUID) error
+	Merge(ctx context.Context, userID uuid.UUID, input request.MergePayeesRequest) (*response.PayeeResponse, error)
+	History(ctx context.Context, userID, payeeID uuid.UUID, query request.PayeeHistoryQuery) (*response.PayeeHistoryResponse, error)
+
+	// ResolveForWallet dipakai transaksi & import: cocokin nama ke payee/alias di scope wallet
+	// (wallet group -> payee group, wallet pribadi -> payee pribadi), belum ada = dibikin.
+	ResolveForWallet(ctx context.Context, userID uuid.UUID, wallet models.Wallet, name string) (*models.Payee, error)
+	// FindAccessible: payee pribadi milik user atau payee group yang user-nya member.
+	FindAccessible(ctx context.Context, userID, payeeID uuid.UUID) (*models.Payee, error)
+}
+
+type payeeService struct {
+	repo         repository.PayeeRepository
+	categoryRepo repository.CategoryRepository
+	groupRepo    repository.GroupRepository
+}
+
+func NewPayeeService(r repository.PayeeRepository, cRepo repository.CategoryRepository, gRepo repository.GroupRepository) PayeeService {
+	return &payeeService{repo: r, categoryRepo: cRepo, groupRepo: gRepo}
+}
+
+func (s *payeeService) Autocomplete(ctx context.Context, userID uuid.UUID, query request.PayeeSearchQuery) ([]response.PayeeResponse, error) {
+	ctx, span := tracing.Start(ctx, "PayeeService.Autocomplete")
+	defer span.End()
+
+	scope, err := s.scopeFor(ctx, userID, query.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultPayeeSearchLimit
+	}
+
+	res := []response.PayeeResponse{}
+	normalized := statement.NormalizePayee(query.Q)
+	if normalized == "" {
+		return res, nil
+	}
+
+	payees, err := s.repo.Search(ctx, scope, normalized, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range payees {
+		res = append(res, toPayeeResponse(p))
+	}
+	return res, nil
+}
+
+func (s *payeeService) Create(ctx context.Context, userID uuid.UUID, input request.PayeeRequest) (*response.PayeeResponse, error) {
+	ctx, span := tracing.Start(ctx, "PayeeService.Create")
+	defer span.End()
+
+	scope, err := s.scopeFor(ctx, userID, input.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	payee := models.Payee{UserID: userID, GroupID: scope.GroupID}
+	if err := s.fillPayee(ctx, scope, &payee, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, &payee); err != nil {
+		return nil, err
+	}
+
+	res := toPayeeResponse(payee)
+	return &res, nil
+}
+
+func (s *payeeService) Update(ctx context.Context, userID, payeeID uuid.UUID, input request.PayeeRequest) (*response.PayeeResponse, error) {
+	ctx, span := tracing.Start(ctx, "PayeeService.Update")
+	defer span.End()
+
+	payee, err := s.FindAccessible(ctx, userID, payeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scope gak bisa dipindah (pribadi <-> group), group_id di body diabaikan
+	scope := repository.PayeeScope{UserID: payee.UserID, GroupID: payee.GroupID}
+	if err := s.fillPayee(ctx, scope, payee, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, payee); err != nil {
+		return nil, err
+	}
+
+	res := toPayeeResponse(*payee)
+	return &res, nil
+}
+
+func (s *payeeService) Delete(ctx context.Context, userID, payeeID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "PayeeService.Delete")
+	defer span.End()
+
+	if _, err := s.FindAccessible(ctx, userID, payeeID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, payeeID)
+}
+
+// Merge gabungin payee dobel ke target: transaksi pindah, nama & alias source jadi alias target.
+func (s *payeeService) Merge(ctx context.Context, userID uuid.UUID, input request.MergePayeesRequest) (*response.PayeeResponse, error) {
+	ctx, span := tracing.Start(ctx, "PayeeService.Merge")
+	defer span.End()
+
+	targetID, _ := uuid.Parse(input.TargetID) // format udah divalidasi binding
+	target, err := s.FindAccessible(ctx, userID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sourceIDs []uuid.UUID
+	for _, raw := range input.SourceIDs {
+		id, _ := uuid.Parse(raw)
+		if id == target.ID {
+			return nil, apperror.Validation("merge_target_in_sources", "target_id gak boleh ada di source_ids")
+		}
+		sourceIDs = append(sourceIDs, id)
+	}
+
+	sources, err := s.repo.FindByIDs(ctx, sourceIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) != len(uniqueIDs(sourceIDs)) {
+		return nil, apperror.NotFound("payee_not_found", "sebagian payee source tidak ditemukan")
+	}
+
+	// Alias yang udah ada di target gak didobel
+	known := map[string]bool{target.NormalizedName: true}
+	for _, a := range target.Aliases {
+		known[a.NormalizedAlias] = true
+	}
+	var newAliases []models.PayeeAlias
+	addAlias := func(alias string) {
+		normalized := statement.NormalizePayee(alias)
+		if normalized == "" || known[normalized] {
+			return
+		}
+		known[normalized] = true
+		newAliases = append(newAliases, models.PayeeAlias{Alias: truncate(alias, 150), NormalizedAlias: truncate(normalized, 150)})
+	}
+
+	for _, source := range sources {
+		if !sameUUIDPtr(source.GroupID, target.GroupID) || (source.GroupID == nil && source.UserID != target.UserID) {
+			return nil, apperror.Validation("payee_owner_mismatch", fmt.Sprintf("payee %s beda pemilik dengan target, gak bisa digabung", source.Name))
+		}
+		addAlias(source.Name)
+		for _, a := range source.Aliases {
+			addAlias(a.Alias)
+		}
+	}
+
+	if err := s.repo.Merge(ctx, target.ID, sourceIDs, newAliases); err != nil {
+		return nil, err
+	}
+
+	merged, err := s.repo.FindByID(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	res := toPayeeResponse(*merged)
+	return &res, nil
+}
+
+// History = total & rekap bulanan transaksi ke payee ini, plus daftar transaksi terbaru.
+func (s *payeeService) History(ctx context.Context, userID, payeeID uuid.UUID, query request.PayeeHistoryQuery) (*response.PayeeHistoryResponse, error) {
+	ctx, span := tracing.Start(ctx, "PayeeService.History")
+	defer span.End()
+
+	payee, err := s.FindAccessible(ctx, userID, payeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var from, to *time.Time
+	if query.From != "" {
+		t, err := time.Parse(reportDateLayout, query.From)
+		if err != nil {
+			return nil, ErrInvalidFromDate
+		}
+		from = &t
+	}
+	if query.To != "" {
+		t, err := time.Parse(reportDateLayout, query.To)
+		if err != nil {
+			return nil, ErrInvalidToDate
+		}
+		t = t.AddDate(0, 0, 1) // to inklusif
+		to = &t
+	}
+
+	transactions, err := s.repo.FindTransactions(ctx, payee.ID, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	res := response.PayeeHistoryResponse{
+		Payee:            toPayeeResponse(*payee),
+		TransactionCount: len(transactions),
+		Monthly:          []response.PayeeMonthlyTotal{},
+		Transactions:     []response.TransactionResponse{},
+	}
+
+	// Transaksi udah urut terbaru dulu, jadi bulan juga keluar urut terbaru dulu
+	monthIndex := make(map[string]int)
+	for i, t := range transactions {
+		res.TotalAmount += t.Amount
+
+		month := t.Date.Format("2006-01")
+		idx, ok := monthIndex[month]
+		if !ok {
+			idx = len(res.Monthly)
+			monthIndex[month] = idx
+			res.Monthly = append(res.Monthly, response.PayeeMonthlyTotal{Month: month})
+		}
+		res.Monthly[idx].Total += t.Amount
+		res.Monthly[idx].TransactionCount++
+
+		if i < maxPayeeHistoryItems {
+			res.Transactions = append(res.Transactions, toTransactionResponse(t))
+		}
+	}
+
+	return &res, nil
+}
+
+func (s *payeeService) ResolveForWallet(ctx context.Context, userID uuid.UUID, wallet models.Wallet, name string) (*models.Payee, error) {
+	ctx, span := tracing.Start(ctx, "PayeeService.ResolveForWallet")
+	defer span.End()
+
+	normalized := statement.NormalizePayee(name)
+	if normalized == "" {
+		return nil, nil
+	}
+
+	scope := repository.PayeeScope{UserID: userID, GroupID: wallet.GroupID}
+	if wallet.GroupID == nil && wallet.UserID != nil {
+		scope.UserID = *wallet.UserID
+	}
+
+	payee, err := s.repo.FindByNormalized(ctx, scope, truncate(normalized, 150))
+	if err != nil || payee != nil {
+		return payee, err
+	}
+
+	payee = &models.Payee{
+		UserID:         scope.UserID,
+		GroupID:        scope.GroupID,
+		Name:           truncate(name, 150),
+		NormalizedName: truncate(normalized, 150),
+	}
+	if err := s.repo.Create(ctx, payee); err != nil {
+		return nil, err
+	}
+	return payee, nil
+}
+
+func (s *payeeService) FindAccessible(ctx context.Context, userID, payeeID uuid.UUID) (*models.Payee, error) {
+	ctx, span := tracing.Start(ctx, "PayeeService.FindAccessible")
+	defer span.End()
+
+	payee, err := s.repo.FindByID(ctx, payeeID)
+	if err != nil {
+		return nil, apperror.Replace(err, ErrPayeeNotFound)
+	}
+
+	if payee.GroupID != nil {
+		isMember, err := s.groupRepo.IsGroupMember(ctx, *payee.GroupID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check group membership: %w", err)
+		}
+		if !isMember {
+			return nil, ErrNotGroupMember.WithMessage("unauthorized: user is not a member of the payee group")
+		}
+		return payee, nil
+	}
+
+	if payee.UserID != userID {
+		return nil, apperror.Forbidden("payee_forbidden", "unauthorized: payee does not belong to user")
+	}
+	return payee, nil
+}
+
+// scopeFor: group_id kosong = payee pribadi, diisi = user harus member group itu.
+func (s *payeeService) scopeFor(ctx context.Context, userID uuid.UUID, rawGroupID string) (repository.PayeeScope, error) {
+	scope := repository.PayeeScope{UserID: userID}
+	if rawGroupID == "" {
+		return scope, nil
+	}
+
+	groupID, err := uuid.Parse(rawGroupID)
+	if err != nil {
+		return scope, ErrInvalidGroupID
+	}
+	isMember, err := s.groupRepo.IsGroupMember(ctx, groupID, userID)
+	if err != nil {
+		return scope, fmt.Errorf("failed to check group membership: %w", err)
+	}
+	if !isMember {
+		return scope, ErrNotGroupMember
+	}
+	scope.GroupID = &groupID
+	return scope, nil
+}
+
+// fillPayee isi nama, alias & default kategori, sekalian cek nama/alias belum dipakai payee lain di scope yang sama.
+func (s *payeeService) fillPayee(ctx context.Context, scope repository.PayeeScope, payee *models.Payee, input request.PayeeRequest) error {
+	normalized := statement.NormalizePayee(input.Name)
+	if normalized == "" {
+		return apperror.Validation("invalid_payee_name", "nama payee harus ada huruf/angka")
+	}
+
+	seen := map[string]bool{normalized: true}
+	if err := s.ensureAvailable(ctx, scope, payee.ID, input.Name, normalized); err != nil {
+		return err
+	}
+
+	var aliases []models.PayeeAlias
+	for _, alias := range input.Aliases {
+		normalizedAlias := statement.NormalizePayee(alias)
+		if normalizedAlias == "" || seen[normalizedAlias] {
+			continue
+		}
+		seen[normalizedAlias] = true
+		if err := s.ensureAvailable(ctx, scope, payee.ID, alias, normalizedAlias); err != nil {
+			return err
+		}
+		aliases = append(aliases, models.PayeeAlias{Alias: alias, NormalizedAlias: normalizedAlias})
+	}
+
+	payee.DefaultCategoryID = nil
+	payee.DefaultCategory = nil
+	if input.DefaultCategoryName != "" {
+		category, err := s.categoryRepo.FindByName(ctx, input.DefaultCategoryName)
+		if err != nil {
+			return apperror.Replace(err, ErrCategoryNotFound.WithMessage("default category not found"))
+		}
+		payee.DefaultCategoryID = &category.ID
+		payee.DefaultCategory = category
+	}
+
+	payee.Name = input.Name
+	payee.NormalizedName = normalized
+	payee.Aliases = aliases
+	return nil
+}
+
+func (s *payeeService) ensureAvailable(ctx context.Context, scope repository.PayeeScope, selfID uuid.UUID, raw, normalized string) error {
+	existing, err := s.repo.FindByNormalized(ctx, scope, normalized)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != selfID {
+		return apperror.Conflict("payee_name_taken", fmt.Sprintf("%q udah dipakai payee %s, gabungkan (merge) aja", raw, existing.Name))
+	}
+	return nil
+}
+
+func toPayeeResponse(p models.Payee) response.PayeeResponse {
+	res := response.PayeeResponse{
+		ID:               p.ID.String(),
+		Name:             p.Name,
+		Aliases:          []string{},
+		TransactionCount: p.TransactionCount,
+	}
+	if p.GroupID != nil {
+		res.GroupID = p.GroupID.String()
+	}
+	for _, a := range p.Aliases {
+		res.Aliases = append(res.Aliases, a.Alias)
+	}
+	if p.DefaultCategory != nil {
+		res.DefaultCategory = &response.CategoryResponse{
+			ID:   p.DefaultCategory.ID.String(),
+			Name: p.DefaultCategory.Name,
+			Type: p.DefaultCategory.Type,
+		}
+	}
+	return res
+}
+
+func toPayeeSummary(p *models.Payee) *response.PayeeSummaryResponse {
+	if p == nil {
+		return nil
+	}
+	return &response.PayeeSummaryResponse{ID: p.ID.String(), Name: p.Name}
+}
+
+func sameUUIDPtr(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func uniqueIDs(ids []uuid.UUID) map[uuid.UUID]bool {
+	set := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"cashflow_gin/events"
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	realtimeBufferSize = 32
+	// Membership group dicek ulang kalau cek terakhir udah lebih lama dari ini (user bisa dikeluarin dari group pas lagi connect)
+	realtimeMembershipTTL = 30 * time.Second
+)
+
+// RealtimeSubscription = 1 koneksi SSE. Events cuma nerima event wallet yang boleh dilihat user.
+type RealtimeSubscription struct {
+	Events <-chan events.Event
+
+	hub     *RealtimeHub
+	id      uuid.UUID
+	userID  uuid.UUID
+	ch      chan events.Event
+	wallets map[uuid.UUID]*uuid.UUID // walletID -> groupID (nil = wallet pribadi)
+
+	mu        sync.Mutex
+	checkedAt map[uuid.UUID]time.Time // groupID -> terakhir dicek IsGroupMember
+}
+
+// RealtimeHub nerima event dari bus lalu nyebarin ke koneksi SSE yang subscribe wallet terkait.
+type RealtimeHub struct {
+	walletRepo repository.WalletRepository
+	groupRepo  repository.GroupRepository
+
+	mu     sync.RWMutex
+	subs   map[uuid.UUID]*RealtimeSubscription
+	closed bool
+}
+
+func NewRealtimeHub(wRepo repository.WalletRepository, gRepo repository.GroupRepository) *RealtimeHub {
+	return &RealtimeHub{
+		walletRepo: wRepo,
+		groupRepo:  gRepo,
+		subs:       make(map[uuid.UUID]*RealtimeSubscription),
+	}
+}
+
+// Subscribe validasi akses ke tiap wallet. walletIDs kosong = semua wallet pribadi + wallet group yang diikuti.
+func (h *RealtimeHub) Subscribe(ctx context.Context, userID uuid.UUID, walletIDs []uuid.UUID) (*RealtimeSubscription, error) {
+	var wallets []models.Wallet
+	var err error
+	if len(walletIDs) == 0 {
+		wallets, err = h.walletRepo.FindAccessibleByUserID(ctx, userID)
+	} else {
+		wallets, err = h.walletRepo.FindByIDs(ctx, walletIDs)
+		if err == nil && len(wallets) != len(walletIDs) {
+			return nil, ErrWalletNotFound
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &RealtimeSubscription{
+		hub:       h,
+		id:        uuid.New(),
+		userID:    userID,
+		ch:        make(chan events.Event, realtimeBufferSize),
+		wallets:   make(map[uuid.UUID]*uuid.UUID),
+		checkedAt: make(map[uuid.UUID]time.Time),
+	}
+	sub.Events = sub.ch
+
+	now := time.Now()
+	for _, w := range wallets {
+		if w.GroupID != nil {
+			isMember, err := h.groupRepo.IsGroupMember(ctx, *w.GroupID, userID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check group membership: %w", err)
+			}
+			if !isMember {
+				return nil, ErrNotGroupMember.WithMessage("unauthorized: user is not a member of the group wallet")
+			}
+			sub.checkedAt[*w.GroupID] = now
+		} else if w.UserID == nil || *w.UserID != userID {
+			return nil, ErrWalletForbidden
+		}
+		sub.wallets[w.ID] = w.GroupID
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, errors.New("server is shutting down")
+	}
+	h.subs[sub.id] = sub
+
+	return sub, nil
+}
+
+// HandleEvent dipasang ke events.Bus. Kirim non-blocking: client yang lambat kehilangan event, bukan nahan publisher.
+func (h *RealtimeHub) HandleEvent(event events.Event) {
+	if event.WalletID == nil {
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, sub := range h.subs {
+		if _, ok := sub.wallets[*event.WalletID]; !ok {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+}
+
+// Close dipanggil pas server shutdown: channel semua subscription ditutup biar stream SSE-nya selesai
+// (kalau gak, http.Server.Shutdown nungguin koneksi SSE sampai timeout).
+func (h *RealtimeHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for id, sub := range h.subs {
+		close(sub.ch)
+		delete(h.subs, id)
+	}
+}
+
+// Close dipanggil pas koneksi putus.
+func (s *RealtimeSubscription) Close() {
+	s.hub.mu.Lock()
+	delete(s.hub.subs, s.id)
+	s.hub.mu.Unlock()
+}
+
+// Allowed dicek sebelum event dikirim ke client. Wallet group dicek ulang ke IsGroupMember kalau cache-nya udah basi.
+func (s *RealtimeSubscription) Allowed(ctx context.Context, event events.Event) bool {
+	if event.WalletID == nil {
+		return false
+	}
+	groupID, ok := s.wallets[*event.WalletID]
+	if !ok {
+		return false
+	}
+	if groupID == nil {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Since(s.checkedAt[*groupID]) < realtimeMembershipTTL {
+		return true
+	}
+
+	isMember, err := s.hub.groupRepo.IsGroupMember(ctx, *groupID, s.userID)
+	if err != nil || !isMember {
+		return false
+	}
+	s.checkedAt[*groupID] = time.Now()
+	return true
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/repository"
+	"cashflow_gin/tracing"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const reportDateLayout = "2006-01-02"
+
+type ReportService interface {
+	CategoryReport(ctx context.Context, userID uuid.UUID, query request.CategoryReportQuery) (*response.CategoryReportResponse, error)
+}
+
+type reportService struct {
+	repo       repository.ReportRepository
+	walletRepo repository.WalletRepository
+	groupRepo  repository.GroupRepository
+}
+
+func NewReportService(r repository.ReportRepository, wRepo repository.WalletRepository, gRepo repository.GroupRepository) ReportService {
+	return &reportService{repo: r, walletRepo: wRepo, groupRepo: gRepo}
+}
+
+// CategoryReport = total per kategori di rentang tanggal. Transaksi split masuk ke kategori tiap line-nya.
+func (s *reportService) CategoryReport(ctx context.Context, userID uuid.UUID, query request.CategoryReportQuery) (*response.CategoryReportResponse, error) {
+	ctx, span := tracing.Start(ctx, "ReportService.CategoryReport")
+	defer span.End()
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, -1)
+
+	var err error
+	if query.From != "" {
+		if from, err = time.Parse(reportDateLayout, query.From); err != nil {
+			return nil, ErrInvalidFromDate
+		}
+	}
+	if query.To != "" {
+		if to, err = time.Parse(reportDateLayout, query.To); err != nil {
+			return nil, ErrInvalidToDate
+		}
+	}
+	if to.Before(from) {
+		return nil, apperror.Validation("invalid_date_range", "to harus setelah from")
+	}
+
+	filter := repository.ReportFilter{UserID: userID, From: from, To: to.AddDate(0, 0, 1)}
+	res := response.CategoryReportResponse{
+		From:       from.Format(reportDateLayout),
+		To:         to.Format(reportDateLayout),
+		Categories: []response.CategoryReportItem{},
+	}
+
+	if query.WalletID != "" {
+		walletID, err := uuid.Parse(query.WalletID)
+		if err != nil {
+			return nil, ErrInvalidWalletID
+		}
+		if _, err := accessibleWallet(ctx, s.walletRepo, s.groupRepo, userID, walletID); err != nil {
+			return nil, err
+		}
+		filter.WalletID = &walletID
+		res.WalletID = walletID.String()
+	}
+
+	totals, err := s.repo.CategoryTotals(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range totals {
+		if t.Type == "EXPENSE" {
+			res.TotalExpense += t.Total
+		} else {
+			res.TotalIncome += t.Total
+		}
+		res.Categories = append(res.Categories, response.CategoryReportItem{
+			Category: response.CategoryResponse{
+				ID:   t.CategoryID.String(),
+				Name: t.Name,
+				Type: t.Type,
+			},
+			Total:            t.Total,
+			TransactionCount: t.TransactionCount,
+		})
+	}
+
+	return &res, nil
+}
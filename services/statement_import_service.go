@@ -0,0 +1,362 @@
+package services
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"cashflow_gin/statement"
+	"cashflow_gin/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrImportNotFound  = apperror.NotFound("import_not_found", "import not found")
+	ErrImportCommitted = apperror.Conflict("import_already_committed", "import sudah di-commit")
+)
+
+type StatementImportService interface {
+	Upload(ctx context.Context, userID, walletID uuid.UUID, format, fileName string, data []byte) (*response.StatementImportResponse, error)
+	GetMine(ctx context.Context, userID uuid.UUID) ([]response.StatementImportResponse, error)
+	GetByID(ctx context.Context, userID, importID uuid.UUID) (*response.StatementImportResponse, error)
+	ReviewRows(ctx context.Context, userID, importID uuid.UUID, input request.ReviewStatementRowsRequest) (*response.StatementImportResponse, error)
+	Commit(ctx context.Context, userID, importID uuid.UUID) (*response.StatementImportResponse, error)
+}
+
+type statementImportService struct {
+	repo         repository.StatementImportRepository
+	walletRepo   repository.WalletRepository
+	groupRepo    repository.GroupRepository
+	categoryRepo repository.CategoryRepository
+	transactions TransactionService
+	rules        *CategoryRuleEngine
+	payees       PayeeService
+}
+
+func NewStatementImportService(
+	r repository.StatementImportRepository,
+	wRepo repository.WalletRepository,
+	gRepo repository.GroupRepository,
+	cRepo repository.CategoryRepository,
+	transactions TransactionService,
+	rules *CategoryRuleEngine,
+	payees PayeeService,
+) StatementImportService {
+	return &statementImportService{
+		repo:         r,
+		walletRepo:   wRepo,
+		groupRepo:    gRepo,
+		categoryRepo: cRepo,
+		transactions: transactions,
+		rules:        rules,
+		payees:       payees,
+	}
+}
+
+func (s *statementImportService) Upload(ctx context.Context, userID, walletID uuid.UUID, format, fileName string, data []byte) (*response.StatementImportResponse, error) {
+	ctx, span := tracing.Start(ctx, "StatementImportService.Upload")
+	defer span.End()
+
+	if _, err := accessibleWallet(ctx, s.walletRepo, s.groupRepo, userID, walletID); err != nil {
+		return nil, err
+	}
+
+	format, rows, err := statement.Parse(strings.ToLower(format), fileName, data)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprints := make([]string, len(rows))
+	for i, row := range rows {
+		fingerprints[i] = statement.Fingerprint(row)
+	}
+	existing, err := s.repo.ExistingFingerprints(ctx, walletID, fingerprints)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.rules.ActiveRules(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	statementImport := models.StatementImport{
+		UserID:   userID,
+		WalletID: walletID,
+		Format:   format,
+		FileName: fileName,
+		Status:   models.StatementImportPending,
+	}
+	seen := make(map[string]bool)
+	for i, row := range rows {
+		fp := fingerprints[i]
+		// Duplikat = udah ada di wallet, atau muncul dua kali di file yang sama.
+		// Defaultnya di-skip, user masih bisa maksa import lewat review.
+		duplicate := existing[fp] || seen[fp]
+		seen[fp] = true
+
+		importRow := models.StatementImportRow{
+			Date:        row.Date,
+			Amount:      row.Amount,
+			Payee:       truncate(row.Payee, 255),
+			Memo:        row.Memo,
+			ExternalID:  truncate(row.ExternalID, 100),
+			Fingerprint: fp,
+			Duplicate:   duplicate,
+			Skip:        duplicate,
+		}
+
+		// Kategori awal dari rule user, masih bisa diganti pas review.
+		// Rule yang kategorinya gak cocok sama arah uang (income/expense) dilewat.
+		if rule := MatchRules(rules, RuleInput{Title: row.Payee, Description: row.Memo, Amount: row.Amount}); rule != nil &&
+			categoryFitsAmount(rule.Category, row.Amount) {
+			categoryID := rule.CategoryID
+			importRow.CategoryID = &categoryID
+			importRow.Tags = models.JoinTags(rule.TagList())
+		}
+
+		statementImport.Rows = append(statementImport.Rows, importRow)
+	}
+
+	if err := s.repo.Create(ctx, &statementImport); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, userID, statementImport.ID)
+}
+
+func (s *statementImportService) GetMine(ctx context.Context, userID uuid.UUID) ([]response.StatementImportResponse, error) {
+	ctx, span := tracing.Start(ctx, "StatementImportService.GetMine")
+	defer span.End()
+
+	imports, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []response.StatementImportResponse{}
+	for _, i := range imports {
+		res = append(res, toStatementImportResponse(i, false))
+	}
+	return res, nil
+}
+
+func (s *statementImportService) GetByID(ctx context.Context, userID, importID uuid.UUID) (*response.StatementImportResponse, error) {
+	ctx, span := tracing.Start(ctx, "StatementImportService.GetByID")
+	defer span.End()
+
+	statementImport, err := s.repo.FindByIDAndUserID(ctx, importID, userID)
+	if err != nil {
+		return nil, apperror.Replace(err, ErrImportNotFound)
+	}
+
+	res := toStatementImportResponse(*statementImport, true)
+	return &res, nil
+}
+
+func (s *statementImportService) ReviewRows(ctx context.Context, userID, importID uuid.UUID, input request.ReviewStatementRowsRequest) (*response.StatementImportResponse, error) {
+	ctx, span := tracing.Start(ctx, "StatementImportService.ReviewRows")
+	defer span.End()
+
+	statementImport, err := s.repo.FindByIDAndUserID(ctx, importID, userID)
+	if err != nil {
+		return nil, apperror.Replace(err, ErrImportNotFound)
+	}
+	if statementImport.Status != models.StatementImportPending {
+		return nil, ErrImportCommitted
+	}
+
+	rowsByID := make(map[string]*models.StatementImportRow)
+	for i := range statementImport.Rows {
+		rowsByID[statementImport.Rows[i].ID.String()] = &statementImport.Rows[i]
+	}
+
+	var updated []models.StatementImportRow
+	for _, review := range input.Rows {
+		row, ok := rowsByID[review.RowID]
+		if !ok {
+			return nil, apperror.NotFound("import_row_not_found", fmt.Sprintf("row %s tidak ada di import ini", review.RowID))
+		}
+		if review.CategoryName != "" {
+			category, err := s.categoryRepo.FindByName(ctx, review.CategoryName)
+			if err != nil {
+				return nil, apperror.Replace(err, ErrCategoryNotFound.WithMessage(fmt.Sprintf("category %s not found", review.CategoryName)))
+			}
+			row.CategoryID = &category.ID
+		}
+		if review.Skip != nil {
+			row.Skip = *review.Skip
+		}
+		updated = append(updated, *row)
+	}
+
+	if err := s.repo.UpdateRows(ctx, updated); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, userID, importID)
+}
+
+// Commit masukin baris yang gak di-skip jadi transaksi, lewat jalur update saldo wallet yang sama.
+func (s *statementImportService) Commit(ctx context.Context, userID, importID uuid.UUID) (*response.StatementImportResponse, error) {
+	ctx, span := tracing.Start(ctx, "StatementImportService.Commit")
+	defer span.End()
+
+	statementImport, err := s.repo.FindByIDAndUserID(ctx, importID, userID)
+	if err != nil {
+		return nil, apperror.Replace(err, ErrImportNotFound)
+	}
+	if statementImport.Status != models.StatementImportPending {
+		return nil, ErrImportCommitted
+	}
+
+	wallet, err := accessibleWallet(ctx, s.walletRepo, s.groupRepo, userID, statementImport.WalletID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cek ulang duplikat (siapa tau file yang sama udah di-commit dari import lain) dilakuin repo
+	// di dalam DB transaction yang sama dengan insert-nya
+	var items []repository.ImportCommitItem
+	// Payee mutasi ("STARBUCKS JKT") dicocokin ke payee/alias wallet ini, yang belum ada dibikin
+	payeeIDs := make(map[string]*uuid.UUID)
+	for i := range statementImport.Rows {
+		row := &statementImport.Rows[i]
+		if row.Skip {
+			continue
+		}
+		if row.Category == nil {
+			return nil, apperror.Validation("import_row_uncategorized", fmt.Sprintf("baris %s (%s, %.2f) belum punya kategori", row.Date.Format("2006-01-02"), row.Payee, row.Amount))
+		}
+		if !categoryFitsAmount(*row.Category, row.Amount) {
+			return nil, apperror.Validation("category_amount_mismatch", fmt.Sprintf("kategori %s (%s) tidak cocok dengan nominal %.2f di baris %s",
+				row.Category.Name, row.Category.Type, row.Amount, row.Date.Format("2006-01-02")))
+		}
+
+		title := row.Payee
+		if title == "" {
+			title = row.Memo
+		}
+		if title == "" {
+			title = "Import " + strings.ToUpper(statementImport.Format)
+		}
+
+		payeeKey := statement.NormalizePayee(row.Payee)
+		if _, ok := payeeIDs[payeeKey]; !ok && payeeKey != "" && s.payees != nil {
+			payee, err := s.payees.ResolveForWallet(ctx, userID, wallet, row.Payee)
+			if err != nil {
+				return nil, err
+			}
+			if payee != nil {
+				payeeIDs[payeeKey] = &payee.ID
+			}
+		}
+
+		items = append(items, repository.ImportCommitItem{Row: row, Transaction: models.Transaction{
+			UserID:      userID,
+			WalletID:    wallet.ID,
+			CategoryID:  *row.CategoryID,
+			PayeeID:     payeeIDs[payeeKey],
+			Title:       truncate(title, 255),
+			Amount:      row.Amount,
+			Description: row.Memo,
+			Date:        row.Date,
+			ExternalID:  row.ExternalID,
+			Fingerprint: row.Fingerprint,
+			Tags:        row.Tags,
+		}})
+	}
+
+	// Status pending -> committed, insert & saldo wallet satu DB transaction: commit barengan cuma satu yang lolos
+	created, err := s.repo.Commit(ctx, statementImport, wallet.ID, items)
+	if err != nil {
+		if errors.Is(err, repository.ErrImportNotPending) {
+			return nil, ErrImportCommitted
+		}
+		return nil, err
+	}
+	s.transactions.PublishBatchCreated(ctx, userID, wallet, created)
+	return s.GetByID(ctx, userID, importID)
+}
+
+// accessibleWallet: wallet group -> user harus member, wallet pribadi -> user harus pemiliknya.
+// Dipakai import & report (yang gak punya transactionService.authorizeWallet).
+func accessibleWallet(ctx context.Context, walletRepo repository.WalletRepository, groupRepo repository.GroupRepository, userID, walletID uuid.UUID) (models.Wallet, error) {
+	wallet, err := walletRepo.FindByID(ctx, walletID)
+	if err != nil {
+		return models.Wallet{}, apperror.Replace(err, ErrWalletNotFound)
+	}
+
+	if wallet.GroupID != nil {
+		isMember, err := groupRepo.IsGroupMember(ctx, *wallet.GroupID, userID)
+		if err != nil {
+			return models.Wallet{}, fmt.Errorf("failed to check group membership: %w", err)
+		}
+		if !isMember {
+			return models.Wallet{}, ErrNotGroupMember.WithMessage("unauthorized: user is not a member of the group wallet")
+		}
+		return wallet, nil
+	}
+
+	if wallet.UserID == nil || *wallet.UserID != userID {
+		return models.Wallet{}, ErrWalletForbidden
+	}
+	return wallet, nil
+}
+
+func toStatementImportResponse(i models.StatementImport, withRows bool) response.StatementImportResponse {
+	res := response.StatementImportResponse{
+		ID:        i.ID.String(),
+		WalletID:  i.WalletID.String(),
+		Format:    i.Format,
+		FileName:  i.FileName,
+		Status:    i.Status,
+		TotalRows: len(i.Rows),
+		CreatedAt: i.CreatedAt,
+	}
+
+	for _, row := range i.Rows {
+		if row.Duplicate {
+			res.Duplicates++
+		}
+		if !withRows {
+			continue
+		}
+
+		item := response.StatementImportRowResponse{
+			ID:         row.ID.String(),
+			Date:       row.Date,
+			Amount:     row.Amount,
+			Payee:      row.Payee,
+			Memo:       row.Memo,
+			ExternalID: row.ExternalID,
+			Duplicate:  row.Duplicate,
+			Skip:       row.Skip,
+			Tags:       models.SplitTags(row.Tags),
+		}
+		if row.Category != nil {
+			item.Category = &response.CategoryResponse{
+				ID:   row.Category.ID.String(),
+				Name: row.Category.Name,
+				Type: row.Category.Type,
+			}
+		}
+		if row.TransactionID != nil {
+			item.TransactionID = row.TransactionID.String()
+		}
+		res.Rows = append(res.Rows, item)
+	}
+	return res
+}
+
+func truncate(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
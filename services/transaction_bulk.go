@@ -0,0 +1,337 @@
+package services
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/events"
+	"cashflow_gin/metrics"
+	"cashflow_gin/models"
+	"cashflow_gin/tracing"
+	"context"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// Operasi bulk: tiap item dicek pakai aturan yang sama dengan endpoint satuan. Item yang gagal
+// dicatat di hasil, sisanya ditulis dalam SATU DB transaction dengan saldo wallet digeser
+// sebesar net delta per wallet. Kalau penulisan ke DB gagal, semuanya batal.
+
+func (s *transactionService) BulkCreate(ctx context.Context, userID uuid.UUID, input request.BulkCreateTransactionRequest) (*response.BulkTransactionResponse, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.BulkCreate")
+	defer span.End()
+
+	results := make([]response.BulkItemResult, len(input.Items))
+	wallets := make(map[uuid.UUID]models.Wallet)
+	walletErrs := make(map[uuid.UUID]error)
+
+	var (
+		transactions []models.Transaction
+		indexes      []int
+	)
+	for i, item := range input.Items {
+		results[i] = response.BulkItemResult{Index: i}
+
+		walletID, err := uuid.Parse(item.WalletID)
+		if err != nil {
+			results[i].Error = "invalid wallet id"
+			continue
+		}
+		if _, checked := wallets[walletID]; !checked && walletErrs[walletID] == nil {
+			wallet, err := s.authorizeWallet(ctx, userID, walletID)
+			if err != nil {
+				walletErrs[walletID] = err
+			} else {
+				wallets[walletID] = wallet
+			}
+		}
+		if err := walletErrs[walletID]; err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		payee, err := s.resolvePayee(ctx, userID, wallets[walletID], item.PayeeID, item.PayeeName)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		// Sama kayak Create: lines > category_name > default payee > rule > Uncategorized
+		var (
+			category *models.Category
+			ruleTags []string
+			lines    []models.TransactionLine
+		)
+		if len(item.Lines) > 0 {
+			var primary models.Category
+			lines, primary, _, err = s.buildLines(ctx, item.Lines, item.Amount)
+			category = &primary
+		} else {
+			category, ruleTags, err = s.resolveCategory(ctx, userID, item, payee)
+		}
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		t := models.Transaction{
+			UserID:      userID,
+			WalletID:    walletID,
+			CategoryID:  category.ID,
+			Category:    *category,
+			Title:       item.Title,
+			Amount:      signedAmount(*category, item.Amount),
+			Description: item.Description,
+			Date:        item.Date,
+			Tags:        models.JoinTags(item.Tags, ruleTags),
+			Lines:       lines,
+		}
+		if payee != nil {
+			t.PayeeID = &payee.ID
+			t.Payee = payee
+		}
+		transactions = append(transactions, t)
+		indexes = append(indexes, i)
+	}
+
+	if err := s.transactionRepo.CreateManyWithWalletUpdate(ctx, transactions); err != nil {
+		return nil, err
+	}
+	metrics.TransactionsCreatedTotal.Add(float64(len(transactions)), "bulk")
+
+	deltas := make(map[uuid.UUID]float64)
+	for k, t := range transactions {
+		res := toTransactionResponse(t)
+		results[indexes[k]].ID = res.ID
+		results[indexes[k]].Success = true
+		results[indexes[k]].Transaction = &res
+
+		deltas[t.WalletID] += t.Amount
+		s.publish(events.TransactionCreated, userID, wallets[t.WalletID], res)
+	}
+	for walletID, delta := range deltas {
+		s.publishBalanceChanged(ctx, userID, wallets[walletID], delta)
+	}
+
+	return summarizeBulk(results), nil
+}
+
+func (s *transactionService) BulkRecategorize(ctx context.Context, userID uuid.UUID, input request.BulkRecategorizeRequest) (*response.BulkTransactionResponse, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.BulkRecategorize")
+	defer span.End()
+
+	category, err := s.categoryRepo.FindByName(ctx, input.CategoryName)
+	if err != nil {
+		return nil, apperror.Replace(err, ErrCategoryNotFound)
+	}
+
+	results := make([]response.BulkItemResult, len(input.TransactionIDs))
+	owned, order, err := s.loadOwnedForBulk(ctx, userID, input.TransactionIDs, results)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []models.Transaction
+	var recategorized []int
+	deltas := make(map[uuid.UUID]float64)
+	for _, i := range order {
+		t := owned[i]
+		// Transaksi split kategorinya per line, gak bisa diganti sekaligus
+		if len(t.Lines) > 0 {
+			results[i].Error = "transaksi split: ubah kategori lewat lines"
+			continue
+		}
+		recategorized = append(recategorized, i)
+
+		// EXPENSE <-> INCOME: tanda nominal dibalik, saldo ikut digeser
+		newAmount := signedAmount(*category, t.Amount)
+		deltas[t.WalletID] += newAmount - t.Amount
+
+		t.CategoryID = category.ID
+		t.Category = *category
+		t.Amount = newAmount
+		owned[i] = t
+		changed = append(changed, t)
+	}
+
+	if err := s.UpdateBatch(ctx, userID, changed, deltas); err != nil {
+		return nil, err
+	}
+	markBulkSuccess(results, owned, recategorized)
+
+	return summarizeBulk(results), nil
+}
+
+func (s *transactionService) BulkMove(ctx context.Context, userID uuid.UUID, input request.BulkMoveRequest) (*response.BulkTransactionResponse, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.BulkMove")
+	defer span.End()
+
+	targetID, err := uuid.Parse(input.WalletID)
+	if err != nil {
+		return nil, ErrInvalidWalletID
+	}
+	target, err := s.authorizeWallet(ctx, userID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]response.BulkItemResult, len(input.TransactionIDs))
+	owned, order, err := s.loadOwnedForBulk(ctx, userID, input.TransactionIDs, results)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []models.Transaction
+	var moved []int
+	deltas := make(map[uuid.UUID]float64)
+	for _, i := range order {
+		t := owned[i]
+		if t.WalletID == target.ID {
+			moved = append(moved, i) // udah di wallet tujuan, tetap dihitung sukses
+			continue
+		}
+		// Payee pribadi gak boleh kebawa ke wallet group (dan sebaliknya), ganti payee-nya dulu
+		if t.Payee != nil && !payeeFitsWallet(*t.Payee, target) {
+			results[i].Error = ErrPayeeWalletScope.Message
+			continue
+		}
+		moved = append(moved, i)
+		deltas[t.WalletID] -= t.Amount
+		deltas[target.ID] += t.Amount
+
+		t.WalletID = target.ID
+		t.Wallet = target
+		owned[i] = t
+		changed = append(changed, t)
+	}
+
+	if err := s.UpdateBatch(ctx, userID, changed, deltas); err != nil {
+		return nil, err
+	}
+	markBulkSuccess(results, owned, moved)
+
+	return summarizeBulk(results), nil
+}
+
+func (s *transactionService) BulkDelete(ctx context.Context, userID uuid.UUID, input request.BulkTransactionIDsRequest) (*response.BulkTransactionResponse, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.BulkDelete")
+	defer span.End()
+
+	results := make([]response.BulkItemResult, len(input.TransactionIDs))
+	owned, order, err := s.loadOwnedForBulk(ctx, userID, input.TransactionIDs, results)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []models.Transaction
+	deltas := make(map[uuid.UUID]float64)
+	wallets := make(map[uuid.UUID]models.Wallet)
+	for _, i := range order {
+		t := owned[i]
+		deleted = append(deleted, t)
+		deltas[t.WalletID] -= t.Amount
+		wallets[t.WalletID] = t.Wallet
+	}
+
+	if err := s.transactionRepo.SoftDeleteManyWithWalletUpdate(ctx, deleted, deltas); err != nil {
+		return nil, err
+	}
+
+	for _, i := range order {
+		t := owned[i]
+		results[i].Success = true
+		s.publish(events.TransactionDeleted, userID, t.Wallet, map[string]string{"id": t.ID.String()})
+	}
+	for walletID, delta := range deltas {
+		s.publishBalanceChanged(ctx, userID, wallets[walletID], delta)
+	}
+
+	return summarizeBulk(results), nil
+}
+
+// loadOwnedForBulk ambil transaksi + cek kepemilikan per item (sama dengan update/delete satuan).
+// Balikin map index->transaksi yang lolos dan urutan index-nya biar hasilnya deterministik.
+func (s *transactionService) loadOwnedForBulk(ctx context.Context, userID uuid.UUID, rawIDs []string, results []response.BulkItemResult) (map[int]models.Transaction, []int, error) {
+	var ids []uuid.UUID
+	for _, raw := range rawIDs {
+		id, _ := uuid.Parse(raw) // format udah divalidasi binding
+		ids = append(ids, id)
+	}
+
+	found, err := s.transactionRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[uuid.UUID]models.Transaction)
+	for _, t := range found {
+		byID[t.ID] = t
+	}
+
+	owned := make(map[int]models.Transaction)
+	var order []int
+	seen := make(map[uuid.UUID]bool)
+	for i, id := range ids {
+		results[i] = response.BulkItemResult{Index: i, ID: id.String()}
+		t, ok := byID[id]
+		switch {
+		case !ok:
+			results[i].Error = "transaction not found"
+		case seen[id]:
+			results[i].Error = "duplicate transaction id in request"
+		case t.UserID != userID:
+			results[i].Error = "unauthorized: transaction does not belong to user"
+		default:
+			owned[i] = t
+			order = append(order, i)
+		}
+		seen[id] = true
+	}
+	return owned, order, nil
+}
+
+func markBulkSuccess(results []response.BulkItemResult, owned map[int]models.Transaction, order []int) {
+	for _, i := range order {
+		res := toTransactionResponse(owned[i])
+		results[i].Success = true
+		results[i].Transaction = &res
+	}
+}
+
+func summarizeBulk(results []response.BulkItemResult) *response.BulkTransactionResponse {
+	summary := response.BulkTransactionResponse{Total: len(results), Items: results}
+	for _, r := range results {
+		if r.Success {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+	}
+	return &summary
+}
+
+// signedAmount: EXPENSE selalu negatif, INCOME selalu positif.
+func signedAmount(category models.Category, amount float64) float64 {
+	if category.Type == "EXPENSE" {
+		return -math.Abs(amount)
+	}
+	return math.Abs(amount)
+}
+
+func toTransactionResponse(t models.Transaction) response.TransactionResponse {
+	return response.TransactionResponse{
+		ID:          t.ID.String(),
+		Title:       t.Title,
+		Amount:      t.Amount,
+		Description: t.Description,
+		Date:        t.Date,
+		Tags:        models.SplitTags(t.Tags),
+		Version:     t.Version,
+		Lines:       toTransactionLineResponses(t.Lines),
+		Payee:       toPayeeSummary(t.Payee),
+		Category: response.CategoryResponse{
+			Name: t.Category.Name,
+			Type: t.Category.Type,
+		},
+	}
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/models"
+	"context"
+	"fmt"
+	"math"
+)
+
+// Toleransi selisih pembulatan antara total lines dan amount transaksi (decimal 2 digit)
+const lineAmountTolerance = 0.005
+
+// buildLines ubah input lines jadi models.TransactionLine (amount udah bertanda).
+// amount = total yang diharapkan (positif), 0 = ikut total lines.
+// Return lines, kategori utama (line terbesar, dipakai jadi Transaction.CategoryID) & total (positif).
+func (s *transactionService) buildLines(ctx context.Context, inputs []request.TransactionLineRequest, amount float64) ([]models.TransactionLine, models.Category, float64, error) {
+	var (
+		lines   []models.TransactionLine
+		primary models.Category
+		largest float64
+		total   float64
+	)
+
+	for i, in := range inputs {
+		category, err := s.categoryRepo.FindByName(ctx, in.CategoryName)
+		if err != nil {
+			return nil, models.Category{}, 0, apperror.Replace(err, ErrCategoryNotFound.WithMessage(fmt.Sprintf("lines[%d]: category %q not found", i, in.CategoryName)))
+		}
+		// Satu transaksi cuma bisa keluar ATAU masuk, jadi semua line harus satu tipe
+		if i > 0 && category.Type != lines[0].Category.Type {
+			return nil, models.Category{}, 0, apperror.Validation("mixed_line_types", "semua lines harus kategori dengan tipe yang sama (EXPENSE atau INCOME)")
+		}
+
+		lines = append(lines, models.TransactionLine{
+			CategoryID:  category.ID,
+			Amount:      signedAmount(*category, in.Amount),
+			Description: in.Description,
+			Category:    *category,
+		})
+		total += math.Abs(in.Amount)
+
+		if math.Abs(in.Amount) > largest {
+			largest = math.Abs(in.Amount)
+			primary = *category
+		}
+	}
+
+	total = math.Round(total*100) / 100
+	if amount != 0 && math.Abs(total-math.Abs(amount)) > lineAmountTolerance {
+		return nil, models.Category{}, 0, apperror.Validation("lines_total_mismatch", fmt.Sprintf("total lines (%.2f) harus sama dengan amount transaksi (%.2f)", total, math.Abs(amount)))
+	}
+
+	return lines, primary, total, nil
+}
+
+func toTransactionLineResponses(lines []models.TransactionLine) []response.TransactionLineResponse {
+	if len(lines) == 0 {
+		return nil
+	}
+	res := make([]response.TransactionLineResponse, 0, len(lines))
+	for _, l := range lines {
+		res = append(res, response.TransactionLineResponse{
+			ID:          l.ID.String(),
+			Amount:      l.Amount,
+			Description: l.Description,
+			Category: response.CategoryResponse{
+				ID:   l.Category.ID.String(),
+				Name: l.Category.Name,
+				Type: l.Category.Type,
+			},
+		})
+	}
+	return res
+}
@@ -1,11 +1,16 @@
 package services
 
 import (
+	"cashflow_gin/apperror"
 	"cashflow_gin/dto/request"
 	"cashflow_gin/dto/response"
+	"cashflow_gin/events"
+	"cashflow_gin/logging"
+	"cashflow_gin/metrics"
 	"cashflow_gin/models"
 	"cashflow_gin/repository"
-	"errors"
+	"cashflow_gin/tracing"
+	"context"
 	"fmt"
 	"math"
 
@@ -13,11 +18,24 @@ import (
 )
 
 type TransactionService interface {
-	Create(userID uuid.UUID, input request.CreateTransactionRequest) (response.TransactionResponse, error)
-	GetAll() (*[]response.TransactionResponse, error)
-	GetTransactionByID(userID uuid.UUID, transactionID uuid.UUID) (response.TransactionResponse, error)
-	UpdateTransaction(userID, transactionID uuid.UUID, input request.UpdateTransactionRequest) (response.TransactionResponse, error)
-	SoftDeleteTransaction(userID, transactionID, walletID uuid.UUID) error
+	Create(ctx context.Context, userID uuid.UUID, input request.CreateTransactionRequest) (response.TransactionResponse, error)
+	GetAll(ctx context.Context) (*[]response.TransactionResponse, error)
+	GetTransactionByID(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) (response.TransactionResponse, error)
+	// expectedVersion = version dari header If-Match
+	UpdateTransaction(ctx context.Context, userID, transactionID uuid.UUID, expectedVersion int, input request.UpdateTransactionRequest) (response.TransactionResponse, error)
+	SoftDeleteTransaction(ctx context.Context, userID, transactionID, walletID uuid.UUID, expectedVersion int) error
+
+	// PublishBatchCreated dipakai fitur lain yang nyimpen transaksi sendiri dalam DB transaction-nya
+	// (import mutasi): tinggal catat metric & publish event transaksi + saldo wallet.
+	PublishBatchCreated(ctx context.Context, userID uuid.UUID, wallet models.Wallet, transactions []models.Transaction)
+	// UpdateBatch simpen transaksi yang udah diubah + geser saldo per wallet (walletDeltas).
+	// Transaction.Wallet harus udah ke-preload buat publish event.
+	UpdateBatch(ctx context.Context, userID uuid.UUID, transactions []models.Transaction, walletDeltas map[uuid.UUID]float64) error
+
+	BulkCreate(ctx context.Context, userID uuid.UUID, input request.BulkCreateTransactionRequest) (*response.BulkTransactionResponse, error)
+	BulkRecategorize(ctx context.Context, userID uuid.UUID, input request.BulkRecategorizeRequest) (*response.BulkTransactionResponse, error)
+	BulkMove(ctx context.Context, userID uuid.UUID, input request.BulkMoveRequest) (*response.BulkTransactionResponse, error)
+	BulkDelete(ctx context.Context, userID uuid.UUID, input request.BulkTransactionIDsRequest) (*response.BulkTransactionResponse, error)
 }
 
 type transactionService struct {
@@ -26,6 +44,10 @@ type transactionService struct {
 	userRepo        repository.UserRepository
 	groupRepo       repository.GroupRepository
 	walletRepo      repository.WalletRepository
+	bus             events.Bus
+	notifier        NotificationPublisher
+	rules           *CategoryRuleEngine
+	payees          PayeeService
 }
 
 // Constructor minta 2 Repository sekarang
@@ -35,6 +57,10 @@ func NewTransactionService(
 	uRepo repository.UserRepository,
 	gRepo repository.GroupRepository,
 	wRepo repository.WalletRepository,
+	bus events.Bus,
+	notifier NotificationPublisher,
+	rules *CategoryRuleEngine,
+	payees PayeeService,
 ) TransactionService {
 	return &transactionService{
 		transactionRepo: tRepo,
@@ -42,50 +68,56 @@ func NewTransactionService(
 		userRepo:        uRepo,
 		groupRepo:       gRepo,
 		walletRepo:      wRepo,
+		bus:             bus,
+		notifier:        notifier,
+		rules:           rules,
+		payees:          payees,
 	}
 }
 
-func (s *transactionService) Create(userID uuid.UUID, input request.CreateTransactionRequest) (response.TransactionResponse, error) {
+func (s *transactionService) Create(ctx context.Context, userID uuid.UUID, input request.CreateTransactionRequest) (response.TransactionResponse, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.Create")
+	defer span.End()
+
 	// 1. Parsing UUID
 	walletUUID, err := uuid.Parse(input.WalletID)
 	if err != nil {
-		return response.TransactionResponse{}, errors.New("invalid wallet id")
+		return response.TransactionResponse{}, ErrInvalidWalletID
 	}
 
-	wallet, err := s.walletRepo.FindByID(walletUUID)
+	wallet, err := s.authorizeWallet(ctx, userID, walletUUID)
 	if err != nil {
-		return response.TransactionResponse{}, errors.New("wallet not found")
+		return response.TransactionResponse{}, err
 	}
 
-	// Personal Wallet
-	// Cek Apakah user id yang mengirim = user id yang punya wallet
-	isGroupWallet, err := s.groupRepo.IsGroupWallet(walletUUID)
-	fmt.Println("Is Group Wallet?", isGroupWallet)
+	// Payee: payee_id eksplisit, atau payee_name dicocokin ke nama/alias (belum ada = dibikin)
+	payee, err := s.resolvePayee(ctx, userID, wallet, input.PayeeID, input.PayeeName)
 	if err != nil {
-		return response.TransactionResponse{}, errors.New("failed to check wallet type")
+		return response.TransactionResponse{}, err
 	}
-	if isGroupWallet {
-		isGroupMember, err := s.groupRepo.IsGroupMember(*wallet.GroupID, userID)
-		fmt.Println("Is Group Member?", isGroupMember)
+
+	// 2. BUSSINESS LOGIC: Cek Category Type (Income/Expense)
+	// Kalau category_name kosong, kategori (dan tag tambahan) ditentukan default payee / rule user.
+	// Kalau split (lines diisi), kategori transaksi = kategori line terbesar.
+	var (
+		category *models.Category
+		ruleTags []string
+		lines    []models.TransactionLine
+	)
+	if len(input.Lines) > 0 {
+		var primary models.Category
+		lines, primary, _, err = s.buildLines(ctx, input.Lines, input.Amount)
 		if err != nil {
-			return response.TransactionResponse{}, errors.New("failed to check group membership")
-		}
-		if !isGroupMember {
-			return response.TransactionResponse{}, errors.New("unauthorized: user is not a member of the group wallet, cannot create personal transaction")
+			return response.TransactionResponse{}, err
 		}
+		category = &primary
 	} else {
-		reqUser := s.transactionRepo.IsOwner(userID, input.WalletID)
-		if !reqUser {
-			return response.TransactionResponse{}, errors.New("unauthorized: wallet does not belong to user")
+		category, ruleTags, err = s.resolveCategory(ctx, userID, input, payee)
+		if err != nil {
+			return response.TransactionResponse{}, err
 		}
 	}
 
-	// 2. BUSSINESS LOGIC: Cek Category Type (Income/Expense)
-	category, err := s.categoryRepo.FindByName(input.CategoryName)
-	if err != nil {
-		return response.TransactionResponse{}, errors.New("category not found")
-	}
-
 	finalAmount := input.Amount
 
 	// Logic Matematika:
@@ -107,13 +139,20 @@ func (s *transactionService) Create(userID uuid.UUID, input request.CreateTransa
 		Amount:      finalAmount, // Nilai sudah otomatis +/- sesuai kategori
 		Description: input.Description,
 		Date:        input.Date,
+		Tags:        models.JoinTags(input.Tags, ruleTags),
+		Lines:       lines,
+	}
+	if payee != nil {
+		transaction.PayeeID = &payee.ID
+		transaction.Payee = payee
 	}
 
 	// 4. Save Atomic (Transaction + Wallet Update)
-	err = s.transactionRepo.CreateWithWalletUpdate(&transaction)
+	err = s.transactionRepo.CreateWithWalletUpdate(ctx, &transaction)
 	if err != nil {
 		return response.TransactionResponse{}, err
 	}
+	metrics.TransactionsCreatedTotal.Inc("single")
 
 	res := response.TransactionResponse{
 		ID:          transaction.ID.String(),
@@ -121,18 +160,31 @@ func (s *transactionService) Create(userID uuid.UUID, input request.CreateTransa
 		Amount:      transaction.Amount,
 		Description: transaction.Description,
 		Date:        transaction.Date,
+		Tags:        models.SplitTags(transaction.Tags),
+		Version:     transaction.Version,
+		Lines:       toTransactionLineResponses(transaction.Lines),
+		Payee:       toPayeeSummary(transaction.Payee),
 		Category: response.CategoryResponse{
 			Name: category.Name,
 			Type: category.Type,
 		},
 	}
+
+	// 5. Publish event (udah ke-commit)
+	s.publish(events.TransactionCreated, userID, wallet, res)
+	s.publishBalanceChanged(ctx, userID, wallet, transaction.Amount)
+	s.notifyGroupTransaction(ctx, userID, wallet, res)
+
 	return res, nil
 }
 
-func (s *transactionService) GetAll() (*[]response.TransactionResponse, error) {
+func (s *transactionService) GetAll(ctx context.Context) (*[]response.TransactionResponse, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.GetAll")
+	defer span.End()
+
 	// 1. Panggil Repository (Filter by UserID biar gak bocor data orang lain)
 
-	transactions, err := s.transactionRepo.FindAll()
+	transactions, err := s.transactionRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -148,6 +200,10 @@ func (s *transactionService) GetAll() (*[]response.TransactionResponse, error) {
 			Amount:      t.Amount,
 			Description: t.Description,
 			Date:        t.Date,
+			Tags:        models.SplitTags(t.Tags),
+			Version:     t.Version,
+			Lines:       toTransactionLineResponses(t.Lines),
+			Payee:       toPayeeSummary(t.Payee),
 			Category: response.CategoryResponse{
 				Name: t.Category.Name,
 				Type: t.Category.Type,
@@ -159,23 +215,26 @@ func (s *transactionService) GetAll() (*[]response.TransactionResponse, error) {
 	return &transactionResponses, nil
 }
 
-func (s *transactionService) GetTransactionByID(userID uuid.UUID, transactionID uuid.UUID) (response.TransactionResponse, error) {
-	user, err := s.userRepo.FindMyProfile(userID)
+func (s *transactionService) GetTransactionByID(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) (response.TransactionResponse, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.GetTransactionByID")
+	defer span.End()
+
+	user, err := s.userRepo.FindMyProfile(ctx, userID)
 	if err != nil {
-		return response.TransactionResponse{}, err
+		return response.TransactionResponse{}, apperror.Replace(err, ErrUserNotFound)
 	}
 
 	if user.ID != userID {
-		return response.TransactionResponse{}, errors.New("unauthorized: user not found")
+		return response.TransactionResponse{}, ErrUserNotFound
 	}
 
-	transaction, err := s.transactionRepo.FindByID(transactionID)
+	transaction, err := s.transactionRepo.FindByID(ctx, transactionID)
 	if err != nil {
-		return response.TransactionResponse{}, err
+		return response.TransactionResponse{}, apperror.Replace(err, ErrTransactionNotFound)
 	}
 
 	if transaction.UserID != user.ID {
-		return response.TransactionResponse{}, errors.New("unauthorized: transaction does not belong to user")
+		return response.TransactionResponse{}, ErrTransactionForbidden
 	}
 	res := response.TransactionResponse{
 		ID:          transaction.ID.String(),
@@ -183,6 +242,10 @@ func (s *transactionService) GetTransactionByID(userID uuid.UUID, transactionID
 		Amount:      transaction.Amount,
 		Description: transaction.Description,
 		Date:        transaction.Date,
+		Tags:        models.SplitTags(transaction.Tags),
+		Version:     transaction.Version,
+		Lines:       toTransactionLineResponses(transaction.Lines),
+		Payee:       toPayeeSummary(transaction.Payee),
 		Category: response.CategoryResponse{
 			Name: transaction.Category.Name,
 			Type: transaction.Category.Type,
@@ -191,21 +254,42 @@ func (s *transactionService) GetTransactionByID(userID uuid.UUID, transactionID
 	return res, nil
 }
 
-func (s *transactionService) UpdateTransaction(userID, transactionID uuid.UUID, input request.UpdateTransactionRequest) (response.TransactionResponse, error) {
-	reqUser, err := s.userRepo.FindMyProfile(userID)
+func (s *transactionService) UpdateTransaction(ctx context.Context, userID, transactionID uuid.UUID, expectedVersion int, input request.UpdateTransactionRequest) (response.TransactionResponse, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.UpdateTransaction")
+	defer span.End()
+
+	reqUser, err := s.userRepo.FindMyProfile(ctx, userID)
 	if err != nil {
-		return response.TransactionResponse{}, errors.New("unauthorized: user not found")
+		return response.TransactionResponse{}, apperror.Replace(err, ErrUserNotFound)
 	}
 	// Cari Transaksi dan validasi
-	transaction, err := s.transactionRepo.FindByID(transactionID)
+	transaction, err := s.transactionRepo.FindByID(ctx, transactionID)
 	if err != nil {
-		return response.TransactionResponse{}, errors.New("transaction not found")
+		return response.TransactionResponse{}, apperror.Replace(err, ErrTransactionNotFound)
 	}
 	if transaction.UserID != reqUser.ID {
-		return response.TransactionResponse{}, errors.New("unauthorized: transaction does not belong to user")
+		return response.TransactionResponse{}, ErrTransactionForbidden
+	}
+	if err := checkVersion(transaction.Version, expectedVersion); err != nil {
+		return response.TransactionResponse{}, err
 	}
 
 	oldAmount := transaction.Amount
+	oldWallet := transaction.Wallet
+	currentLines := transaction.Lines
+
+	// Transaksi split: kategori & amount ikut lines, jadi harus diubah lewat lines
+	if input.Lines == nil {
+		transaction.Lines = nil // nil = lines gak disentuh repo
+		if len(currentLines) > 0 && input.CategoryID != "" {
+			return response.TransactionResponse{}, apperror.Validation("split_category_via_lines", "transaksi split: ubah kategori lewat lines")
+		}
+		if len(currentLines) > 0 && input.Amount != 0 && math.Abs(input.Amount-math.Abs(oldAmount)) > lineAmountTolerance {
+			return response.TransactionResponse{}, apperror.Validation("split_amount_via_lines", "transaksi split: amount harus diubah bareng lines")
+		}
+	} else if len(*input.Lines) > 0 && input.CategoryID != "" {
+		return response.TransactionResponse{}, apperror.Validation("category_with_lines", "category_id gak bisa dipakai bareng lines")
+	}
 
 	// Update fields
 	if input.Title != "" {
@@ -218,35 +302,90 @@ func (s *transactionService) UpdateTransaction(userID, transactionID uuid.UUID,
 		transaction.Date = input.Date
 	}
 
-	var deltaAmount float64
-	if input.Amount != 0 {
-		var newAmount float64
-
-		if transaction.Category.Type == "EXPENSE" {
-			newAmount = -math.Abs(input.Amount)
-		} else {
-			newAmount = math.Abs(input.Amount)
+	// Pindah wallet: wallet tujuan dicek sama seperti pas create
+	moved := false
+	if input.WalletID != "" {
+		targetID, err := uuid.Parse(input.WalletID)
+		if err != nil {
+			return response.TransactionResponse{}, ErrInvalidWalletID
 		}
+		if targetID != transaction.WalletID {
+			target, err := s.authorizeWallet(ctx, userID, targetID)
+			if err != nil {
+				return response.TransactionResponse{}, err
+			}
+			transaction.WalletID = target.ID
+			transaction.Wallet = target
+			moved = true
+		}
+	}
 
-		transaction.Amount = newAmount
-		deltaAmount = newAmount - oldAmount
-
+	// Ganti kategori: kalau tipenya beda (EXPENSE <-> INCOME) tanda nominal dibalik di bawah.
+	// Dicek setelah pindah wallet biar scope-nya ngikut wallet tujuan.
+	if input.CategoryID != "" {
+		categoryID, err := uuid.Parse(input.CategoryID)
+		if err != nil {
+			return response.TransactionResponse{}, ErrInvalidCategoryID
+		}
+		category, err := s.categoryRepo.FindByID(ctx, categoryID)
+		if err != nil {
+			return response.TransactionResponse{}, apperror.Replace(err, ErrCategoryNotFound)
+		}
+		// Kategori orang lain / group lain dianggap gak ada, biar gak bocor ID-nya valid
+		if !categoryFitsWallet(*category, transaction.Wallet, userID) {
+			return response.TransactionResponse{}, ErrCategoryNotFound
+		}
+		transaction.CategoryID = category.ID
+		transaction.Category = *category
 	}
 
-	if deltaAmount != 0 {
-		fmt.Println("Update Transaction With Wallet Ballance")
-		err := s.transactionRepo.UpdateTransactionWithWalletBallance(transaction, deltaAmount)
+	if input.PayeeID != "" {
+		payee, err := s.resolvePayee(ctx, userID, transaction.Wallet, input.PayeeID, "")
 		if err != nil {
 			return response.TransactionResponse{}, err
 		}
-	} else {
-		fmt.Println("Update Transaction ONLY")
+		transaction.PayeeID = &payee.ID
+		transaction.Payee = payee
+	} else if moved && transaction.Payee != nil && !payeeFitsWallet(*transaction.Payee, transaction.Wallet) {
+		// Pindah wallet tanpa ganti payee: payee lama harus tetap satu scope sama wallet tujuan
+		return response.TransactionResponse{}, ErrPayeeWalletScope
+	}
 
-		// Simpan perubahan
-		err = s.transactionRepo.UpdateTransaction(transaction)
+	amount := oldAmount
+	if input.Amount != 0 {
+		amount = input.Amount
+	}
+	if input.Lines != nil && len(*input.Lines) > 0 {
+		lines, primary, total, err := s.buildLines(ctx, *input.Lines, input.Amount)
 		if err != nil {
 			return response.TransactionResponse{}, err
 		}
+		transaction.Lines = lines
+		transaction.CategoryID = primary.ID
+		transaction.Category = primary
+		amount = total
+	} else if input.Lines != nil {
+		transaction.Lines = []models.TransactionLine{} // [] = hapus split
+	}
+	transaction.Amount = signedAmount(transaction.Category, amount)
+	deltaAmount := transaction.Amount - oldAmount
+
+	switch {
+	case moved:
+		err = s.transactionRepo.MoveTransactionWithWalletUpdate(ctx, transaction, oldWallet.ID, oldAmount)
+	case deltaAmount != 0:
+		logging.FromContext(ctx).DebugContext(ctx, "update transaction with wallet balance",
+			"transaction_id", transaction.ID, "delta_amount", deltaAmount)
+		err = s.transactionRepo.UpdateTransactionWithWalletBallance(ctx, transaction, deltaAmount)
+	default:
+		// Simpan perubahan
+		err = s.transactionRepo.UpdateTransaction(ctx, transaction)
+	}
+	if err != nil {
+		return response.TransactionResponse{}, err
+	}
+	if transaction.Lines == nil {
+		transaction.Lines = currentLines
 	}
 
 	res := response.TransactionResponse{
@@ -255,38 +394,301 @@ func (s *transactionService) UpdateTransaction(userID, transactionID uuid.UUID,
 		Amount:      transaction.Amount,
 		Description: transaction.Description,
 		Date:        transaction.Date,
+		Tags:        models.SplitTags(transaction.Tags),
+		Version:     transaction.Version,
+		Lines:       toTransactionLineResponses(transaction.Lines),
+		Payee:       toPayeeSummary(transaction.Payee),
 		Category: response.CategoryResponse{
 			Name: transaction.Category.Name,
 			Type: transaction.Category.Type,
 		},
 	}
 
+	s.publish(events.TransactionUpdated, userID, transaction.Wallet, res)
+	if moved {
+		s.publishBalanceChanged(ctx, userID, oldWallet, -oldAmount)
+		s.publishBalanceChanged(ctx, userID, transaction.Wallet, transaction.Amount)
+	} else if deltaAmount != 0 {
+		s.publishBalanceChanged(ctx, userID, transaction.Wallet, deltaAmount)
+	}
+
 	return res, nil
 }
 
-func (s *transactionService) SoftDeleteTransaction(userID, transactionID, walletID uuid.UUID) error {
-	reqUser, err := s.userRepo.FindMyProfile(userID)
+func (s *transactionService) SoftDeleteTransaction(ctx context.Context, userID, transactionID, walletID uuid.UUID, expectedVersion int) error {
+	ctx, span := tracing.Start(ctx, "TransactionService.SoftDeleteTransaction")
+	defer span.End()
+
+	reqUser, err := s.userRepo.FindMyProfile(ctx, userID)
 	if err != nil {
-		return errors.New("unauthorized: user not found")
+		return apperror.Replace(err, ErrUserNotFound)
 	}
 	// Cari Transaksi dan validasi
-	transaction, err := s.transactionRepo.FindByID(transactionID)
+	transaction, err := s.transactionRepo.FindByID(ctx, transactionID)
 	if err != nil {
-		return errors.New("transaction not found")
+		return apperror.Replace(err, ErrTransactionNotFound)
 	}
 	if transaction.UserID != reqUser.ID {
-		return errors.New("unauthorized: transaction does not belong to user")
+		return ErrTransactionForbidden
+	}
+	// Wallet di path harus wallet transaksinya, biar balance wallet lain gak ikut kepotong
+	if transaction.WalletID != walletID {
+		return apperror.NotFound("transaction_not_in_wallet", "transaction does not belong to this wallet")
+	}
+	if err := checkVersion(transaction.Version, expectedVersion); err != nil {
+		return err
 	}
 
 	// Logic Matematika:
 	// Untuk Soft Delete, kita harus ngurangin balance wallet dengan amount transaksi yang mau dihapus
 	deltaAmount := -transaction.Amount
-	fmt.Println("Delta Amount", deltaAmount)
+	logging.FromContext(ctx).DebugContext(ctx, "soft delete transaction",
+		"transaction_id", transactionID, "delta_amount", deltaAmount)
 
-	err = s.transactionRepo.SoftDeleteTransaction(transactionID, deltaAmount, walletID)
+	err = s.transactionRepo.SoftDeleteTransaction(ctx, transactionID, transaction.Version, deltaAmount, transaction.WalletID)
 	if err != nil {
 		return err
 	}
 
+	s.publish(events.TransactionDeleted, userID, transaction.Wallet, map[string]string{"id": transaction.ID.String()})
+	s.publishBalanceChanged(ctx, userID, transaction.Wallet, deltaAmount)
+
+	return nil
+}
+
+func (s *transactionService) PublishBatchCreated(ctx context.Context, userID uuid.UUID, wallet models.Wallet, transactions []models.Transaction) {
+	ctx, span := tracing.Start(ctx, "TransactionService.PublishBatchCreated")
+	defer span.End()
+
+	if len(transactions) == 0 {
+		return
+	}
+
+	var delta float64
+	for _, t := range transactions {
+		delta += t.Amount
+	}
+	metrics.TransactionsCreatedTotal.Add(float64(len(transactions)), "import")
+
+	for _, t := range transactions {
+		s.publish(events.TransactionCreated, userID, wallet, response.TransactionResponse{
+			ID:          t.ID.String(),
+			Title:       t.Title,
+			Amount:      t.Amount,
+			Description: t.Description,
+			Date:        t.Date,
+			Tags:        models.SplitTags(t.Tags),
+			Version:     t.Version,
+			Lines:       toTransactionLineResponses(t.Lines),
+			Payee:       toPayeeSummary(t.Payee),
+		})
+	}
+	s.publishBalanceChanged(ctx, userID, wallet, delta)
+}
+
+func (s *transactionService) UpdateBatch(ctx context.Context, userID uuid.UUID, transactions []models.Transaction, walletDeltas map[uuid.UUID]float64) error {
+	ctx, span := tracing.Start(ctx, "TransactionService.UpdateBatch")
+	defer span.End()
+
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	if err := s.transactionRepo.UpdateManyWithWalletUpdate(ctx, transactions, walletDeltas); err != nil {
+		return err
+	}
+
+	for _, t := range transactions {
+		s.publish(events.TransactionUpdated, userID, t.Wallet, toTransactionResponse(t))
+	}
+	for walletID, delta := range walletDeltas {
+		if delta == 0 {
+			continue
+		}
+		wallet, err := s.walletRepo.FindByID(ctx, walletID)
+		if err != nil {
+			continue
+		}
+		s.publishBalanceChanged(ctx, userID, wallet, delta)
+	}
+
 	return nil
 }
+
+// authorizeWallet: wallet group -> user harus member, wallet pribadi -> user harus pemiliknya.
+// Dipakai create, bulk, dan pindah wallet biar aturannya sama persis.
+func (s *transactionService) authorizeWallet(ctx context.Context, userID, walletID uuid.UUID) (models.Wallet, error) {
+	wallet, err := s.walletRepo.FindByID(ctx, walletID)
+	if err != nil {
+		return models.Wallet{}, apperror.Replace(err, ErrWalletNotFound)
+	}
+
+	// Personal Wallet
+	// Cek Apakah user id yang mengirim = user id yang punya wallet
+	isGroupWallet, err := s.groupRepo.IsGroupWallet(ctx, walletID)
+	if err != nil {
+		return models.Wallet{}, fmt.Errorf("failed to check wallet type: %w", err)
+	}
+	if isGroupWallet {
+		isGroupMember, err := s.groupRepo.IsGroupMember(ctx, *wallet.GroupID, userID)
+		if err != nil {
+			return models.Wallet{}, fmt.Errorf("failed to check group membership: %w", err)
+		}
+		if !isGroupMember {
+			return models.Wallet{}, ErrNotGroupMember.WithMessage("unauthorized: user is not a member of the group wallet, cannot create personal transaction")
+		}
+	} else {
+		reqUser := s.transactionRepo.IsOwner(ctx, userID, walletID.String())
+		if !reqUser {
+			return models.Wallet{}, ErrWalletForbidden
+		}
+	}
+
+	return wallet, nil
+}
+
+// resolveCategory: category_name eksplisit > default kategori payee > rule pertama yang cocok > "Uncategorized".
+func (s *transactionService) resolveCategory(ctx context.Context, userID uuid.UUID, input request.CreateTransactionRequest, payee *models.Payee) (*models.Category, []string, error) {
+	if input.CategoryName != "" {
+		category, err := s.categoryRepo.FindByName(ctx, input.CategoryName)
+		if err != nil {
+			return nil, nil, apperror.Replace(err, ErrCategoryNotFound)
+		}
+		return category, nil, nil
+	}
+
+	if payee != nil && payee.DefaultCategory != nil {
+		return payee.DefaultCategory, nil, nil
+	}
+
+	if s.rules != nil {
+		rule, err := s.rules.Match(ctx, userID, RuleInput{
+			Title:       input.Title,
+			Description: input.Description,
+			Amount:      input.Amount,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if rule != nil {
+			category := rule.Category
+			return &category, rule.TagList(), nil
+		}
+	}
+
+	category, err := s.categoryRepo.FindOrCreate(ctx, models.UncategorizedCategoryName, models.UncategorizedCategoryType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve default category: %w", err)
+	}
+	return category, nil, nil
+}
+
+// resolvePayee: payee_id harus payee yang boleh diakses user DAN satu scope sama wallet-nya,
+// payee_name di-resolve di scope wallet.
+func (s *transactionService) resolvePayee(ctx context.Context, userID uuid.UUID, wallet models.Wallet, payeeID, payeeName string) (*models.Payee, error) {
+	if s.payees == nil {
+		return nil, nil
+	}
+	if payeeID != "" {
+		id, err := uuid.Parse(payeeID)
+		if err != nil {
+			return nil, ErrInvalidPayeeID
+		}
+		payee, err := s.payees.FindAccessible(ctx, userID, id)
+		if err != nil {
+			return nil, err
+		}
+		// Payee pribadi gak boleh nempel di transaksi wallet group (dan sebaliknya), kalau gak transaksinya
+		// ikut kelihatan di riwayat payee scope lain
+		if !payeeFitsWallet(*payee, wallet) {
+			return nil, ErrPayeeWalletScope
+		}
+		return payee, nil
+	}
+	if payeeName != "" {
+		return s.payees.ResolveForWallet(ctx, userID, wallet, payeeName)
+	}
+	return nil, nil
+}
+
+// payeeFitsWallet: wallet group -> payee group yang sama, wallet pribadi -> payee pribadi pemilik wallet.
+func payeeFitsWallet(payee models.Payee, wallet models.Wallet) bool {
+	if wallet.GroupID != nil {
+		return payee.GroupID != nil && *payee.GroupID == *wallet.GroupID
+	}
+	return payee.GroupID == nil && wallet.UserID != nil && payee.UserID == *wallet.UserID
+}
+
+// categoryFitsWallet: kategori bawaan (tanpa pemilik) boleh di mana aja, kategori group cuma buat wallet
+// group itu, kategori pribadi cuma punya user sendiri.
+func categoryFitsWallet(category models.Category, wallet models.Wallet, userID uuid.UUID) bool {
+	if category.GroupID != nil {
+		return wallet.GroupID != nil && *wallet.GroupID == *category.GroupID
+	}
+	return category.UserID == uuid.Nil || category.UserID == userID
+}
+
+// publish kirim event ke bus. Dipanggil SETELAH DB commit biar subscriber gak nerima data yang di-rollback.
+func (s *transactionService) publish(eventType string, actorID uuid.UUID, wallet models.Wallet, data interface{}) {
+	if s.bus == nil {
+		return
+	}
+
+	walletID := wallet.ID
+	event := events.Event{
+		Type:     eventType,
+		ActorID:  actorID,
+		WalletID: &walletID,
+		GroupID:  wallet.GroupID,
+		Data:     data,
+	}
+	if wallet.GroupID == nil {
+		event.WalletUserID = wallet.UserID
+	}
+	s.bus.Publish(event)
+}
+
+func (s *transactionService) publishBalanceChanged(ctx context.Context, actorID uuid.UUID, wallet models.Wallet, delta float64) {
+	balance, err := s.walletRepo.FindBalance(ctx, wallet.ID)
+	if err != nil {
+		return
+	}
+	s.publish(events.WalletBalanceChanged, actorID, wallet, events.WalletBalancePayload{
+		WalletID: wallet.ID,
+		Delta:    delta,
+		Balance:  balance,
+	})
+}
+
+// notifyGroupTransaction kabarin member group lain kalau ada transaksi baru di wallet group.
+func (s *transactionService) notifyGroupTransaction(ctx context.Context, actorID uuid.UUID, wallet models.Wallet, trx response.TransactionResponse) {
+	if s.notifier == nil || wallet.GroupID == nil {
+		return
+	}
+
+	group, err := s.groupRepo.GetGroupByID(ctx, *wallet.GroupID)
+	if err != nil {
+		return
+	}
+
+	actorName := "Seseorang"
+	var recipients []uuid.UUID
+	for _, m := range group.Members {
+		if m.UserID == actorID {
+			actorName = m.User.Username
+			continue
+		}
+		recipients = append(recipients, m.UserID)
+	}
+
+	s.notifier.Notify(ctx, recipients, NotificationMessage{
+		Type:  models.NotificationGroupTransactionAdded,
+		Title: fmt.Sprintf("Transaksi baru di group %s", group.Name),
+		Body:  fmt.Sprintf("%s menambahkan %s (%.2f)", actorName, trx.Title, trx.Amount),
+		Data: map[string]string{
+			"group_id":       group.ID.String(),
+			"wallet_id":      wallet.ID.String(),
+			"transaction_id": trx.ID,
+		},
+	})
+}
@@ -1,27 +1,50 @@
 package services
 
 import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
 	"cashflow_gin/dto/response"
+	"cashflow_gin/models"
 	"cashflow_gin/repository"
+	"cashflow_gin/tracing"
+	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type UserService interface {
-	FindAllUser() (*[]response.UserResponse, error)
-	GetMyProfile(id uuid.UUID) (*response.UserResponse, error)
+	FindAllUser(ctx context.Context) (*[]response.UserResponse, error)
+	GetMyProfile(ctx context.Context, id uuid.UUID) (*response.UserResponse, error)
+
+	UpdateProfile(ctx context.Context, id uuid.UUID, input request.UpdateProfileRequest) (*response.UserResponse, error)
+	ChangePassword(ctx context.Context, id uuid.UUID, input request.ChangePasswordRequest) error
+	DeleteAccount(ctx context.Context, id uuid.UUID, input request.DeleteAccountRequest) error
+	ExportData(ctx context.Context, id uuid.UUID) (*response.UserExportResponse, error)
 }
 
+var (
+	ErrEmailTaken    = apperror.Conflict("email_taken", "email sudah dipakai user lain")
+	ErrUsernameTaken = apperror.Conflict("username_taken", "username sudah dipakai user lain")
+)
+
 type userService struct {
-	repo repository.UserRepository
+	repo         repository.UserRepository
+	categoryRepo repository.CategoryRepository
 }
 
-func NewUserService(r repository.UserRepository) UserService {
-	return &userService{repo: r}
+func NewUserService(r repository.UserRepository, cRepo repository.CategoryRepository) UserService {
+	return &userService{repo: r, categoryRepo: cRepo}
 }
 
-func (s *userService) FindAllUser() (*[]response.UserResponse, error) {
-	users, err := s.repo.FindAllUser()
+func (s *userService) FindAllUser(ctx context.Context) (*[]response.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.FindAllUser")
+	defer span.End()
+
+	users, err := s.repo.FindAllUser(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -47,8 +70,11 @@ func (s *userService) FindAllUser() (*[]response.UserResponse, error) {
 	return &userRes, nil
 }
 
-func (s *userService) GetMyProfile(id uuid.UUID) (*response.UserResponse, error) {
-	user, err := s.repo.FindMyProfile(id)
+func (s *userService) GetMyProfile(ctx context.Context, id uuid.UUID) (*response.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetMyProfile")
+	defer span.End()
+
+	user, err := s.repo.FindMyProfile(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -73,3 +99,220 @@ func (s *userService) GetMyProfile(id uuid.UUID) (*response.UserResponse, error)
 	}
 	return UserRes, nil
 }
+
+func (s *userService) UpdateProfile(ctx context.Context, id uuid.UUID, input request.UpdateProfileRequest) (*response.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.UpdateProfile")
+	defer span.End()
+
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperror.Replace(err, ErrUserNotFound)
+	}
+
+	username := strings.TrimSpace(input.Username)
+	email := strings.TrimSpace(input.Email)
+	if username == "" && email == "" {
+		return nil, apperror.Validation("username_or_email_required", "username atau email wajib diisi")
+	}
+
+	// Cek unik per field (bukan email OR username sekaligus, yang bisa aja ketemu user sendiri duluan),
+	// biar client tau field mana yang bentrok
+	if email != "" {
+		taken, err := s.repo.IsEmailTaken(ctx, email, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, ErrEmailTaken
+		}
+		user.Email = email
+	}
+	if username != "" {
+		taken, err := s.repo.IsUsernameTaken(ctx, username, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, ErrUsernameTaken
+		}
+		user.Username = username
+	}
+
+	if err := s.repo.UpdateProfile(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return s.GetMyProfile(ctx, user.ID)
+}
+
+func (s *userService) ChangePassword(ctx context.Context, id uuid.UUID, input request.ChangePasswordRequest) error {
+	ctx, span := tracing.Start(ctx, "UserService.ChangePassword")
+	defer span.End()
+
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return apperror.Replace(err, ErrUserNotFound)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.CurrentPassword)); err != nil {
+		return ErrWrongPassword.WithMessage("password lama salah")
+	}
+	if input.CurrentPassword == input.NewPassword {
+		return apperror.Validation("password_unchanged", "password baru tidak boleh sama dengan password lama")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %w", err)
+	}
+
+	return s.repo.UpdatePassword(ctx, user.ID, string(hashedPassword))
+}
+
+func (s *userService) DeleteAccount(ctx context.Context, id uuid.UUID, input request.DeleteAccountRequest) error {
+	ctx, span := tracing.Start(ctx, "UserService.DeleteAccount")
+	defer span.End()
+
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return apperror.Replace(err, ErrUserNotFound)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
+		return ErrWrongPassword.WithMessage("password salah")
+	}
+
+	ownedGroups, err := s.repo.FindOwnedGroups(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	ownerTransfers := make(map[uuid.UUID]uuid.UUID)
+	if len(ownedGroups) > 0 {
+		if !input.TransferOwnership {
+			return apperror.Conflict("owns_groups", fmt.Sprintf("user masih memiliki %d group, pindahkan kepemilikan dulu (transfer_ownership=true)", len(ownedGroups)))
+		}
+
+		for _, group := range ownedGroups {
+			newOwnerID, ok := pickNewOwner(group, user.ID)
+			if !ok {
+				return apperror.Conflict("no_successor_owner", fmt.Sprintf("group %s tidak punya member lain untuk dijadikan owner, hapus group dulu", group.Name))
+			}
+			ownerTransfers[group.ID] = newOwnerID
+		}
+	}
+
+	return s.repo.DeleteAccount(ctx, user.ID, ownerTransfers)
+}
+
+// pickNewOwner milih owner pengganti: ADMIN lain dulu, kalau gak ada ambil member paling lama.
+// Members diasumsikan udah urut created_at ASC dari repo.
+func pickNewOwner(group models.Group, currentOwnerID uuid.UUID) (uuid.UUID, bool) {
+	var fallback *uuid.UUID
+	for _, m := range group.Members {
+		if m.UserID == currentOwnerID {
+			continue
+		}
+		if m.MembersRole == models.GroupAdmin {
+			return m.UserID, true
+		}
+		if fallback == nil {
+			id := m.UserID
+			fallback = &id
+		}
+	}
+	if fallback == nil {
+		return uuid.Nil, false
+	}
+	return *fallback, true
+}
+
+func (s *userService) ExportData(ctx context.Context, id uuid.UUID) (*response.UserExportResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.ExportData")
+	defer span.End()
+
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperror.Replace(err, ErrUserNotFound)
+	}
+
+	wallets, err := s.repo.FindPersonalWallets(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := s.repo.FindTransactionsByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	memberships, err := s.repo.FindMemberships(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Slice kosong biar hasil export "[]" bukan "null"
+	res := response.UserExportResponse{
+		ExportedAt: time.Now(),
+		Profile: response.UserResponse{
+			ID:       user.ID.String(),
+			Username: user.Username,
+			Email:    user.Email,
+			UserRole: user.UserRole.String(),
+		},
+		Wallets:      []response.WalletResponse{},
+		Transactions: []response.TransactionResponse{},
+		Categories:   []response.CategoryResponse{},
+		Groups:       []response.GroupMembershipResponse{},
+	}
+
+	for _, w := range wallets {
+		res.Wallets = append(res.Wallets, response.WalletResponse{
+			ID:      w.ID,
+			Name:    w.Name,
+			Balance: w.Balance,
+		})
+	}
+
+	for _, t := range transactions {
+		res.Transactions = append(res.Transactions, response.TransactionResponse{
+			ID:          t.ID.String(),
+			Title:       t.Title,
+			Amount:      t.Amount,
+			Description: t.Description,
+			Date:        t.Date,
+			Category: response.CategoryResponse{
+				ID:   t.CategoryID.String(),
+				Name: t.Category.Name,
+				Type: t.Category.Type,
+			},
+		})
+	}
+
+	for _, c := range *categories {
+		cat := response.CategoryResponse{
+			ID:     c.ID.String(),
+			UserID: c.UserID.String(),
+			Name:   c.Name,
+			Type:   c.Type,
+		}
+		if c.GroupID != nil {
+			cat.GroupID = c.GroupID.String()
+		}
+		res.Categories = append(res.Categories, cat)
+	}
+
+	for _, m := range memberships {
+		res.Groups = append(res.Groups, response.GroupMembershipResponse{
+			GroupID:   m.GroupID.String(),
+			GroupName: m.Group.Name,
+			Role:      m.MembersRole.String(),
+			IsOwner:   m.Group.OwnerID == user.ID,
+			JoinedAt:  m.CreatedAt,
+		})
+	}
+
+	return &res, nil
+}
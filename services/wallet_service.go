@@ -3,14 +3,15 @@ package services
 import (
 	"cashflow_gin/dto/response"
 	"cashflow_gin/repository"
-	"errors"
+	"cashflow_gin/tracing"
+	"context"
 
 	"github.com/google/uuid"
 )
 
 type WalletService interface {
-	GetAll() ([]response.WalletResponse, error)
-	GetWalletByID(userID, walletID, groupID uuid.UUID) (response.WalletResponse, error)
+	GetAll(ctx context.Context) ([]response.WalletResponse, error)
+	GetWalletByID(ctx context.Context, userID, walletID, groupID uuid.UUID) (response.WalletResponse, error)
 }
 
 type walletService struct {
@@ -23,9 +24,12 @@ func NewWalletService(wRepo repository.WalletRepository, gRepo repository.GroupR
 	return &walletService{walletRepo: wRepo, groupRepo: gRepo}
 }
 
-func (s *walletService) GetAll() ([]response.WalletResponse, error) {
+func (s *walletService) GetAll(ctx context.Context) ([]response.WalletResponse, error) {
+	ctx, span := tracing.Start(ctx, "WalletService.GetAll")
+	defer span.End()
+
 	// Implementasi untuk mendapatkan semua wallet
-	wallet, err := s.walletRepo.FindAll()
+	wallet, err := s.walletRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -62,23 +66,26 @@ func (s *walletService) GetAll() ([]response.WalletResponse, error) {
 	return responses, nil
 }
 
-func (s *walletService) GetWalletByID(userID, walletID, groupID uuid.UUID) (response.WalletResponse, error) {
+func (s *walletService) GetWalletByID(ctx context.Context, userID, walletID, groupID uuid.UUID) (response.WalletResponse, error) {
+	ctx, span := tracing.Start(ctx, "WalletService.GetWalletByID")
+	defer span.End()
+
 	// Cek Apakah Group id nya Nil atau bukan
 	if groupID != uuid.Nil {
-		isMember, err := s.groupRepo.IsGroupMember(groupID, userID)
+		isMember, err := s.groupRepo.IsGroupMember(ctx, groupID, userID)
 		if err != nil || !isMember {
 			return response.WalletResponse{}, err
 		}
 	}
 
-	wallet, err := s.walletRepo.FindByID(walletID)
+	wallet, err := s.walletRepo.FindByID(ctx, walletID)
 	if err != nil {
 		return response.WalletResponse{}, err
 	}
 
 	// cek apakah si wallet itu milik user atau grup yang dia ikuti
 	if wallet.UserID != &userID || (groupID != uuid.Nil && wallet.GroupID != &groupID) {
-		return response.WalletResponse{}, errors.New("unauthorized: wallet does not belong to user")
+		return response.WalletResponse{}, ErrWalletForbidden
 	}
 
 	var transactions []response.TransactionResponse
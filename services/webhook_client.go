@@ -0,0 +1,106 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var errWebhookRedirect = errors.New("webhook: redirect tidak diikuti")
+
+// webhookBlockedPrefixes = range IP yang gak boleh jadi tujuan webhook (jaringan internal, metadata cloud, dll).
+// Loopback, private, link-local, multicast & unspecified dicek lewat method netip.Addr di isBlockedWebhookAddr.
+var webhookBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),  // CGNAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved + broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, bisa nembus ke IPv4 internal
+	netip.MustParsePrefix("64:ff9b:1::/48"), // NAT64 lokal
+	netip.MustParsePrefix("2002::/16"),      // 6to4, sama
+}
+
+// isBlockedWebhookAddr: true kalau IP ini gak boleh dihubungi webhook.
+func isBlockedWebhookAddr(addr netip.Addr) bool {
+	addr = addr.Unmap() // ::ffff:127.0.0.1 = 127.0.0.1
+	if !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || // termasuk 169.254.169.254 (metadata cloud)
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() {
+		return true
+	}
+	for _, prefix := range webhookBlockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateWebhookHost nolak host yang udah jelas internal pas webhook dibikin (IP literal / localhost),
+// biar user langsung dapet error. Hostname biasa baru ketahuan IP-nya pas dikirim (webhookDialControl).
+func validateWebhookHost(host string) error {
+	if host == "" {
+		return errors.New("host kosong")
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("host %s tidak diizinkan", host)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && isBlockedWebhookAddr(addr) {
+		return fmt.Errorf("alamat %s tidak diizinkan", host)
+	}
+	return nil
+}
+
+// webhookDialControl dipanggil setelah DNS di-resolve, tepat sebelum connect. Ngecek di sini (bukan cuma
+// pas webhook dibikin) biar gak bisa diakalin DNS rebinding: yang dicek IP yang beneran dihubungi.
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if isBlockedWebhookAddr(addr) {
+		return fmt.Errorf("webhook: alamat %s tidak diizinkan", addr)
+	}
+	return nil
+}
+
+// newWebhookClient = http.Client buat ngirim webhook: cuma boleh ke IP publik, gak lewat proxy env
+// (proxy-nya sendiri bisa aja di jaringan internal), dan redirect gak diikuti (tujuan redirect gak
+// dicek ulang dan bisa dipakai buat lompat ke alamat internal).
+func newWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: webhookDialControl,
+	}
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return errWebhookRedirect
+		},
+	}
+}
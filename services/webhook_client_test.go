@@ -0,0 +1,106 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsBlockedWebhookAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		blocked bool
+	}{
+		{"127.0.0.1", true},          // loopback
+		{"127.10.20.30", true},       // loopback
+		{"::1", true},                // loopback IPv6
+		{"::ffff:127.0.0.1", true},   // IPv4-mapped loopback
+		{"10.1.2.3", true},           // private
+		{"172.16.0.1", true},         // private
+		{"192.168.1.1", true},        // private
+		{"fd12:3456::1", true},       // ULA
+		{"169.254.169.254", true},    // metadata cloud (link-local)
+		{"fe80::1", true},            // link-local IPv6
+		{"100.64.0.1", true},         // CGNAT
+		{"100.127.255.254", true},    // CGNAT
+		{"0.0.0.0", true},            // unspecified
+		{"::", true},                 // unspecified IPv6
+		{"0.1.2.3", true},            // "this network"
+		{"224.0.0.1", true},          // multicast
+		{"255.255.255.255", true},    // broadcast
+		{"64:ff9b::a9fe:a9fe", true}, // NAT64 ke 169.254.169.254
+		{"8.8.8.8", false},
+		{"1.1.1.1", false},
+		{"100.128.0.1", false}, // pas di luar CGNAT
+		{"2606:4700:4700::1111", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := isBlockedWebhookAddr(netip.MustParseAddr(tt.addr)); got != tt.blocked {
+				t.Errorf("isBlockedWebhookAddr(%s) = %v, want %v", tt.addr, got, tt.blocked)
+			}
+		})
+	}
+}
+
+func TestValidateWebhookHost(t *testing.T) {
+	tests := []struct {
+		host  string
+		valid bool
+	}{
+		{"example.com", true},
+		{"hooks.example.com", true},
+		{"8.8.8.8", true},
+		{"", false},
+		{"localhost", false},
+		{"api.localhost", false},
+		{"127.0.0.1", false},
+		{"169.254.169.254", false},
+		{"10.0.0.5", false},
+		{"::1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			err := validateWebhookHost(tt.host)
+			if (err == nil) != tt.valid {
+				t.Errorf("validateWebhookHost(%q) error = %v, valid want %v", tt.host, err, tt.valid)
+			}
+		})
+	}
+}
+
+// Dicek pas connect, jadi hostname yang resolve ke loopback juga ketahan (bukan cuma IP literal).
+func TestWebhookClientBlocksLoopbackAtDial(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	client := newWebhookClient(2 * time.Second)
+	targets := []string{srv.URL, strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)}
+	for _, target := range targets {
+		res, err := client.Post(target, "application/json", strings.NewReader("{}"))
+		if err == nil {
+			res.Body.Close()
+			t.Fatalf("POST %s: expected dial to be blocked", target)
+		}
+		if !strings.Contains(err.Error(), "tidak diizinkan") {
+			t.Errorf("POST %s: unexpected error %v", target, err)
+		}
+	}
+	if called {
+		t.Error("server loopback sempat kena request")
+	}
+}
+
+func TestWebhookClientDoesNotFollowRedirects(t *testing.T) {
+	client := newWebhookClient(time.Second)
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/hook", nil)
+	if err := client.CheckRedirect(req, []*http.Request{req}); err == nil {
+		t.Error("CheckRedirect harus nolak redirect")
+	}
+}
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"bytes"
+	"cashflow_gin/events"
+	"cashflow_gin/logging"
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"cashflow_gin/tracing"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	webhookMaxAttempts  = 6
+	webhookBaseBackoff  = 2 * time.Second // 2s, 4s, 8s, 16s, 32s
+	webhookTimeout      = 10 * time.Second
+	webhookMaxDrain     = 64 << 10 // body response dibuang (gak disimpen), cukup dibaca sedikit biar koneksi bisa dipakai ulang
+	webhookQueueSize    = 1000
+	webhookPingEventKey = "ping"
+)
+
+type webhookJob struct {
+	subscription models.WebhookSubscription
+	eventID      uuid.UUID
+	eventType    string
+	payload      []byte
+	attempt      int
+}
+
+// WebhookDispatcher ngirim event ke URL subscriber secara async (worker pool + retry exponential backoff).
+// Tiap percobaan dicatat di tabel webhook_deliveries.
+type WebhookDispatcher struct {
+	repo      repository.WebhookRepository
+	groupRepo repository.GroupRepository
+	client    *http.Client
+
+	events chan events.Event
+	jobs   chan webhookJob
+	quit   chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+
+	// Retry yang lagi nunggu backoff (time.AfterFunc). Pas Stop timer-nya dibatalin & job-nya dikirim langsung
+	retryMu  sync.Mutex
+	retries  map[*time.Timer]webhookJob
+	retryWG  sync.WaitGroup // callback timer yang udah jalan
+	leftover []webhookJob   // job dari callback yang gak kebagian antrian karena keburu Stop
+	stopping bool
+}
+
+func NewWebhookDispatcher(repo repository.WebhookRepository, groupRepo repository.GroupRepository, workers int) *WebhookDispatcher {
+	d := &WebhookDispatcher{
+		repo:      repo,
+		groupRepo: groupRepo,
+		client:    newWebhookClient(webhookTimeout),
+		events:    make(chan events.Event, webhookQueueSize),
+		jobs:      make(chan webhookJob, webhookQueueSize),
+		quit:      make(chan struct{}),
+		retries:   make(map[*time.Timer]webhookJob),
+	}
+
+	for i := 0; i < workers; i++ {
+		d.wg.Add(1)
+		go d.worker()
+	}
+	return d
+}
+
+// HandleEvent dipasang ke events.Bus. Gak boleh nge-block request, jadi kalau antrian penuh event di-drop.
+func (d *WebhookDispatcher) HandleEvent(event events.Event) {
+	select {
+	case d.events <- event:
+	default:
+		slog.Warn("webhook: queue full, dropping event", "event_id", event.ID, "event_type", event.Type)
+	}
+}
+
+// Stop nunggu worker selesai kirim yang lagi jalan, lalu ngosongin sisanya secara synchronous: event yang
+// masih antri, dan retry yang belum jatuh tempo dikirim langsung (1x, tanpa retry lagi). Kalau ctx keburu
+// habis, sisanya dibuang & dicatat di log.
+func (d *WebhookDispatcher) Stop(ctx context.Context) {
+	d.once.Do(func() {
+		close(d.quit)
+		d.wg.Wait()
+
+		d.retryMu.Lock()
+		d.stopping = true
+		var pending []webhookJob
+		for timer, job := range d.retries {
+			if timer.Stop() {
+				pending = append(pending, job)
+			}
+		}
+		d.retries = nil
+		d.retryMu.Unlock()
+		// Callback yang udah keburu jalan naruh job-nya ke d.jobs atau d.leftover
+		d.retryWG.Wait()
+		pending = append(pending, d.leftover...)
+
+		logCtx := logging.WithLogger(ctx, slog.Default().With("component", "webhook"))
+		for {
+			if ctx.Err() != nil {
+				logging.FromContext(logCtx).WarnContext(logCtx, "webhook: shutdown timeout, dropping pending deliveries",
+					"retries", len(pending), "jobs", len(d.jobs), "events", len(d.events))
+				return
+			}
+			select {
+			case event := <-d.events:
+				d.fanOut(logCtx, event)
+			case job := <-d.jobs:
+				d.deliver(logCtx, job)
+			default:
+				if len(pending) == 0 {
+					return
+				}
+				d.deliver(logCtx, pending[0])
+				pending = pending[1:]
+			}
+		}
+	})
+}
+
+// Ping kirim 1x (tanpa retry) secara synchronous, dipakai endpoint test.
+func (d *WebhookDispatcher) Ping(ctx context.Context, subscription models.WebhookSubscription) (*models.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookDispatcher.Ping")
+	defer span.End()
+
+	event := events.Event{
+		ID:         uuid.New(),
+		Type:       webhookPingEventKey,
+		OccurredAt: time.Now(),
+		ActorID:    subscription.UserID,
+		GroupID:    subscription.GroupID,
+		Data:       map[string]string{"message": "pong"},
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := d.send(ctx, webhookJob{
+		subscription: subscription,
+		eventID:      event.ID,
+		eventType:    event.Type,
+		payload:      payload,
+		attempt:      1,
+	})
+	if err := d.repo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (d *WebhookDispatcher) worker() {
+	defer d.wg.Done()
+	// Worker jalan di luar request, jadi pakai context sendiri (bukan context request yang keburu selesai)
+	ctx := logging.WithLogger(context.Background(), slog.Default().With("component", "webhook"))
+	for {
+		select {
+		case <-d.quit:
+			return
+		case event := <-d.events:
+			d.fanOut(ctx, event)
+		case job := <-d.jobs:
+			d.deliver(ctx, job)
+		}
+	}
+}
+
+// fanOut cari subscription yang cocok buat event ini lalu kirim ke masing-masing.
+func (d *WebhookDispatcher) fanOut(ctx context.Context, event events.Event) {
+	subscriptions, err := d.repo.FindActiveByOwner(ctx, event.WalletUserID, event.GroupID)
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "webhook: failed to load subscriptions", "event_id", event.ID, "error", err)
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "webhook: failed to marshal event", "event_id", event.ID, "error", err)
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		if !subscription.HasEvent(event.Type) {
+			continue
+		}
+		// Webhook group: yang bikin harus masih member, kalau udah dikeluarin event group gak dikirim lagi
+		if subscription.GroupID != nil {
+			isMember, err := d.groupRepo.IsGroupMember(ctx, *subscription.GroupID, subscription.UserID)
+			if err != nil {
+				logging.FromContext(ctx).ErrorContext(ctx, "webhook: failed to check group membership",
+					"subscription_id", subscription.ID, "error", err)
+				continue
+			}
+			if !isMember {
+				continue
+			}
+		}
+		d.deliver(ctx, webhookJob{
+			subscription: subscription,
+			eventID:      event.ID,
+			eventType:    event.Type,
+			payload:      payload,
+			attempt:      1,
+		})
+	}
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, job webhookJob) {
+	delivery := d.send(ctx, job)
+	if err := d.repo.CreateDelivery(ctx, delivery); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "webhook: failed to save delivery log",
+			"subscription_id", job.subscription.ID, "event_id", job.eventID, "error", err)
+	}
+
+	if delivery.Success || job.attempt >= webhookMaxAttempts {
+		return
+	}
+
+	// Retry pakai exponential backoff, dijadwalin tanpa nahan worker
+	next := job
+	next.attempt++
+	d.scheduleRetry(ctx, next, webhookBaseBackoff*time.Duration(1<<(job.attempt-1)))
+}
+
+func (d *WebhookDispatcher) scheduleRetry(ctx context.Context, job webhookJob, backoff time.Duration) {
+	d.retryMu.Lock()
+	defer d.retryMu.Unlock()
+	if d.stopping {
+		// Lagi ngosongin antrian pas Stop, gak nunggu backoff lagi
+		logging.FromContext(ctx).WarnContext(ctx, "webhook: shutting down, retry dropped",
+			"subscription_id", job.subscription.ID, "event_id", job.eventID, "attempt", job.attempt)
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(backoff, func() {
+		d.retryMu.Lock()
+		if _, ok := d.retries[timer]; !ok {
+			// Udah diambil Stop
+			d.retryMu.Unlock()
+			return
+		}
+		delete(d.retries, timer)
+		d.retryWG.Add(1)
+		d.retryMu.Unlock()
+		defer d.retryWG.Done()
+
+		select {
+		case d.jobs <- job:
+		case <-d.quit:
+			d.retryMu.Lock()
+			d.leftover = append(d.leftover, job)
+			d.retryMu.Unlock()
+		}
+	})
+	d.retries[timer] = job
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, job webhookJob) *models.WebhookDelivery {
+	delivery := &models.WebhookDelivery{
+		SubscriptionID: job.subscription.ID,
+		EventType:      job.eventType,
+		Payload:        string(job.payload),
+		EventID:        job.eventID,
+		Attempt:        job.attempt,
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.subscription.URL, bytes.NewReader(job.payload))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Cashflow-Webhook/1.0")
+	req.Header.Set("X-Cashflow-Event", job.eventType)
+	req.Header.Set("X-Cashflow-Delivery", job.eventID.String())
+	req.Header.Set("X-Cashflow-Timestamp", timestamp)
+	req.Header.Set("X-Cashflow-Signature", "sha256="+SignWebhookPayload(job.subscription.Secret, timestamp, job.payload))
+
+	start := time.Now()
+	res, err := d.client.Do(req)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	defer res.Body.Close()
+
+	// Body response sengaja gak disimpen/ditampilin: isinya dari server orang lain, cuma status code yang dicatat
+	io.Copy(io.Discard, io.LimitReader(res.Body, webhookMaxDrain))
+	delivery.StatusCode = res.StatusCode
+	delivery.Success = res.StatusCode >= 200 && res.StatusCode < 300
+	return delivery
+}
+
+// SignWebhookPayload = HMAC-SHA256(secret, "<timestamp>.<body>") dalam hex.
+// Receiver wajib hitung ulang & bandingin sama header X-Cashflow-Signature (dan cek timestamp biar gak di-replay).
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
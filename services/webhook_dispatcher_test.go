@@ -0,0 +1,64 @@
+package services
+
+import (
+	"cashflow_gin/models"
+	"cashflow_gin/repository/memory"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Retry yang masih nunggu backoff pas shutdown harus langsung dikirim, bukan dibuang.
+func TestWebhookDispatcherStopDeliversPendingRetries(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	repos := memory.NewRepositories()
+	d := NewWebhookDispatcher(repos.Webhook, repos.Group, 1)
+	d.client = srv.Client() // client asli nolak loopback
+
+	subscription := models.WebhookSubscription{URL: srv.URL, Secret: "secret"}
+	subscription.ID = uuid.New()
+	d.deliver(context.Background(), webhookJob{
+		subscription: subscription,
+		eventID:      uuid.New(),
+		eventType:    "transaction.created",
+		payload:      []byte(`{}`),
+		attempt:      1,
+	})
+	if got := hits.Load(); got != 1 {
+		t.Fatalf("hits = %d, want 1", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	d.Stop(ctx)
+
+	if got := hits.Load(); got != 2 {
+		t.Fatalf("hits after Stop = %d, want 2 (retry dikirim pas Stop)", got)
+	}
+	if elapsed := time.Since(start); elapsed >= webhookBaseBackoff {
+		t.Errorf("Stop nunggu backoff (%s), harusnya retry langsung dikirim", elapsed)
+	}
+
+	deliveries, err := repos.Webhook.FindDeliveries(context.Background(), subscription.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("deliveries = %d, want 2", len(deliveries))
+	}
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"cashflow_gin/apperror"
+	"cashflow_gin/dto/request"
+	"cashflow_gin/dto/response"
+	"cashflow_gin/events"
+	"cashflow_gin/models"
+	"cashflow_gin/repository"
+	"cashflow_gin/tracing"
+	"cashflow_gin/utils"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const webhookDeliveryLogLimit = 50
+
+var ErrWebhookNotFound = apperror.NotFound("webhook_not_found", "webhook not found")
+
+type WebhookService interface {
+	Create(ctx context.Context, userID uuid.UUID, input request.CreateWebhookRequest) (*response.CreatedWebhookResponse, error)
+	GetMine(ctx context.Context, userID uuid.UUID) ([]response.WebhookResponse, error)
+	Delete(ctx context.Context, userID, webhookID uuid.UUID) error
+	GetDeliveries(ctx context.Context, userID, webhookID uuid.UUID) ([]response.WebhookDeliveryResponse, error)
+	Test(ctx context.Context, userID, webhookID uuid.UUID) (*response.WebhookDeliveryResponse, error)
+}
+
+type webhookService struct {
+	repo       repository.WebhookRepository
+	groupRepo  repository.GroupRepository
+	dispatcher *WebhookDispatcher
+}
+
+func NewWebhookService(r repository.WebhookRepository, gRepo repository.GroupRepository, dispatcher *WebhookDispatcher) WebhookService {
+	return &webhookService{repo: r, groupRepo: gRepo, dispatcher: dispatcher}
+}
+
+func (s *webhookService) Create(ctx context.Context, userID uuid.UUID, input request.CreateWebhookRequest) (*response.CreatedWebhookResponse, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Create")
+	defer span.End()
+
+	parsed, err := url.Parse(input.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, apperror.Validation("invalid_webhook_url", "url webhook harus http atau https")
+	}
+	// Alamat internal (localhost, IP private, metadata cloud) ditolak. Hostname dicek lagi pas kirim
+	if err := validateWebhookHost(strings.ToLower(parsed.Hostname())); err != nil {
+		return nil, apperror.Validation("invalid_webhook_url", "url webhook harus alamat publik: "+err.Error())
+	}
+
+	seen := make(map[string]bool)
+	var eventTypes []string
+	for _, e := range input.Events {
+		if !events.IsValidType(e) {
+			return nil, apperror.Validation("unknown_event_type", fmt.Sprintf("event %s tidak dikenal, pilihan: %s", e, strings.Join(events.Types, ", ")))
+		}
+		if !seen[e] {
+			seen[e] = true
+			eventTypes = append(eventTypes, e)
+		}
+	}
+
+	subscription := models.WebhookSubscription{
+		UserID: userID,
+		URL:    input.URL,
+		Events: strings.Join(eventTypes, ","),
+		Active: true,
+	}
+
+	// Webhook level group cuma boleh dibikin member group tsb
+	if input.GroupID != "" {
+		groupID, err := uuid.Parse(input.GroupID)
+		if err != nil {
+			return nil, ErrInvalidGroupID
+		}
+		isMember, err := s.groupRepo.IsGroupMember(ctx, groupID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check group membership: %w", err)
+		}
+		if !isMember {
+			return nil, ErrNotGroupMember
+		}
+		subscription.GroupID = &groupID
+	}
+
+	secret, err := utils.RandomToken(24)
+	if err != nil {
+		return nil, err
+	}
+	subscription.Secret = "whsec_" + secret
+
+	if err := s.repo.Create(ctx, &subscription); err != nil {
+		return nil, err
+	}
+
+	return &response.CreatedWebhookResponse{
+		WebhookResponse: toWebhookResponse(subscription),
+		Secret:          subscription.Secret,
+	}, nil
+}
+
+func (s *webhookService) GetMine(ctx context.Context, userID uuid.UUID) ([]response.WebhookResponse, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetMine")
+	defer span.End()
+
+	subscriptions, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []response.WebhookResponse{}
+	for _, sub := range subscriptions {
+		res = append(res, toWebhookResponse(sub))
+	}
+	return res, nil
+}
+
+func (s *webhookService) Delete(ctx context.Context, userID, webhookID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.Delete")
+	defer span.End()
+
+	deleted, err := s.repo.Delete(ctx, webhookID, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, userID, webhookID uuid.UUID) ([]response.WebhookDeliveryResponse, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetDeliveries")
+	defer span.End()
+
+	subscription, err := s.repo.FindByIDAndUserID(ctx, webhookID, userID)
+	if err != nil {
+		return nil, apperror.Replace(err, ErrWebhookNotFound)
+	}
+
+	deliveries, err := s.repo.FindDeliveries(ctx, subscription.ID, webhookDeliveryLogLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []response.WebhookDeliveryResponse{}
+	for _, d := range deliveries {
+		res = append(res, toWebhookDeliveryResponse(d))
+	}
+	return res, nil
+}
+
+func (s *webhookService) Test(ctx context.Context, userID, webhookID uuid.UUID) (*response.WebhookDeliveryResponse, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Test")
+	defer span.End()
+
+	subscription, err := s.repo.FindByIDAndUserID(ctx, webhookID, userID)
+	if err != nil {
+		return nil, apperror.Replace(err, ErrWebhookNotFound)
+	}
+
+	delivery, err := s.dispatcher.Ping(ctx, *subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	res := toWebhookDeliveryResponse(*delivery)
+	return &res, nil
+}
+
+func toWebhookResponse(sub models.WebhookSubscription) response.WebhookResponse {
+	res := response.WebhookResponse{
+		ID:        sub.ID.String(),
+		URL:       sub.URL,
+		Events:    sub.EventList(),
+		Active:    sub.Active,
+		CreatedAt: sub.CreatedAt,
+	}
+	if sub.GroupID != nil {
+		res.GroupID = sub.GroupID.String()
+	}
+	return res
+}
+
+func toWebhookDeliveryResponse(d models.WebhookDelivery) response.WebhookDeliveryResponse {
+	return response.WebhookDeliveryResponse{
+		ID:         d.ID.String(),
+		EventID:    d.EventID.String(),
+		EventType:  d.EventType,
+		Attempt:    d.Attempt,
+		StatusCode: d.StatusCode,
+		Success:    d.Success,
+		Error:      d.Error,
+		DurationMs: d.DurationMs,
+		CreatedAt:  d.CreatedAt,
+	}
+}
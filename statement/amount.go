@@ -0,0 +1,98 @@
+package statement
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// parseAmount baca nominal dari OFX/QIF yang format angkanya ikut locale bank:
+// "1,234.56", "1.234,56", "1.500.000,00", "150.000", "-150,000", "1234,5", "1234.56".
+//
+// Titik/koma terakhir dianggap pemisah desimal cuma kalau di belakangnya ada 1-2 digit, sisanya
+// pemisah ribuan (harus satu jenis & per 3 digit). Jadi "150.000" = 150000, bukan 150.
+// Yang ambigu/aneh ("1.234.56", "12,34,567") ditolak daripada salah baca nominal.
+func parseAmount(value string) (float64, error) {
+	raw := value
+	value = strings.ReplaceAll(strings.TrimSpace(value), " ", "")
+
+	sign := ""
+	if strings.HasPrefix(value, "-") || strings.HasPrefix(value, "+") {
+		sign, value = value[:1], value[1:]
+	}
+
+	intPart, fracPart, decimalSep := value, "", byte(0)
+	if i := strings.LastIndexAny(value, ".,"); i >= 0 {
+		if n := len(value) - i - 1; n >= 1 && n <= 2 {
+			intPart, fracPart, decimalSep = value[:i], value[i+1:], value[i]
+		}
+	}
+
+	digits, ok := stripGrouping(intPart, decimalSep)
+	if !ok || (decimalSep != 0 && !isDigits(fracPart)) {
+		return 0, errors.New("nominal tidak valid: " + raw)
+	}
+	number := sign + digits
+	if fracPart != "" {
+		number += "." + fracPart
+	}
+	amount, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, errors.New("nominal tidak valid: " + raw)
+	}
+	return amount, nil
+}
+
+// parseDecimal buat format yang angkanya udah pasti (XML decimal): titik desimal, tanpa pemisah ribuan.
+func parseDecimal(value string) (float64, error) {
+	amount, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, errors.New("nominal tidak valid: " + value)
+	}
+	return amount, nil
+}
+
+// stripGrouping buang pemisah ribuan dari bagian bulat. Pemisahnya harus satu jenis, beda dari
+// pemisah desimal, dan tiap grup setelah yang pertama pas 3 digit.
+func stripGrouping(value string, decimalSep byte) (string, bool) {
+	if value == "" {
+		return "0", decimalSep != 0 // ",50" / ".5" boleh, string kosong doang gak
+	}
+	hasDot, hasComma := strings.Contains(value, "."), strings.Contains(value, ",")
+	if !hasDot && !hasComma {
+		return value, isDigits(value)
+	}
+	if hasDot && hasComma {
+		return "", false
+	}
+	sep := ","
+	if hasDot {
+		sep = "."
+	}
+	if sep[0] == decimalSep {
+		return "", false
+	}
+
+	groups := strings.Split(value, sep)
+	if len(groups[0]) < 1 || len(groups[0]) > 3 || !isDigits(groups[0]) {
+		return "", false
+	}
+	for _, group := range groups[1:] {
+		if len(group) != 3 || !isDigits(group) {
+			return "", false
+		}
+	}
+	return strings.Join(groups, ""), true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Struktur minimal CAMT.053 (ISO 20022). Namespace diabaikan, yang dicocokin nama tag-nya aja.
+type camtDocument struct {
+	Statements []struct {
+		Entries []camtEntry `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtEntry struct {
+	Amount      string       `xml:"Amt"`
+	CreditDebit string       `xml:"CdtDbtInd"`
+	BookingDate camtDate     `xml:"BookgDt"`
+	ValueDate   camtDate     `xml:"ValDt"`
+	Reference   string       `xml:"AcctSvcrRef"`
+	Info        string       `xml:"AddtlNtryInf"`
+	Details     []camtTxDtls `xml:"NtryDtls>TxDtls"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+type camtTxDtls struct {
+	EndToEndID string   `xml:"Refs>EndToEndId"`
+	Creditor   string   `xml:"RltdPties>Cdtr>Nm"`
+	Debtor     string   `xml:"RltdPties>Dbtr>Nm"`
+	Remittance []string `xml:"RmtInf>Ustrd"`
+}
+
+func ParseCAMT(data []byte) ([]Row, error) {
+	var doc camtDocument
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for _, stmt := range doc.Statements {
+		for _, entry := range stmt.Entries {
+			date, err := entry.date()
+			if err != nil {
+				return nil, err
+			}
+			amount, err := parseDecimal(entry.Amount) // CAMT = XML, desimal selalu titik & tanpa pemisah ribuan
+			if err != nil {
+				return nil, err
+			}
+			if entry.CreditDebit == "DBIT" {
+				amount = -amount
+			}
+
+			row := Row{
+				Date:       date,
+				Amount:     amount,
+				Memo:       strings.TrimSpace(entry.Info),
+				ExternalID: strings.TrimSpace(entry.Reference),
+			}
+			if len(entry.Details) > 0 {
+				tx := entry.Details[0]
+				// Uang keluar -> payee = penerima (Cdtr), uang masuk -> pengirim (Dbtr)
+				row.Payee = tx.Debtor
+				if entry.CreditDebit == "DBIT" {
+					row.Payee = tx.Creditor
+				}
+				if memo := strings.TrimSpace(strings.Join(tx.Remittance, " ")); memo != "" {
+					row.Memo = memo
+				}
+				if row.ExternalID == "" && tx.EndToEndID != "NOTPROVIDED" {
+					row.ExternalID = strings.TrimSpace(tx.EndToEndID)
+				}
+			}
+			row.Payee = strings.TrimSpace(row.Payee)
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (e camtEntry) date() (time.Time, error) {
+	for _, d := range []camtDate{e.BookingDate, e.ValueDate} {
+		if value := strings.TrimSpace(d.Date); value != "" {
+			// Kadang bank ngirim "2024-01-25+07:00", ambil tanggalnya aja
+			return time.Parse("2006-01-02", value[:min(len(value), 10)])
+		}
+		if value := strings.TrimSpace(d.DateTime); value != "" {
+			if t, err := time.Parse(time.RFC3339, value); err == nil {
+				return t, nil
+			}
+			return time.Parse("2006-01-02T15:04:05", value[:min(len(value), 19)])
+		}
+	}
+	return time.Time{}, errors.New("entry CAMT tanpa tanggal")
+}
@@ -0,0 +1,72 @@
+package statement
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ofxTransactionBlock = regexp.MustCompile(`(?is)<STMTTRN>(.*?)(?:</STMTTRN>|<STMTTRN>|</BANKTRANLIST>)`)
+	ofxField            = regexp.MustCompile(`(?i)<([A-Z0-9.]+)>([^<\r\n]*)`)
+)
+
+// ParseOFX support OFX 1.x (SGML, tag gak ditutup) dan 2.x (XML).
+func ParseOFX(data []byte) ([]Row, error) {
+	content := string(data)
+	if !strings.Contains(strings.ToUpper(content), "<OFX>") {
+		return nil, errors.New("tag <OFX> tidak ditemukan")
+	}
+
+	var rows []Row
+	// Offset digeser ke akhir isi blok (bukan akhir match), soalnya di SGML blok berikutnya
+	// bisa langsung nyambung tanpa </STMTTRN> dan tag pembukanya kepake jadi penutup.
+	for offset := 0; ; {
+		loc := ofxTransactionBlock.FindStringSubmatchIndex(content[offset:])
+		if loc == nil {
+			break
+		}
+		block := content[offset+loc[2] : offset+loc[3]]
+		offset += loc[3]
+
+		fields := make(map[string]string)
+		for _, m := range ofxField.FindAllStringSubmatch(block, -1) {
+			fields[strings.ToUpper(m[1])] = strings.TrimSpace(m[2])
+		}
+
+		date, err := parseOFXDate(fields["DTPOSTED"])
+		if err != nil {
+			return nil, err
+		}
+		amount, err := parseAmount(fields["TRNAMT"])
+		if err != nil {
+			return nil, err
+		}
+
+		rows = append(rows, Row{
+			Date:       date,
+			Amount:     amount,
+			Payee:      fields["NAME"],
+			Memo:       fields["MEMO"],
+			ExternalID: fields["FITID"],
+		})
+	}
+	return rows, nil
+}
+
+// parseOFXDate: YYYYMMDD[HHMMSS[.XXX]][[+/-hh:TZ]], timezone diabaikan.
+func parseOFXDate(value string) (time.Time, error) {
+	if i := strings.IndexAny(value, ".["); i >= 0 {
+		value = value[:i]
+	}
+	switch len(value) {
+	case 8:
+		return time.Parse("20060102", value)
+	case 12:
+		return time.Parse("200601021504", value)
+	case 14:
+		return time.Parse("20060102150405", value)
+	}
+	return time.Time{}, errors.New("DTPOSTED tidak valid: " + value)
+}
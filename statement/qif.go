@@ -0,0 +1,138 @@
+package statement
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type qifRecord struct {
+	rawDate     string
+	amount      float64
+	payee       string
+	memo        string
+	checkNumber string
+}
+
+// ParseQIF baca record !Type:Bank / !Type:CCard. Tiap record diakhiri "^".
+// Urutan tanggal (MM/DD atau DD/MM) ditebak dari seluruh isi file.
+func ParseQIF(data []byte) ([]Row, error) {
+	var (
+		records []qifRecord
+		current qifRecord
+		hasData bool
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		code, value := line[0], strings.TrimSpace(line[1:])
+		switch code {
+		case '!':
+			continue
+		case 'D':
+			current.rawDate = value
+			hasData = true
+		case 'T', 'U':
+			amount, err := parseAmount(value)
+			if err != nil {
+				return nil, err
+			}
+			current.amount = amount
+			hasData = true
+		case 'P':
+			current.payee = value
+		case 'M':
+			current.memo = value
+		case 'N':
+			current.checkNumber = value
+		case '^':
+			if hasData {
+				records = append(records, current)
+			}
+			current, hasData = qifRecord{}, false
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if hasData {
+		records = append(records, current)
+	}
+
+	dayFirst := qifDayFirst(records)
+	rows := make([]Row, 0, len(records))
+	for _, rec := range records {
+		date, err := parseQIFDate(rec.rawDate, dayFirst)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, Row{
+			Date:       date,
+			Amount:     rec.amount,
+			Payee:      rec.payee,
+			Memo:       rec.memo,
+			ExternalID: rec.checkNumber,
+		})
+	}
+	return rows, nil
+}
+
+// qifDayFirst: kalau ada komponen pertama > 12 berarti formatnya DD/MM (umum di bank lokal).
+func qifDayFirst(records []qifRecord) bool {
+	for _, rec := range records {
+		parts := splitQIFDate(rec.rawDate)
+		if len(parts) == 3 && len(parts[0]) <= 2 {
+			if n, err := strconv.Atoi(parts[0]); err == nil && n > 12 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func splitQIFDate(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == '/' || r == '-' || r == '.' || r == '\'' || r == ' '
+	})
+}
+
+func parseQIFDate(value string, dayFirst bool) (time.Time, error) {
+	parts := splitQIFDate(value)
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("tanggal QIF tidak valid: " + value)
+	}
+
+	nums := make([]int, 3)
+	for i, p := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil {
+			return time.Time{}, errors.New("tanggal QIF tidak valid: " + value)
+		}
+		nums[i] = n
+	}
+
+	var year, month, day int
+	switch {
+	case len(parts[0]) == 4: // 2024-01-25
+		year, month, day = nums[0], nums[1], nums[2]
+	case dayFirst:
+		day, month, year = nums[0], nums[1], nums[2]
+	default:
+		month, day, year = nums[0], nums[1], nums[2]
+	}
+	if year < 100 {
+		year += 2000
+	}
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return time.Time{}, fmt.Errorf("tanggal QIF tidak valid: %s", value)
+	}
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC), nil
+}
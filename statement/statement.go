@@ -0,0 +1,101 @@
+// Package statement nge-parse file mutasi rekening (OFX, QIF, CAMT.053) jadi baris transaksi mentah.
+package statement
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	FormatOFX  = "ofx"
+	FormatQIF  = "qif"
+	FormatCAMT = "camt"
+)
+
+var Formats = []string{FormatOFX, FormatQIF, FormatCAMT}
+
+var ErrUnknownFormat = errors.New("format statement tidak dikenali, pilihan: ofx, qif, camt")
+
+// Row = satu baris mutasi. Amount bertanda: negatif = uang keluar, positif = uang masuk.
+type Row struct {
+	Date       time.Time
+	Amount     float64
+	Payee      string
+	Memo       string
+	ExternalID string // FITID (OFX), AcctSvcrRef / EndToEndId (CAMT), nomor cek (QIF)
+}
+
+// Parse baca isi file sesuai format. Kalau format kosong, ditebak dari nama file & isinya.
+func Parse(format, fileName string, data []byte) (string, []Row, error) {
+	if format == "" {
+		format = DetectFormat(fileName, data)
+	}
+
+	var (
+		rows []Row
+		err  error
+	)
+	switch format {
+	case FormatOFX:
+		rows, err = ParseOFX(data)
+	case FormatQIF:
+		rows, err = ParseQIF(data)
+	case FormatCAMT:
+		rows, err = ParseCAMT(data)
+	default:
+		return "", nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return format, nil, fmt.Errorf("gagal parse file %s: %w", format, err)
+	}
+	if len(rows) == 0 {
+		return format, nil, errors.New("tidak ada transaksi di file")
+	}
+	return format, rows, nil
+}
+
+func DetectFormat(fileName string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".ofx", ".qfx":
+		return FormatOFX
+	case ".qif":
+		return FormatQIF
+	}
+
+	head := strings.ToUpper(string(data[:min(len(data), 2048)]))
+	switch {
+	case strings.Contains(head, "OFXHEADER") || strings.Contains(head, "<OFX>"):
+		return FormatOFX
+	case strings.Contains(head, "!TYPE:") || strings.Contains(head, "!ACCOUNT"):
+		return FormatQIF
+	case strings.Contains(head, "CAMT.053") || strings.Contains(head, "BKTOCSTMRSTMT"):
+		return FormatCAMT
+	}
+	return ""
+}
+
+// Fingerprint buat deteksi duplikat: tanggal + nominal + payee yang dinormalisasi + id dari bank.
+func Fingerprint(row Row) string {
+	raw := fmt.Sprintf("%s|%.2f|%s|%s",
+		row.Date.Format("2006-01-02"), row.Amount, NormalizePayee(row.Payee), strings.TrimSpace(row.ExternalID))
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizePayee: huruf kecil, selain huruf/angka jadi spasi, spasi dobel dirapihin.
+// "PT. Indomaret  -  JKT" == "pt indomaret jkt"
+func NormalizePayee(payee string) string {
+	mapped := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, payee)
+	return strings.Join(strings.Fields(mapped), " ")
+}
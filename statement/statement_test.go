@@ -0,0 +1,164 @@
+package statement
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{in: "1234.56", want: 1234.56},
+		{in: "-1234.56", want: -1234.56},
+		{in: "+1234.5", want: 1234.5},
+		{in: "1234,56", want: 1234.56},
+		{in: "1,234.56", want: 1234.56},
+		{in: "1.234,56", want: 1234.56},
+		{in: "1.500.000,00", want: 1500000},
+		{in: "1,500,000.00", want: 1500000},
+		{in: "150.000", want: 150000},
+		{in: "-150,000", want: -150000},
+		{in: "-150.000", want: -150000},
+		{in: "1.500.000", want: 1500000},
+		{in: "1 500 000,50", want: 1500000.5},
+		{in: "150", want: 150},
+		{in: "0,5", want: 0.5},
+		{in: ".5", want: 0.5},
+		{in: " -25.00 ", want: -25},
+
+		{in: "", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "1.234.56", wantErr: true},  // titik dipakai buat ribuan & desimal sekaligus
+		{in: "12,34,567", wantErr: true}, // grup ribuan bukan per 3 digit
+		{in: "1.234,567", wantErr: true}, // dua jenis pemisah ribuan
+		{in: "1234.567", wantErr: true},  // grup pertama kepanjangan
+		{in: "1e5", wantErr: true},
+		{in: "-", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseAmount(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseAmount(%q) = %v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAmount(%q) error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseAmount(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		format  string
+		file    string
+		data    string
+		want    []Row
+		wantErr bool
+	}{
+		{
+			name:   "qif format indonesia",
+			format: FormatQIF,
+			data: "!Type:Bank\n" +
+				"D25/01/2024\nT-1.500.000,00\nPToko Makmur\nMBelanja bulanan\n^\n" +
+				"D26/01/2024\nT150.000\nPGaji\nN0042\n^\n",
+			want: []Row{
+				{Date: date(2024, 1, 25), Amount: -1500000, Payee: "Toko Makmur", Memo: "Belanja bulanan"},
+				{Date: date(2024, 1, 26), Amount: 150000, Payee: "Gaji", ExternalID: "0042"},
+			},
+		},
+		{
+			name:   "qif format us",
+			format: FormatQIF,
+			data:   "!Type:CCard\nD01/02/2024\nT-1,234.56\nPStore\n^\n",
+			want:   []Row{{Date: date(2024, 1, 2), Amount: -1234.56, Payee: "Store"}},
+		},
+		{
+			name:    "qif nominal ambigu ditolak",
+			format:  FormatQIF,
+			data:    "!Type:Bank\nD25/01/2024\nT1.234.56\n^\n",
+			wantErr: true,
+		},
+		{
+			name:   "ofx sgml",
+			format: FormatOFX,
+			data: "OFXHEADER:100\n<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>\n" +
+				"<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20240125<TRNAMT>-150.000<FITID>A1<NAME>Kopi\n" +
+				"<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20240126120000[+7:WIB]<TRNAMT>2.500.000,50<FITID>A2<NAME>Gaji<MEMO>Januari\n" +
+				"</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>",
+			want: []Row{
+				{Date: date(2024, 1, 25), Amount: -150000, Payee: "Kopi", ExternalID: "A1"},
+				{Date: time.Date(2024, 1, 26, 12, 0, 0, 0, time.UTC), Amount: 2500000.5, Payee: "Gaji", Memo: "Januari", ExternalID: "A2"},
+			},
+		},
+		{
+			name:   "ofx xml",
+			format: FormatOFX,
+			data: "<?xml version=\"1.0\"?><OFX><BANKTRANLIST>" +
+				"<STMTTRN><DTPOSTED>20240125</DTPOSTED><TRNAMT>-12.34</TRNAMT><FITID>X</FITID><NAME>Parkir</NAME></STMTTRN>" +
+				"</BANKTRANLIST></OFX>",
+			want: []Row{{Date: date(2024, 1, 25), Amount: -12.34, Payee: "Parkir", ExternalID: "X"}},
+		},
+		{
+			name:   "camt",
+			format: FormatCAMT,
+			data: `<Document><BkToCstmrStmt><Stmt>
+<Ntry><Amt Ccy="IDR">150000.00</Amt><CdtDbtInd>DBIT</CdtDbtInd><BookgDt><Dt>2024-01-25</Dt></BookgDt><AcctSvcrRef>R1</AcctSvcrRef>
+<NtryDtls><TxDtls><RltdPties><Cdtr><Nm>Toko</Nm></Cdtr></RltdPties><RmtInf><Ustrd>Belanja</Ustrd></RmtInf></TxDtls></NtryDtls></Ntry>
+<Ntry><Amt Ccy="IDR">1500.5</Amt><CdtDbtInd>CRDT</CdtDbtInd><BookgDt><Dt>2024-01-26</Dt></BookgDt><AcctSvcrRef>R2</AcctSvcrRef></Ntry>
+</Stmt></BkToCstmrStmt></Document>`,
+			want: []Row{
+				{Date: date(2024, 1, 25), Amount: -150000, Payee: "Toko", Memo: "Belanja", ExternalID: "R1"},
+				{Date: date(2024, 1, 26), Amount: 1500.5, ExternalID: "R2"},
+			},
+		},
+		{
+			name:    "camt nominal pakai pemisah ribuan ditolak",
+			format:  FormatCAMT,
+			data:    `<Document><BkToCstmrStmt><Stmt><Ntry><Amt>1.500,00</Amt><CdtDbtInd>DBIT</CdtDbtInd><BookgDt><Dt>2024-01-25</Dt></BookgDt></Ntry></Stmt></BkToCstmrStmt></Document>`,
+			wantErr: true,
+		},
+		{
+			name: "format ditebak dari nama file",
+			file: "mutasi.qif",
+			data: "!Type:Bank\nD2024-01-25\nT-50.000\n^\n",
+			want: []Row{{Date: date(2024, 1, 25), Amount: -50000}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, rows, err := Parse(tt.format, tt.file, []byte(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("want error, got %+v", rows)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d: %+v", len(rows), len(tt.want), rows)
+			}
+			for i := range rows {
+				if !rows[i].Date.Equal(tt.want[i].Date) || rows[i].Amount != tt.want[i].Amount ||
+					rows[i].Payee != tt.want[i].Payee || rows[i].Memo != tt.want[i].Memo ||
+					rows[i].ExternalID != tt.want[i].ExternalID {
+					t.Errorf("row %d = %+v, want %+v", i, rows[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package tracing
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanKey = "tracing:span"
+
+// Prefix package repository, dipakai buat nyari method repo mana yang jalanin query.
+const repositoryPkg = "cashflow_gin/repository."
+
+// GormPlugin bikin satu span per query, child dari span yang ada di ctx (db.WithContext).
+// Nama span = method repository pemanggil (misal groupRepository.IsGroupMember), jadi di trace
+// kelihatan query mana yang lambat. SQL yang dicatat masih pakai placeholder, nilainya gak ikut.
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string { return "tracing" }
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tracing:before_create", startSpan("INSERT")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", endSpan),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", startSpan("SELECT")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", endSpan),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", startSpan("UPDATE")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", endSpan),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", startSpan("DELETE")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", endSpan),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", startSpan("ROW")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", endSpan),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", startSpan("RAW")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", endSpan),
+	)
+}
+
+func startSpan(operation string) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		ctx := tx.Statement.Context
+		if ctx == nil || !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			return // query di luar request/span (migration, janitor), gak usah bikin root span baru
+		}
+
+		name := repositoryCaller()
+		if name == "" {
+			name = strings.TrimSpace(operation + " " + tx.Statement.Table)
+		}
+		_, span := Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				dbSystem(tx.Dialector.Name()),
+				semconv.DBOperationName(operation),
+			),
+		)
+		tx.InstanceSet(spanKey, span)
+	}
+}
+
+func dbSystem(dialect string) attribute.KeyValue {
+	if dialect == "sqlite" {
+		return semconv.DBSystemNameSQLite
+	}
+	return semconv.DBSystemNamePostgreSQL
+}
+
+func endSpan(tx *gorm.DB) {
+	v, ok := tx.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+
+	if tx.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(tx.Statement.Table))
+	}
+	span.SetAttributes(
+		semconv.DBQueryText(tx.Statement.SQL.String()),
+		semconv.DBResponseReturnedRows(int(tx.Statement.RowsAffected)),
+	)
+	if !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		RecordError(span, tx.Error)
+	}
+	span.End()
+}
+
+// repositoryCaller cari frame pertama dari package repository di call stack,
+// hasilnya "transactionRepository.CreateWithWalletUpdate".
+func repositoryCaller() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(4, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if name, ok := strings.CutPrefix(frame.Function, repositoryPkg); ok {
+			// "(*transactionRepository).CreateWithWalletUpdate.func1" -> "transactionRepository.CreateWithWalletUpdate"
+			name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+			if i := strings.Index(name, ".func"); i > 0 {
+				name = name[:i]
+			}
+			return name
+		}
+		if !more {
+			return ""
+		}
+	}
+}
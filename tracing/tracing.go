@@ -0,0 +1,105 @@
+// Package tracing = setup OpenTelemetry: tracer provider + exporter (otlp | stdout | none),
+// helper bikin span di service, dan plugin GORM biar tiap query jadi child span request-nya.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "cashflow_gin"
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Options diisi dari config.TracingConfig.
+type Options struct {
+	Exporter     string  // none | stdout | otlp
+	OTLPEndpoint string  // URL collector OTLP/HTTP, misal http://localhost:4318. Kosong = default SDK
+	SampleRatio  float64 // 0..1, dipakai kalau request masuk belum bawa traceparent
+	ServiceName  string
+	Environment  string
+}
+
+// Setup pasang tracer provider global. Return func shutdown buat flush span yang masih di buffer.
+// Exporter "none" = gak ada span yang direkam, tapi traceparent dari client tetap diterusin.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(opts.Exporter) {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	case ExporterOTLP:
+		var exporterOpts []otlptracehttp.Option
+		if opts.OTLPEndpoint != "" {
+			exporterOpts = append(exporterOpts, otlptracehttp.WithEndpointURL(opts.OTLPEndpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, exporterOpts...)
+	default:
+		return nil, fmt.Errorf("tracing exporter %q gak dikenal (none|stdout|otlp)", opts.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal bikin exporter tracing: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+		semconv.DeploymentEnvironmentName(opts.Environment),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Enabled = ada exporter yang beneran dipasang (dipakai buat skip kerja ekstra kayak plugin GORM).
+func Enabled(exporter string) bool {
+	e := strings.ToLower(exporter)
+	return e != "" && e != ExporterNone
+}
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Start bikin child span dari span yang ada di ctx. Pemakaian di service:
+//
+//	ctx, span := tracing.Start(ctx, "TransactionService.Create")
+//	defer span.End()
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// RecordError tandain span gagal. err nil = no-op, biar bisa dipanggil langsung sebelum return.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// RandomToken bikin string random (base32 lowercase) dengan panjang n byte entropy.
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return strings.ToLower(base32NoPadding.EncodeToString(b)), nil
+}
+
+// HashToken = SHA-256 hex. Cukup buat token random ber-entropy tinggi (recovery code, API key),
+// gak perlu bcrypt karena gak bisa di-bruteforce kayak password.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
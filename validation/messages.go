@@ -0,0 +1,208 @@
+package validation
+
+import (
+	"cashflow_gin/dto/response"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	LangID = "id"
+	LangEN = "en"
+
+	DefaultLang = LangID
+)
+
+// Pesan per bahasa. Key = tag validator, atau "tag.string" / "tag.slice" kalau kalimatnya beda
+// buat teks (karakter) & list (item). {field} & {param} diisi pas translate.
+var messages = map[string]map[string]string{
+	LangID: {
+		"validation_failed": "Validasi gagal",
+		"invalid_input":     "Input tidak valid",
+
+		"required":      "{field} wajib diisi",
+		"min.string":    "{field} minimal {param} karakter",
+		"min.slice":     "{field} minimal berisi {param} item",
+		"min":           "{field} minimal {param}",
+		"max.string":    "{field} maksimal {param} karakter",
+		"max.slice":     "{field} maksimal berisi {param} item",
+		"max":           "{field} maksimal {param}",
+		"len.string":    "{field} harus {param} karakter",
+		"len.slice":     "{field} harus berisi {param} item",
+		"len":           "{field} harus {param}",
+		"gt":            "{field} harus lebih dari {param}",
+		"gte":           "{field} minimal {param}",
+		"lt":            "{field} harus kurang dari {param}",
+		"lte":           "{field} maksimal {param}",
+		"uuid":          "{field} harus UUID yang valid",
+		"email":         "{field} harus alamat email yang valid",
+		"url":           "{field} harus URL yang valid",
+		"oneof":         "{field} harus salah satu dari: {param}",
+		"datetime":      "{field} harus berformat {param}",
+		"currency":      "{field} harus kode mata uang ISO 4217 (misal IDR, USD)",
+		"category_type": "{field} harus INCOME atau EXPENSE",
+		"money":         "{field} maksimal 2 angka di belakang koma",
+		"type":          "{field} harus bertipe {param}",
+		"default":       "{field} tidak valid ({rule})",
+
+		"kind.number":  "angka",
+		"kind.string":  "teks",
+		"kind.boolean": "boolean",
+		"kind.array":   "array",
+		"kind.object":  "object",
+	},
+	LangEN: {
+		"validation_failed": "Validation failed",
+		"invalid_input":     "Invalid input data",
+
+		"required":      "{field} is required",
+		"min.string":    "{field} must be at least {param} characters",
+		"min.slice":     "{field} must contain at least {param} items",
+		"min":           "{field} must be at least {param}",
+		"max.string":    "{field} must be at most {param} characters",
+		"max.slice":     "{field} must contain at most {param} items",
+		"max":           "{field} must be at most {param}",
+		"len.string":    "{field} must be exactly {param} characters",
+		"len.slice":     "{field} must contain exactly {param} items",
+		"len":           "{field} must be {param}",
+		"gt":            "{field} must be greater than {param}",
+		"gte":           "{field} must be at least {param}",
+		"lt":            "{field} must be less than {param}",
+		"lte":           "{field} must be at most {param}",
+		"uuid":          "{field} must be a valid UUID",
+		"email":         "{field} must be a valid email address",
+		"url":           "{field} must be a valid URL",
+		"oneof":         "{field} must be one of: {param}",
+		"datetime":      "{field} must use the format {param}",
+		"currency":      "{field} must be an ISO 4217 currency code (e.g. IDR, USD)",
+		"category_type": "{field} must be INCOME or EXPENSE",
+		"money":         "{field} must have at most 2 decimal places",
+		"type":          "{field} must be a {param}",
+		"default":       "{field} is invalid ({rule})",
+
+		"kind.number":  "number",
+		"kind.string":  "string",
+		"kind.boolean": "boolean",
+		"kind.array":   "array",
+		"kind.object":  "object",
+	},
+}
+
+// Language pilih bahasa dari header Accept-Language ("en-US,en;q=0.9,id;q=0.8").
+// Yang q-nya paling tinggi & didukung menang, gak ada yang cocok = DefaultLang.
+func Language(acceptLanguage string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if _, ok := messages[primary]; !ok {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{primary, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return DefaultLang
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+// Message ambil pesan umum (validation_failed, invalid_input) sesuai bahasa.
+func Message(lang, key string) string {
+	if msg, ok := messages[lang][key]; ok {
+		return msg
+	}
+	return messages[DefaultLang][key]
+}
+
+// Translate ubah error dari ShouldBind jadi daftar field yang gak valid.
+// Return nil kalau err bukan error validasi / tipe JSON (misal body bukan JSON sama sekali).
+func Translate(err error, lang string) []response.FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]response.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			field := namespace(fe)
+			fields = append(fields, response.FieldError{
+				Field:   field,
+				Rule:    fe.Tag(),
+				Message: render(lang, messageKey(fe), field, fe.Tag(), fe.Param()),
+			})
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []response.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: render(lang, "type", typeErr.Field, "type", Message(lang, kindKey(typeErr.Type))),
+		}}
+	}
+	return nil
+}
+
+// namespace: "CreateTransactionRequest.items[0].amount" -> "items[0].amount".
+func namespace(fe validator.FieldError) string {
+	if _, rest, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return rest
+	}
+	return fe.Field()
+}
+
+func messageKey(fe validator.FieldError) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return fe.Tag() + ".string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fe.Tag() + ".slice"
+	}
+	return fe.Tag()
+}
+
+// kindKey: tipe Go tujuan unmarshal -> nama tipe JSON yang dimengerti client.
+func kindKey(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "kind.number"
+	case reflect.String:
+		return "kind.string"
+	case reflect.Bool:
+		return "kind.boolean"
+	case reflect.Slice, reflect.Array:
+		return "kind.array"
+	}
+	return "kind.object"
+}
+
+func render(lang, key, field, rule, param string) string {
+	msg, ok := messages[lang][key]
+	if !ok {
+		base, _, _ := strings.Cut(key, ".")
+		if msg, ok = messages[lang][base]; !ok {
+			msg = messages[lang]["default"]
+		}
+	}
+	return strings.NewReplacer("{field}", field, "{param}", param, "{rule}", rule).Replace(msg)
+}
@@ -0,0 +1,91 @@
+// Package validation = setup validator bawaan gin (go-playground/validator): nama field ikut tag json/form,
+// validator custom (currency, category_type, money), dan terjemahan error validasi jadi daftar
+// {field, rule, message} dalam bahasa Indonesia / Inggris sesuai header Accept-Language.
+package validation
+
+import (
+	"cashflow_gin/models"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Register dipanggil sekali pas startup, sebelum router nerima request.
+func Register() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("validator gin bukan go-playground/validator")
+	}
+
+	v.RegisterTagNameFunc(fieldName)
+	return errors.Join(
+		v.RegisterValidation("currency", isCurrency),
+		v.RegisterValidation("category_type", isCategoryType),
+		v.RegisterValidation("money", isMoney),
+	)
+}
+
+// fieldName: error validasi pakai nama di JSON (atau query/form), bukan nama field Go.
+func fieldName(f reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// isCurrency: kode mata uang ISO 4217, huruf besar (IDR, USD, ...).
+func isCurrency(fl validator.FieldLevel) bool {
+	_, ok := currencies[fl.Field().String()]
+	return ok
+}
+
+func isCategoryType(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case models.CategoryTypeIncome, models.CategoryTypeExpense:
+		return true
+	}
+	return false
+}
+
+// isMoney: maksimal 2 angka di belakang koma, sesuai kolom decimal(16,2). Lebih dari itu
+// bakal dibulatin diam-diam sama postgres, jadi mending ditolak di depan.
+func isMoney(fl validator.FieldLevel) bool {
+	var s string
+	switch fl.Field().Kind() {
+	case reflect.Float32, reflect.Float64:
+		s = strconv.FormatFloat(fl.Field().Float(), 'f', -1, 64)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+	_, decimals, _ := strings.Cut(s, ".")
+	return len(decimals) <= 2
+}
+
+// Kode mata uang ISO 4217 yang masih berlaku.
+var currencies = toSet(`AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND BOB BRL
+BSD BTN BWP BYN BZD CAD CDF CHF CLP CNY COP CRC CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP
+GEL GHS GIP GMD GNF GTQ GYD HKD HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY KES KGS KHR KMF KPW KRW
+KWD KYD KZT LAK LBP LKR LRD LSL LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MYR MZN NAD NGN NIO
+NOK NPR NZD OMR PAB PEN PGK PHP PKR PLN PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD
+SSP STN SVC SYP SZL THB TJS TMT TND TOP TRY TTD TWD TZS UAH UGX USD UYU UZS VES VND VUV WST XAF XCD XOF
+XPF YER ZAR ZMW ZWG`)
+
+func toSet(list string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, code := range strings.Fields(list) {
+		set[code] = struct{}{}
+	}
+	return set
+}